@@ -10,9 +10,10 @@ import (
 var clients = map[string]map[string]AuthInterface{}
 
 type AuthInterface interface {
-	GetAuthCodeURL(state string) string
+	// GetAuthCodeURL returns the url of authorization with the code challenge of verifier.
+	GetAuthCodeURL(state, verifier string) string
 	WebRedirectDir() string
-	Auth(code, scope string) (string, string, error)
+	Auth(code, scope, verifier string) (string, string, error)
 }
 
 func RegisterPlatform(credentialFile string) error {
@@ -15,16 +15,16 @@ type client struct {
 	platform string
 }
 
-func (this *client) GetAuthCodeURL(state string) string {
-	return this.c.GetOauth2CodeURL(state)
+func (this *client) GetAuthCodeURL(state, verifier string) string {
+	return this.c.GetOauth2CodeURL(state, verifier)
 }
 
 func (this *client) WebRedirectDir() string {
 	return this.webRedirectDir
 }
 
-func (this *client) Auth(code, scope string) (string, string, error) {
-	token, err := this.c.GetToken(code, scope)
+func (this *client) Auth(code, scope, verifier string) (string, string, error) {
+	token, err := this.c.GetToken(code, scope, verifier)
 	if err != nil {
 		return "", "", fmt.Errorf("Get token failed: %s", err.Error())
 	}
@@ -0,0 +1,77 @@
+package platforms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// GithubAPI is the client of GitHub REST API. It is shared by the
+// authentication of code platform and the robot.
+type GithubAPI struct {
+	endpoint string
+	c        *http.Client
+}
+
+// NewGithubAPI returns the client which accesses the endpoint by the token.
+// The endpoint is the one of github.com if it is empty.
+func NewGithubAPI(accessToken, endpoint string) *GithubAPI {
+	if endpoint == "" {
+		endpoint = githubAPIEndpoint
+	}
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
+
+	return &GithubAPI{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		c:        oauth2.NewClient(context.Background(), ts),
+	}
+}
+
+// Do sends the body in json and decodes the response into result if it is not nil.
+func (this *GithubAPI) Do(method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, this.endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := this.c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("request github api(%s %s) failed, status code: %d, body: %s", method, path, resp.StatusCode, string(data))
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,78 @@
+package platforms
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	githubAPIEndpoint = "https://api.github.com"
+	githubPerPage     = 100
+	githubRoleAdmin   = "admin"
+)
+
+type githubClient struct {
+	accessToken  string
+	refreshToken string
+	api          *GithubAPI
+}
+
+func newGithubClient(accessToken, refreshToken string) *githubClient {
+	return newGithubClientWithEndpoint(accessToken, refreshToken, githubAPIEndpoint)
+}
+
+// newGithubClientWithEndpoint allows to access the api server of GitHub
+// Enterprise or a local server which imitates the GitHub REST API.
+func newGithubClientWithEndpoint(accessToken, refreshToken, endpoint string) *githubClient {
+	return &githubClient{
+		accessToken:  accessToken,
+		refreshToken: refreshToken,
+		api:          NewGithubAPI(accessToken, endpoint),
+	}
+}
+
+func (this *githubClient) GetUser() (string, error) {
+	var u struct {
+		Login string `json:"login"`
+	}
+
+	if err := this.get("/user", &u); err != nil {
+		return "", err
+	}
+	return u.Login, nil
+}
+
+func (this *githubClient) ListOrg() ([]string, error) {
+	var r []string
+
+	for p := 1; ; p++ {
+		var ls []struct {
+			State        string `json:"state"`
+			Role         string `json:"role"`
+			Organization struct {
+				Login string `json:"login"`
+			} `json:"organization"`
+		}
+
+		path := fmt.Sprintf("/user/memberships/orgs?state=active&page=%d&per_page=%d", p, githubPerPage)
+		if err := this.get(path, &ls); err != nil {
+			return nil, err
+		}
+
+		for _, v := range ls {
+			if v.Role == githubRoleAdmin {
+				r = append(r, v.Organization.Login)
+			}
+		}
+
+		if len(ls) < githubPerPage {
+			break
+		}
+	}
+
+	return r, nil
+}
+
+func (this *githubClient) get(path string, result interface{}) error {
+	return this.api.Do(http.MethodGet, path, nil, result)
+}
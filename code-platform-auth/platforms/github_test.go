@@ -0,0 +1,121 @@
+package platforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+const testGithubToken = "token-for-test"
+
+type githubMembership struct {
+	State        string `json:"state"`
+	Role         string `json:"role"`
+	Organization struct {
+		Login string `json:"login"`
+	} `json:"organization"`
+}
+
+// newGithubServer imitates the api server of GitHub. The user is the admin
+// of the odd orgs among the total ones.
+func newGithubServer(t *testing.T, total int) *httptest.Server {
+	memberships := make([]githubMembership, 0, total)
+	for i := 0; i < total; i++ {
+		m := githubMembership{State: "active", Role: "member"}
+		if i%2 == 1 {
+			m.Role = githubRoleAdmin
+		}
+		m.Organization.Login = fmt.Sprintf("org%d", i)
+		memberships = append(memberships, m)
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"login": "octocat"})
+	})
+
+	mux.HandleFunc("/user/memberships/orgs", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != "active" {
+			t.Errorf("unexpected state: %s", q.Get("state"))
+		}
+
+		page, _ := strconv.Atoi(q.Get("page"))
+		perPage, _ := strconv.Atoi(q.Get("per_page"))
+		if page < 1 || perPage < 1 {
+			http.Error(w, "invalid page", http.StatusBadRequest)
+			return
+		}
+
+		start := (page - 1) * perPage
+		if start > len(memberships) {
+			start = len(memberships)
+		}
+		end := start + perPage
+		if end > len(memberships) {
+			end = len(memberships)
+		}
+		json.NewEncoder(w).Encode(memberships[start:end])
+	})
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testGithubToken {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestGithubGetUser(t *testing.T) {
+	s := newGithubServer(t, 0)
+	defer s.Close()
+
+	user, err := newGithubClientWithEndpoint(testGithubToken, "", s.URL+"/").GetUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != "octocat" {
+		t.Errorf("expect user octocat, but got %s", user)
+	}
+
+	if _, err := newGithubClientWithEndpoint("invalid", "", s.URL).GetUser(); err == nil {
+		t.Error("expect error for invalid token")
+	}
+}
+
+func TestGithubListOrg(t *testing.T) {
+	cases := []struct {
+		name  string
+		total int
+	}{
+		{"no org", 0},
+		{"one page", githubPerPage - 1},
+		{"full page", githubPerPage},
+		{"multiple pages", githubPerPage*2 + 3},
+	}
+
+	for _, c := range cases {
+		s := newGithubServer(t, c.total)
+
+		orgs, err := newGithubClientWithEndpoint(testGithubToken, "", s.URL).ListOrg()
+		s.Close()
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+
+		var want []string
+		for i := 1; i < c.total; i += 2 {
+			want = append(want, fmt.Sprintf("org%d", i))
+		}
+		if !reflect.DeepEqual(orgs, want) {
+			t.Errorf("%s: expect %d orgs of admin, but got %d: %v", c.name, len(want), len(orgs), orgs)
+		}
+	}
+}
@@ -8,20 +8,36 @@ import (
 	"github.com/opensourceways/app-cla-server/util"
 )
 
+const (
+	DBDriverMongodb    = "mongodb"
+	DBDriverPostgresql = "postgresql"
+)
+
 var AppConfig *appConfig
 
 type appConfig struct {
-	PythonBin               string `json:"python_bin"`
-	MongodbConn             string `json:"mongodb_conn"`
-	DBName                  string `json:"mongodb_db"`
-	VerificationCodeExpiry  int64  `json:"verification_code_expiry"`
-	APITokenExpiry          int64  `json:"api_token_expiry"`
-	APITokenKey             string `json:"api_token_key"`
-	PDFOrgSignatureDir      string `json:"pdf_org_signature_dir"`
-	PDFOutDir               string `json:"pdf_out_dir"`
-	CodePlatformConfigFile  string `json:"code_platforms"`
-	EmailPlatformConfigFile string `json:"email_platforms"`
-	EmployeeManagersNumber  int    `json:"employee_managers_number"`
+	DBDriver                   string `json:"db_driver"`
+	MongodbConn                string `json:"mongodb_conn"`
+	DBName                     string `json:"mongodb_db"`
+	PostgresqlConn             string `json:"postgresql_conn"`
+	VerificationCodeExpiry     int64  `json:"verification_code_expiry"`
+	APITokenExpiry             int64  `json:"api_token_expiry"`
+	APITokenKey                string `json:"api_token_key"`
+	APIRefreshTokenExpiry      int64  `json:"api_refresh_token_expiry"`
+	SessionEncryptionKey       string `json:"session_encryption_key"`
+	OAuthStateExpiry           int64  `json:"oauth_state_expiry"`
+	MasterKeyConfigFile        string `json:"master_keys"`
+	OrgOwnerSessionLifetime    int64  `json:"org_owner_session_lifetime"`
+	CorpManagerSessionLifetime int64  `json:"corp_manager_session_lifetime"`
+	PDFOrgSignatureDir         string `json:"pdf_org_signature_dir"`
+	PDFOutDir                  string `json:"pdf_out_dir"`
+	PDFFontConfigFile          string `json:"pdf_font_config"`
+	CodePlatformConfigFile     string `json:"code_platforms"`
+	EmailPlatformConfigFile    string `json:"email_platforms"`
+	RobotConfigFile            string `json:"robot_config"`
+	EmployeeManagersNumber     int    `json:"employee_managers_number"`
+	EmailWorkerNumber          int    `json:"email_worker_number"`
+	EmailMaxAttempts           int    `json:"email_max_attempts"`
 }
 
 func InitAppConfig() error {
@@ -30,6 +46,26 @@ func InitAppConfig() error {
 		return err
 	}
 
+	refreshTokenExpiry, err := beego.AppConfig.Int64("api_refresh_token_expiry")
+	if err != nil {
+		return err
+	}
+
+	ownerSession, err := beego.AppConfig.Int64("org_owner_session_lifetime")
+	if err != nil {
+		return err
+	}
+
+	corpManagerSession, err := beego.AppConfig.Int64("corp_manager_session_lifetime")
+	if err != nil {
+		return err
+	}
+
+	stateExpiry, err := beego.AppConfig.Int64("oauth_state_expiry")
+	if err != nil {
+		return err
+	}
+
 	codeExpiry, err := beego.AppConfig.Int64("verification_code_expiry")
 	if err != nil {
 		return err
@@ -40,25 +76,52 @@ func InitAppConfig() error {
 		return err
 	}
 
+	emailWorkers, err := beego.AppConfig.Int("email_worker_number")
+	if err != nil {
+		return err
+	}
+
+	emailAttempts, err := beego.AppConfig.Int("email_max_attempts")
+	if err != nil {
+		return err
+	}
+
 	AppConfig = &appConfig{
-		PythonBin:               beego.AppConfig.String("python_bin"),
-		MongodbConn:             beego.AppConfig.String("mongodb_conn"),
-		DBName:                  beego.AppConfig.String("mongodb_db"),
-		VerificationCodeExpiry:  codeExpiry,
-		APITokenExpiry:          tokenExpiry,
-		APITokenKey:             beego.AppConfig.String("api_token_key"),
-		PDFOrgSignatureDir:      beego.AppConfig.String("pdf_org_signature_dir"),
-		PDFOutDir:               beego.AppConfig.String("pdf_out_dir"),
-		CodePlatformConfigFile:  beego.AppConfig.String("code_platforms"),
-		EmailPlatformConfigFile: beego.AppConfig.String("email_platforms"),
-		EmployeeManagersNumber:  employeeMangers,
+		DBDriver:                   beego.AppConfig.DefaultString("db_driver", DBDriverMongodb),
+		MongodbConn:                beego.AppConfig.String("mongodb_conn"),
+		DBName:                     beego.AppConfig.String("mongodb_db"),
+		PostgresqlConn:             beego.AppConfig.String("postgresql_conn"),
+		VerificationCodeExpiry:     codeExpiry,
+		APITokenExpiry:             tokenExpiry,
+		APITokenKey:                beego.AppConfig.String("api_token_key"),
+		APIRefreshTokenExpiry:      refreshTokenExpiry,
+		SessionEncryptionKey:       beego.AppConfig.String("session_encryption_key"),
+		OAuthStateExpiry:           stateExpiry,
+		MasterKeyConfigFile:        beego.AppConfig.String("master_keys"),
+		OrgOwnerSessionLifetime:    ownerSession,
+		CorpManagerSessionLifetime: corpManagerSession,
+		PDFOrgSignatureDir:         beego.AppConfig.String("pdf_org_signature_dir"),
+		PDFOutDir:                  beego.AppConfig.String("pdf_out_dir"),
+		PDFFontConfigFile:          beego.AppConfig.String("pdf_font_config"),
+		CodePlatformConfigFile:     beego.AppConfig.String("code_platforms"),
+		EmailPlatformConfigFile:    beego.AppConfig.String("email_platforms"),
+		RobotConfigFile:            beego.AppConfig.String("robot_config"),
+		EmployeeManagersNumber:     employeeMangers,
+		EmailWorkerNumber:          emailWorkers,
+		EmailMaxAttempts:           emailAttempts,
 	}
 	return AppConfig.validate()
 }
 
 func (this *appConfig) validate() error {
-	if util.IsFileNotExist(this.PythonBin) {
-		return fmt.Errorf("The file:%s is not exist", this.PythonBin)
+	switch this.DBDriver {
+	case DBDriverMongodb:
+	case DBDriverPostgresql:
+		if this.PostgresqlConn == "" {
+			return fmt.Errorf("The postgresql_conn should be set if the db_driver is %s", DBDriverPostgresql)
+		}
+	default:
+		return fmt.Errorf("The db_driver:%s is not supported", this.DBDriver)
 	}
 
 	if this.VerificationCodeExpiry <= 0 {
@@ -69,14 +132,42 @@ func (this *appConfig) validate() error {
 		return fmt.Errorf("The apit_oken_expiry:%d should be bigger than 0", this.APITokenExpiry)
 	}
 
+	if this.APIRefreshTokenExpiry <= 0 {
+		return fmt.Errorf("The api_refresh_token_expiry:%d should be bigger than 0", this.APIRefreshTokenExpiry)
+	}
+
+	if this.OrgOwnerSessionLifetime < this.APITokenExpiry {
+		return fmt.Errorf("The org_owner_session_lifetime:%d should not be less than api_token_expiry", this.OrgOwnerSessionLifetime)
+	}
+
+	if this.CorpManagerSessionLifetime < this.APITokenExpiry {
+		return fmt.Errorf("The corp_manager_session_lifetime:%d should not be less than api_token_expiry", this.CorpManagerSessionLifetime)
+	}
+
+	if this.OAuthStateExpiry <= 0 {
+		return fmt.Errorf("The oauth_state_expiry:%d should be bigger than 0", this.OAuthStateExpiry)
+	}
+
 	if this.EmployeeManagersNumber <= 0 {
 		return fmt.Errorf("The employee_managers_number:%d should be bigger than 0", this.EmployeeManagersNumber)
 	}
 
+	if this.EmailWorkerNumber <= 0 {
+		return fmt.Errorf("The email_worker_number:%d should be bigger than 0", this.EmailWorkerNumber)
+	}
+
+	if this.EmailMaxAttempts <= 0 {
+		return fmt.Errorf("The email_max_attempts:%d should be bigger than 0", this.EmailMaxAttempts)
+	}
+
 	if len(this.APITokenKey) < 20 {
 		return fmt.Errorf("The length of api_token_key should be bigger than 20")
 	}
 
+	if len(this.SessionEncryptionKey) < 20 {
+		return fmt.Errorf("The length of session_encryption_key should be bigger than 20")
+	}
+
 	if util.IsNotDir(this.PDFOrgSignatureDir) {
 		return fmt.Errorf("The directory:%s is not exist", this.PDFOrgSignatureDir)
 	}
@@ -85,6 +176,10 @@ func (this *appConfig) validate() error {
 		return fmt.Errorf("The directory:%s is not exist", this.PDFOutDir)
 
 	}
+	if this.PDFFontConfigFile != "" && util.IsFileNotExist(this.PDFFontConfigFile) {
+		return fmt.Errorf("The file:%s is not exist", this.PDFFontConfigFile)
+	}
+
 	if util.IsFileNotExist(this.CodePlatformConfigFile) {
 		return fmt.Errorf("The file:%s is not exist", this.CodePlatformConfigFile)
 	}
@@ -92,5 +187,13 @@ func (this *appConfig) validate() error {
 	if util.IsFileNotExist(this.EmailPlatformConfigFile) {
 		return fmt.Errorf("The file:%s is not exist", this.EmailPlatformConfigFile)
 	}
+
+	if this.RobotConfigFile != "" && util.IsFileNotExist(this.RobotConfigFile) {
+		return fmt.Errorf("The file:%s is not exist", this.RobotConfigFile)
+	}
+
+	if util.IsFileNotExist(this.MasterKeyConfigFile) {
+		return fmt.Errorf("The file:%s is not exist", this.MasterKeyConfigFile)
+	}
 	return nil
 }
@@ -7,6 +7,8 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/huaweicloud/golangsdk"
+
+	"github.com/opensourceways/app-cla-server/models"
 )
 
 const (
@@ -21,23 +23,30 @@ type accessControllerInterface interface {
 	ParseToken(token, secret string) error
 	Verify(permission []string) error
 	GetUser() string
+	GetPermission() string
+	GetSession() (string, int64)
 }
 
 type accessController struct {
-	Expiry     int64  `json:"expiry"`
-	User       string `json:"user"`
-	Permission string `json:"permission"`
-	secret     string `json:"-"`
+	Expiry        int64  `json:"expiry"`
+	User          string `json:"user"`
+	Permission    string `json:"permission"`
+	SessionID     string `json:"session_id"`
+	SessionExpiry int64  `json:"session_expiry"`
+	secret        string `json:"-"`
 }
 
+// codePlatformAuth is the access controller of user who logins by code platform.
+// The token of code platform is kept in the session store rather than the claims.
 type codePlatformAuth struct {
 	accessController
-
-	PlatformToken string `json:"platform_token"`
 }
 
 func (this *accessController) NewToken(expiry int64) (string, error) {
 	this.Expiry = time.Now().Add(time.Second * time.Duration(expiry)).Unix()
+	if this.SessionExpiry > 0 && this.Expiry > this.SessionExpiry {
+		this.Expiry = this.SessionExpiry
+	}
 
 	body, err := golangsdk.BuildRequestBody(this, "")
 	if err != nil {
@@ -87,16 +96,48 @@ func (this *accessController) GetUser() string {
 	return this.User
 }
 
+func (this *accessController) GetPermission() string {
+	return this.Permission
+}
+
+func (this *accessController) GetSession() (string, int64) {
+	return this.SessionID, this.SessionExpiry
+}
+
+func (this *codePlatformAuth) GetPlatformToken() (string, error) {
+	return models.GetPlatformToken(this.SessionID)
+}
+
 func (this *accessController) Verify(permission []string) error {
 	if this.Expiry < time.Now().Unix() {
 		return fmt.Errorf("token is expired")
 	}
 
+	bingo := false
 	for _, item := range permission {
 		if this.Permission == item {
-			return nil
+			bingo = true
+			break
 		}
 	}
+	if !bingo {
+		return fmt.Errorf("Not allowed permission")
+	}
+
+	return this.verifySession()
+}
+
+func (this *accessController) verifySession() error {
+	if this.SessionID == "" {
+		return fmt.Errorf("token doesn't belong to any session")
+	}
 
-	return fmt.Errorf("Not allowed permission")
+	revoked, err := models.IsAccessSessionRevoked(this.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to check the session: %s", err.Error())
+	}
+	if revoked {
+		return fmt.Errorf("token is revoked")
+	}
+	return nil
 }
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/opensourceways/app-cla-server/conf"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type accessTokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func sessionLifetime(permission string) int64 {
+	cfg := conf.AppConfig
+
+	switch permission {
+	case PermissionOwnerOfOrg:
+		return cfg.OrgOwnerSessionLifetime
+	case PermissionCorporAdmin, PermissionEmployeeManager:
+		return cfg.CorpManagerSessionLifetime
+	}
+	return cfg.APIRefreshTokenExpiry
+}
+
+func hashToken(token string) string {
+	v := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(v[:])
+}
+
+// newAccessTokens starts a new session and returns the tokens of it.
+// The token of code platform is kept in the session store if it is passed.
+func newAccessTokens(user, permission, platformToken string) (accessTokens, error) {
+	session := dbmodels.RefreshToken{
+		SessionID:     util.RandStr(32, "alphanum"),
+		User:          user,
+		Permission:    permission,
+		SessionExpiry: util.Now() + sessionLifetime(permission),
+	}
+
+	if platformToken != "" {
+		err := models.SavePlatformToken(session.SessionID, platformToken, session.SessionExpiry)
+		if err != nil {
+			return accessTokens{}, err
+		}
+	}
+
+	return issueAccessTokens(session)
+}
+
+// issueAccessTokens issues a pair of access token and refresh token for the session.
+func issueAccessTokens(session dbmodels.RefreshToken) (accessTokens, error) {
+	cfg := conf.AppConfig
+
+	ac := accessController{
+		User:          session.User,
+		Permission:    session.Permission,
+		SessionID:     session.SessionID,
+		SessionExpiry: session.SessionExpiry,
+		secret:        cfg.APITokenKey,
+	}
+
+	at, err := ac.NewToken(cfg.APITokenExpiry)
+	if err != nil {
+		return accessTokens{}, err
+	}
+
+	rt := util.RandStr(48, "alphanum")
+
+	session.ID = hashToken(rt)
+	session.Expiry = util.Now() + cfg.APIRefreshTokenExpiry
+	if session.Expiry > session.SessionExpiry {
+		session.Expiry = session.SessionExpiry
+	}
+
+	if err := models.AddRefreshToken(session); err != nil {
+		return accessTokens{}, err
+	}
+
+	return accessTokens{AccessToken: at, RefreshToken: rt}, nil
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type AuditLogController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Description get the audit logs of org
+// @Param	:platform	path 	string	true		"code platform"
+// @Param	:org_id		path 	string	true		"org"
+// @Param	corp_id		query 	string	false		"the email suffix of corporation"
+// @Param	action		query 	string	false		"action"
+// @Param	page		query 	int	false		"page number, starts from 1"
+// @Param	per_page	query 	int	false		"number of items per page"
+// @Param	order		query 	string	false		"asc or desc by the time"
+// @Success 200 {object} dbmodels.AuditLog
+// @router /:platform/:org_id [get]
+func (this *AuditLogController) GetAll() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "list audit logs of org")
+	}()
+
+	if err := checkAPIStringParameter(&this.Controller, []string{":platform", ":org_id"}); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	page, err := fetchPageOption(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	opt := models.AuditLogListOption{
+		Platform: this.GetString(":platform"),
+		OrgID:    this.GetString(":org_id"),
+		CorpID:   this.GetString("corp_id"),
+		Action:   this.GetString("action"),
+		Page:     page,
+	}
+
+	if err := checkOrgAdmin(&this.Controller, opt.Platform, opt.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
+
+	r, total, err := opt.List()
+	if err != nil {
+		reason = err
+		return
+	}
+
+	body = listResult{Total: total, Items: r}
+}
+
+// @Title GetCorporationLogs
+// @Description get the audit logs of corporation which the administrator belongs to
+// @Param	action		query 	string	false		"action"
+// @Param	page		query 	int	false		"page number, starts from 1"
+// @Param	per_page	query 	int	false		"number of items per page"
+// @Param	order		query 	string	false		"asc or desc by the time"
+// @Success 200 {object} dbmodels.AuditLog
+// @router / [get]
+func (this *AuditLogController) GetCorporationLogs() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "list audit logs of corporation")
+	}()
+
+	claOrgID, corpEmail, err := parseCorpManagerUser(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+
+	page, err := fetchPageOption(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		return
+	}
+
+	opt := models.AuditLogListOption{
+		Platform: claOrg.Platform,
+		OrgID:    claOrg.OrgID,
+		CorpID:   util.EmailSuffix(corpEmail),
+		Action:   this.GetString("action"),
+		Page:     page,
+	}
+
+	r, total, err := opt.List()
+	if err != nil {
+		reason = err
+		return
+	}
+
+	body = listResult{Total: total, Items: r}
+}
+
+// addAuditLog records the action done by the user of token on the binding.
+// The error is only logged, because the action has been done.
+func addAuditLog(c *beego.Controller, claOrg *models.CLAOrg, log models.AuditLog) {
+	actor, err := getApiAccessUser(c)
+	if err != nil {
+		beego.Error(fmt.Sprintf("failed to add audit log of %s: %s", log.Action, err.Error()))
+		return
+	}
+
+	log.Actor = actor
+	log.SourceIP = c.Ctx.Input.IP()
+	log.Platform = claOrg.Platform
+	log.OrgID = claOrg.OrgID
+	log.RepoID = claOrg.RepoID
+	log.CLAOrgID = claOrg.ID
+
+	if err := (&log).Create(); err != nil {
+		beego.Error(fmt.Sprintf("failed to add audit log of %s: %s", log.Action, err.Error()))
+	}
+}
+
+// employeeSigningState returns the state of employee signing to be recorded
+// in the audit log, or nil if it can't be found.
+func employeeSigningState(claOrg *models.CLAOrg, email string) map[string]string {
+	enabled, err := models.IsIndividualSigned(claOrg.Platform, claOrg.OrgID, claOrg.RepoID, email)
+	if err != nil {
+		return nil
+	}
+	return map[string]string{"enabled": strconv.FormatBool(enabled)}
+}
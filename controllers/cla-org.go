@@ -16,17 +16,6 @@ type CLAOrgController struct {
 	beego.Controller
 }
 
-func (this *CLAOrgController) Prepare() {
-	if getRouterPattern(&this.Controller) == "/v1/cla-org/:platform/:org_id/:apply_to" {
-		if getHeader(&this.Controller, headerToken) != "" {
-			apiPrepare(&this.Controller, []string{PermissionIndividualSigner}, nil)
-			return
-		}
-	}
-
-	apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg}, &codePlatformAuth{})
-}
-
 // @Title Bind CLA to Org/Repo
 // @Description bind cla
 // @Param	body		body 	models.CLAOrg	true		"body for org-repo content"
@@ -50,6 +39,12 @@ func (this *CLAOrgController) Post() {
 		return
 	}
 
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		statusCode = 403
+		return
+	}
+
 	cla := &models.CLA{ID: claOrg.CLAID}
 
 	if err := cla.Get(); err != nil {
@@ -105,6 +100,17 @@ func (this *CLAOrgController) Delete() {
 	}
 
 	claOrg := models.CLAOrg{ID: uid}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		statusCode = 403
+		return
+	}
 
 	if err := claOrg.Delete(); err != nil {
 		reason = err
@@ -113,10 +119,24 @@ func (this *CLAOrgController) Delete() {
 	}
 
 	body = "unbinding successfully"
+
+	addAuditLog(&this.Controller, &claOrg, models.AuditLog{
+		Action: models.AuditActionDeleteBinding,
+		Target: claOrg.ID,
+		Before: map[string]string{
+			"cla_id":       claOrg.CLAID,
+			"cla_language": claOrg.CLALanguage,
+			"apply_to":     claOrg.ApplyTo,
+			"org_email":    claOrg.OrgEmail,
+		},
+	})
 }
 
 // @Title GetAll
 // @Description get all bindings
+// @Param	page		query 	int	false		"page number, starts from 1"
+// @Param	per_page	query 	int	false		"number of items per page"
+// @Param	order		query 	string	false		"asc or desc by the created time"
 // @Success 200 {object} models.CLAOrg
 // @router /:platform/:org_id [get]
 func (this *CLAOrgController) GetAll() {
@@ -135,21 +155,30 @@ func (this *CLAOrgController) GetAll() {
 			return
 		}
 	}
+
+	page, err := fetchPageOption(&this.Controller, dbmodels.SortByDate)
+	if err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
 	opt := models.CLAOrgListOption{
 		Platform: this.GetString(":platform"),
 		OrgID:    this.GetString(":org_id"),
 		RepoID:   this.GetString("repo_id"),
 		ApplyTo:  this.GetString("apply_to"),
+		Page:     page,
 	}
 
-	r, err := opt.List()
+	r, total, err := opt.List()
 	if err != nil {
 		reason = err
 		statusCode = 500
 		return
 	}
 
-	body = r
+	body = listResult{Total: total, Items: r}
 }
 
 // @Title GetSigningPageInfo
@@ -196,7 +225,7 @@ func (this *CLAOrgController) GetSigningPageInfo() {
 		return
 	}
 
-	claOrgs, err := opt.List()
+	claOrgs, _, err := opt.List()
 	if err != nil {
 		reason = err
 		return
@@ -261,6 +290,12 @@ func (this *CLAOrgController) GetBlankPdf() {
 		return
 	}
 
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		statusCode = 403
+		return
+	}
+
 	if claOrg.ApplyTo != dbmodels.ApplyToCorporation {
 		reason = fmt.Errorf("Only can review blank pdf of corporation")
 		statusCode = 400
@@ -288,3 +323,119 @@ func (this *CLAOrgController) GetBlankPdf() {
 
 	pdf.GetPDFGenerator().GenCLAPDFForCorporation(claOrg, &signing, cla)
 }
+
+// @Title PublishCLAVersion
+// @Description publish a new version of cla for the binding
+// @Param	uid		path 	string				true		"The uid of binding"
+// @Param	body		body 	models.CLAVersionPublishOption	true		"body for new version"
+// @Success 201 {int} map
+// @router /:uid/version [post]
+func (this *CLAOrgController) PublishCLAVersion() {
+	var statusCode = 0
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse1(&this.Controller, statusCode, reason, body)
+	}()
+
+	uid := this.GetString(":uid")
+
+	var opt models.CLAVersionPublishOption
+	if err := json.Unmarshal(this.Ctx.Input.RequestBody, &opt); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	if err := opt.Validate(); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	claOrg := &models.CLAOrg{ID: uid}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		statusCode = 403
+		return
+	}
+
+	cla := &models.CLA{ID: opt.CLAID}
+	if err := cla.Get(); err != nil {
+		reason = fmt.Errorf("error finding the cla(id:%s), err: %v", cla.ID, err)
+		statusCode = 400
+		return
+	}
+
+	if cla.Language != claOrg.CLALanguage || cla.ApplyTo != claOrg.ApplyTo {
+		reason = fmt.Errorf("the language or apply_to of cla(id:%s) is not same as the binding", cla.ID)
+		statusCode = 400
+		return
+	}
+
+	v, err := opt.Publish(uid)
+	if err != nil {
+		reason = err
+		statusCode, _ = convertDBError(err)
+		return
+	}
+
+	body = map[string]int{"cla_version": v}
+}
+
+// @Title ListOutdatedSignings
+// @Description list the signings of outdated cla version
+// @Param	uid		path 	string	true		"The uid of binding"
+// @Param	page		query 	int	false		"page number of both individual and corporation signings, starts from 1"
+// @Param	per_page	query 	int	false		"number of items per page"
+// @Param	sort_by		query 	string	false		"date or email"
+// @Param	order		query 	string	false		"asc or desc"
+// @Success 200 {object} dbmodels.OutdatedSignings
+// @router /:uid/outdated-signing [get]
+func (this *CLAOrgController) ListOutdatedSignings() {
+	var statusCode = 0
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse1(&this.Controller, statusCode, reason, body)
+	}()
+
+	uid := this.GetString(":uid")
+
+	page, err := fetchPageOption(&this.Controller, dbmodels.SortByDate, dbmodels.SortByEmail)
+	if err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	claOrg := &models.CLAOrg{ID: uid}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		statusCode = 403
+		return
+	}
+
+	r, err := models.ListOutdatedSignings(uid, page)
+	if err != nil {
+		reason = err
+		statusCode, _ = convertDBError(err)
+		return
+	}
+
+	body = r
+}
@@ -13,10 +13,6 @@ type CLAController struct {
 	beego.Controller
 }
 
-func (this *CLAController) Prepare() {
-	apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg}, nil)
-}
-
 // @Title CreateCLA
 // @Description create cla
 // @Param	body		body 	models.CLA	true		"body for cla content"
@@ -80,6 +76,10 @@ func (this *CLAController) Delete() {
 
 	cla := models.CLA{ID: uid}
 
+	if statusCode, reason = this.checkSubmitter(&cla); reason != nil {
+		return
+	}
+
 	if err := (&cla).Delete(); err != nil {
 		reason = err
 		statusCode = 500
@@ -113,9 +113,7 @@ func (this *CLAController) Get() {
 
 	cla := models.CLA{ID: uid}
 
-	if err := (&cla).Get(); err != nil {
-		reason = err
-		statusCode = 500
+	if statusCode, reason = this.checkSubmitter(&cla); reason != nil {
 		return
 	}
 
@@ -158,3 +156,20 @@ func (this *CLAController) GetAll() {
 
 	body = r
 }
+
+// checkSubmitter loads the cla and checks whether it is submitted by the caller.
+func (this *CLAController) checkSubmitter(cla *models.CLA) (int, error) {
+	user, err := getApiAccessUser(&this.Controller)
+	if err != nil {
+		return 400, err
+	}
+
+	if err := cla.Get(); err != nil {
+		return 500, err
+	}
+
+	if cla.Submitter != user {
+		return 403, fmt.Errorf("the cla(id:%s) is not submitted by you", cla.ID)
+	}
+	return 0, nil
+}
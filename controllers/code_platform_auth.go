@@ -7,6 +7,7 @@ import (
 	"github.com/astaxie/beego"
 
 	platformAuth "github.com/opensourceways/app-cla-server/code-platform-auth"
+	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
@@ -25,7 +26,7 @@ func (this *AuthController) Auth() {
 		sendResponse(&this.Controller, statusCode, errCode, reason, nil, "authorize by gitee/github")
 	}
 
-	params := map[string]string{":platform": "", "code": "", ":purpose": "", "state": authURLState}
+	params := map[string]string{":platform": "", "code": "", ":purpose": "", "state": ""}
 	if err := checkAndVerifyAPIStringParameter(&this.Controller, params); err != nil {
 		rs(400, util.ErrInvalidParameter, err)
 		return
@@ -43,13 +44,19 @@ func (this *AuthController) Auth() {
 		return
 	}
 
-	token, user, err := cp.Auth(code, scope)
+	state, err := checkOAuthState(&this.Controller, codePlatformAuthPurpose(platform, purpose))
+	if err != nil {
+		rs(400, util.ErrInvalidOAuthState, err)
+		return
+	}
+
+	token, user, err := cp.Auth(code, scope, state.Verifier)
 	if err != nil {
 		rs(500, util.ErrSystemError, err)
 		return
 	}
 
-	at, err := newAccessTokenAuthorizedByCodePlatform(
+	at, err := newAccessTokens(
 		fmt.Sprintf("%s/%s", platform, user),
 		actionToPermission(purpose),
 		token,
@@ -59,16 +66,22 @@ func (this *AuthController) Auth() {
 		return
 	}
 
-	this.Ctx.SetCookie("access_token", at, "3600", "/")
-	this.Ctx.SetCookie("platform_token", token, "3600", "/")
+	this.Ctx.SetCookie("access_token", at.AccessToken, "3600", "/")
+	this.Ctx.SetCookie("refresh_token", at.RefreshToken, "3600", "/")
 
-	http.Redirect(this.Ctx.ResponseWriter, this.Ctx.Request, cp.WebRedirectDir(), http.StatusFound)
+	redirect, err := buildReturnURL(cp.WebRedirectDir(), state.ReturnURL)
+	if err != nil {
+		redirect = cp.WebRedirectDir()
+	}
+
+	http.Redirect(this.Ctx.ResponseWriter, this.Ctx.Request, redirect, http.StatusFound)
 }
 
 // @Title Get
 // @Description get auth code url
 // @Param	:platform	path 	string				true		"gitee/github"
 // @Param	:purpose	path 	string				true		"purpose: login, sign"
+// @Param	return_url	query 	string				false		"the url to return to after authorization"
 // @Success 200 {object}
 // @Failure util.ErrNotSupportedPlatform
 // @router /authcodeurl/:platform/:purpose [get]
@@ -105,7 +118,8 @@ func (this *AuthController) Get() {
 		return
 	}
 
-	cp, err := platformAuth.GetAuthInstance(this.GetString(":platform"), purpose)
+	platform := this.GetString(":platform")
+	cp, err := platformAuth.GetAuthInstance(platform, purpose)
 	if cp == nil {
 		reason = err
 		errCode = util.ErrNotSupportedPlatform
@@ -113,7 +127,123 @@ func (this *AuthController) Get() {
 		return
 	}
 
+	returnURL := this.GetString("return_url")
+	if _, err := buildReturnURL(cp.WebRedirectDir(), returnURL); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	state, verifier, err := newOAuthState(
+		&this.Controller, codePlatformAuthPurpose(platform, purpose), returnURL,
+	)
+	if err != nil {
+		reason = err
+		return
+	}
+
 	body = map[string]string{
-		"url": cp.GetAuthCodeURL(authURLState),
+		"url": cp.GetAuthCodeURL(state, verifier),
+	}
+}
+
+// @Title Refresh
+// @Description refresh the access token by refresh token
+// @Param	body		body 	controllers.refreshTokenRequest	true		"body for refresh token"
+// @Success 201 {object} controllers.accessTokens
+// @Failure util.ErrInvalidRefreshToken
+// @Failure util.ErrSessionExpired
+// @router /refresh [post]
+func (this *AuthController) Refresh() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "refresh access token")
+	}()
+
+	var info refreshTokenRequest
+	if err := fetchInputPayload(&this.Controller, &info); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+	if info.RefreshToken == "" {
+		reason = fmt.Errorf("missing refresh_token")
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	session, err := models.UseRefreshToken(hashToken(info.RefreshToken))
+	if err != nil {
+		reason = err
+		return
 	}
+
+	if session.SessionExpiry < util.Now() {
+		reason = fmt.Errorf("session is expired, please login again")
+		errCode = util.ErrSessionExpired
+		statusCode = 401
+		return
+	}
+
+	revoked, err := models.IsAccessSessionRevoked(session.SessionID)
+	if err != nil {
+		reason = err
+		return
+	}
+	if revoked {
+		reason = fmt.Errorf("session is revoked, please login again")
+		errCode = util.ErrSessionExpired
+		statusCode = 401
+		return
+	}
+
+	tokens, err := issueAccessTokens(session)
+	if err != nil {
+		reason = err
+		return
+	}
+
+	body = tokens
+}
+
+// @Title Logout
+// @Description revoke the session of current token
+// @Success 202 {int} map
+// @router /logout [post]
+func (this *AuthController) Logout() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "logout")
+	}()
+
+	ac, err := getAccessController(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+
+	sessionID, expiry := ac.GetSession()
+	if err := models.RevokeAccessSession(sessionID, expiry); err != nil {
+		reason = err
+		return
+	}
+
+	body = "logout successfully"
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
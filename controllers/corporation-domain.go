@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+	"github.com/opensourceways/app-cla-server/worker"
+)
+
+type CorporationDomainController struct {
+	beego.Controller
+}
+
+// @Title Post
+// @Description request to add a domain to the corporation, which must be approved by the org owner
+// @Param	body		body 	models.CorporationDomainCreateOption	true		"body for corporation domain"
+// @Success 201 {int} map
+// @Failure util.ErrCorpDomainExists
+// @router / [post]
+func (this *CorporationDomainController) Post() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "request corporation domain")
+	}()
+
+	claOrgID, adminEmail, err := parseCorpManagerUser(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+
+	var info models.CorporationDomainCreateOption
+	if err := fetchInputPayload(&this.Controller, &info); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	if c, err := (&info).Validate(); err != nil {
+		reason = err
+		errCode = c
+		statusCode = 400
+		return
+	}
+
+	if err := (&info).Create(claOrgID, adminEmail); err != nil {
+		reason = err
+		return
+	}
+
+	body = "the domain is waiting for approval"
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		return
+	}
+
+	addAuditLog(&this.Controller, claOrg, models.AuditLog{
+		Action: models.AuditActionRequestCorpDomain,
+		CorpID: util.EmailSuffix(adminEmail),
+		Target: info.Domain,
+		After:  map[string]string{"status": dbmodels.CorpDomainStatusPending},
+	})
+}
+
+// @Title GetAll
+// @Description get all the domains of corporation which the administrator belongs to
+// @Success 200 {object} dbmodels.CorporationDomain
+// @router / [get]
+func (this *CorporationDomainController) GetAll() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "list corporation domains")
+	}()
+
+	claOrgID, corpEmail, err := parseCorpManagerUser(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+
+	r, err := models.ListCorporationDomain(claOrgID, dbmodels.CorporationDomainListOption{
+		CorpID: util.EmailSuffix(corpEmail),
+	})
+	if err != nil {
+		reason = err
+		return
+	}
+
+	body = r
+}
+
+// @Title List
+// @Description get the domains of all the corporations which have signed on the cla
+// @Param	:cla_org_id	path 	string	true		"cla org id"
+// @Param	status		query 	string	false		"pending, approved or rejected"
+// @Success 200 {object} dbmodels.CorporationDomain
+// @router /:cla_org_id [get]
+func (this *CorporationDomainController) List() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "list corporation domains of cla")
+	}()
+
+	claOrgID, err := fetchStringParameter(&this.Controller, ":cla_org_id")
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
+
+	r, err := models.ListCorporationDomain(claOrgID, dbmodels.CorporationDomainListOption{
+		Status: this.GetString("status"),
+	})
+	if err != nil {
+		reason = err
+		return
+	}
+
+	body = r
+}
+
+// @Title Put
+// @Description approve or reject the pending domain of corporation
+// @Param	:cla_org_id	path 	string					true		"cla org id"
+// @Param	:domain		path 	string					true		"domain"
+// @Param	body		body 	models.CorporationDomainReviewOption	true		"body for review"
+// @Success 202 {int} map
+// @Failure util.ErrNoPendingCorpDomain
+// @router /:cla_org_id/:domain [put]
+func (this *CorporationDomainController) Put() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "review corporation domain")
+	}()
+
+	if err := checkAPIStringParameter(&this.Controller, []string{":cla_org_id", ":domain"}); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+	claOrgID := this.GetString(":cla_org_id")
+	domain := this.GetString(":domain")
+
+	var info models.CorporationDomainReviewOption
+	if err := fetchInputPayload(&this.Controller, &info); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
+
+	if err := (&info).Review(claOrgID, domain); err != nil {
+		reason = err
+		return
+	}
+
+	body = "review corporation domain successfully"
+
+	item := findCorpDomain(claOrgID, domain)
+	if item == nil {
+		return
+	}
+
+	addAuditLog(&this.Controller, claOrg, models.AuditLog{
+		Action: models.AuditActionReviewCorpDomain,
+		CorpID: item.CorpID,
+		Target: domain,
+		Before: map[string]string{"status": dbmodels.CorpDomainStatusPending},
+		After:  map[string]string{"status": item.Status},
+	})
+
+	d := email.CorpDomainStatus{Domain: domain, Approved: info.Approved}
+	msg, err := d.GenEmailMsg()
+	if err != nil {
+		beego.Error(err)
+		return
+	}
+	msg.To = []string{item.Requester}
+	msg.Subject = "Review of Corporation Domain"
+
+	worker.GetEmailWorker().SendSimpleMessage(claOrg.OrgEmail, msg)
+}
+
+func findCorpDomain(claOrgID, domain string) *dbmodels.CorporationDomain {
+	v, err := models.ListCorporationDomain(claOrgID, dbmodels.CorporationDomainListOption{})
+	if err != nil {
+		beego.Error(err)
+		return nil
+	}
+
+	for i := range v {
+		if v[i].Domain == domain {
+			return &v[i]
+		}
+	}
+	return nil
+}
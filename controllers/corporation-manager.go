@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"fmt"
-	"net/http"
 
 	"github.com/astaxie/beego"
 
@@ -15,18 +14,6 @@ type CorporationManagerController struct {
 	beego.Controller
 }
 
-func (this *CorporationManagerController) Prepare() {
-	switch getRequestMethod(&this.Controller) {
-	case http.MethodPut:
-		// add administrator
-		apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg}, nil)
-
-	case http.MethodPatch:
-		// reset password of manager
-		apiPrepare(&this.Controller, []string{PermissionCorporAdmin, PermissionEmployeeManager}, nil)
-	}
-}
-
 // @Title authenticate corporation manager
 // @Description authenticate corporation manager
 // @Param	body		body 	models.CorporationManagerAuthentication	true		"body for corporation manager info"
@@ -59,8 +46,9 @@ func (this *CorporationManagerController) Auth() {
 
 	type authInfo struct {
 		dbmodels.CorporationManagerCheckResult
-		Token    string `json:"token"`
-		CLAOrgID string `json:"cla_org_id"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		CLAOrgID     string `json:"cla_org_id"`
 	}
 
 	result := make([]authInfo, 0, len(v))
@@ -68,7 +56,7 @@ func (this *CorporationManagerController) Auth() {
 	for claOrgID, items := range v {
 		for _, item := range items {
 			user := corpManagerUser(claOrgID, item.Email)
-			token, err := newAccessToken(user, corporRoleToPermission(item.Role))
+			tokens, err := newAccessTokens(user, corporRoleToPermission(item.Role), "")
 			if err != nil {
 				continue
 			}
@@ -78,7 +66,8 @@ func (this *CorporationManagerController) Auth() {
 
 			result = append(result, authInfo{
 				CorporationManagerCheckResult: item,
-				Token:                         token,
+				Token:                         tokens.AccessToken,
+				RefreshToken:                  tokens.RefreshToken,
 				CLAOrgID:                      claOrgID,
 			})
 		}
@@ -114,6 +103,20 @@ func (this *CorporationManagerController) Put() {
 	claOrgID := this.GetString(":cla_org_id")
 	adminEmail := this.GetString(":email")
 
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode, errCode = convertDBError(err)
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
+
 	info, err := models.CheckCorporationSigning(claOrgID, adminEmail)
 	if err != nil {
 		reason = err
@@ -132,6 +135,13 @@ func (this *CorporationManagerController) Put() {
 		return
 	}
 
+	if info.Status != dbmodels.CorpSigningStatusApproved {
+		reason = fmt.Errorf("corporation signing has not been approved")
+		errCode = util.ErrInvalidSigningStatus
+		statusCode = 400
+		return
+	}
+
 	added, err := models.CreateCorporationAdministrator(claOrgID, adminEmail)
 	if err != nil {
 		reason = err
@@ -140,10 +150,13 @@ func (this *CorporationManagerController) Put() {
 
 	body = "add manager successfully"
 
-	claOrg := &models.CLAOrg{ID: claOrgID}
-	if err := claOrg.Get(); err != nil {
-		return
-	}
+	addAuditLog(&this.Controller, claOrg, models.AuditLog{
+		Action: models.AuditActionAddCorpAdmin,
+		CorpID: util.EmailSuffix(adminEmail),
+		Target: adminEmail,
+		Before: map[string]string{"admin_added": "false"},
+		After:  map[string]string{"admin_added": "true"},
+	})
 
 	notifyCorpManagerWhenAdding(claOrg.OrgEmail, "Corporation Administrator", added)
 }
@@ -3,12 +3,12 @@ package controllers
 import (
 	"fmt"
 	"io/ioutil"
-	"net/http"
 
 	"github.com/astaxie/beego"
 
 	"github.com/opensourceways/app-cla-server/conf"
 	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/metrics"
 	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/util"
 	"github.com/opensourceways/app-cla-server/worker"
@@ -18,28 +18,6 @@ type CorporationSigningController struct {
 	beego.Controller
 }
 
-func (this *CorporationSigningController) Prepare() {
-	method := getRequestMethod(&this.Controller)
-
-	if getRouterPattern(&this.Controller) == "/v1/corporation-signing/:cla_org_id/:email" {
-		switch method {
-		// upload pdf
-		case http.MethodPatch:
-			apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg}, nil)
-
-		// download pdf
-		case http.MethodGet:
-			apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg, PermissionCorporAdmin}, nil)
-		}
-
-	} else {
-		// list corp signings
-		if method == http.MethodGet {
-			apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg}, nil)
-		}
-	}
-}
-
 // @Title Post
 // @Description sign as corporation
 // @Param	:cla_org_id	path 	string					true		"cla org id"
@@ -100,11 +78,22 @@ func (this *CorporationSigningController) Post() {
 
 	body = "sign successfully"
 
+	metrics.IncSigning(metrics.SigningCorporation, claOrg.Platform, claOrg.OrgID)
+
 	worker.GetEmailWorker().GenCLAPDFForCorporationAndSendIt(claOrg, &info.CorporationSigning, cla)
 }
 
 // @Title GetAll
 // @Description get all the corporations which have signed to a org
+// @Param	page		query 	int	false		"page number, starts from 1"
+// @Param	per_page	query 	int	false		"number of items per page"
+// @Param	sort_by		query 	string	false		"date, name or email"
+// @Param	order		query 	string	false		"asc or desc"
+// @Param	enabled		query 	bool	false		"whether the administrator has been added"
+// @Param	date_from	query 	string	false		"signed on or after the date, such as 2006-01-02"
+// @Param	date_to		query 	string	false		"signed on or before the date"
+// @Param	email		query 	string	false		"substring of the email of administrator"
+// @Param	status		query 	string	false		"status of signing, such as under_review"
 // @router / [get]
 func (this *CorporationSigningController) GetAll() {
 	var statusCode = 0
@@ -116,23 +105,56 @@ func (this *CorporationSigningController) GetAll() {
 		sendResponse(&this.Controller, statusCode, errCode, reason, body, "list corporation")
 	}()
 
+	page, err := fetchPageOption(
+		&this.Controller, dbmodels.SortByDate, dbmodels.SortByName, dbmodels.SortByEmail,
+	)
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	filter, err := fetchSigningFilter(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	if err := checkAPIStringParameter(&this.Controller, []string{"platform", "org_id"}); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
 	opt := models.CorporationSigningListOption{
 		Platform:    this.GetString("platform"),
 		OrgID:       this.GetString("org_id"),
 		RepoID:      this.GetString("repo_id"),
 		CLALanguage: this.GetString("cla_language"),
+		Filter:      filter,
+		Status:      this.GetString("status"),
+		Page:        page,
 	}
 
-	// TODO: check whether can do this
+	if err := checkOrgAdmin(&this.Controller, opt.Platform, opt.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
 
-	r, err := opt.List()
+	r, total, err := opt.List()
 	if err != nil {
 		reason = err
 		statusCode, errCode = convertDBError(err)
 		return
 	}
 
-	body = r
+	body = listResult{Total: total, Items: r}
 }
 
 // @Title Upload
@@ -151,14 +173,28 @@ func (this *CorporationSigningController) Upload() {
 		sendResponse(&this.Controller, statusCode, errCode, reason, body, "upload corp's signing pdf")
 	}()
 
-	// TODO: is this cla bound by the org
-
 	if err := checkAPIStringParameter(&this.Controller, []string{":cla_org_id", ":email"}); err != nil {
 		reason = err
 		errCode = util.ErrInvalidParameter
 		statusCode = 400
 		return
 	}
+	claOrgID := this.GetString(":cla_org_id")
+	corpEmail := this.GetString(":email")
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode, errCode = convertDBError(err)
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
 
 	f, _, err := this.GetFile("pdf")
 	if err != nil {
@@ -177,7 +213,7 @@ func (this *CorporationSigningController) Upload() {
 		return
 	}
 
-	err = models.UploadCorporationSigningPDF(this.GetString(":cla_org_id"), this.GetString(":email"), data)
+	err = models.UploadCorporationSigningPDF(claOrgID, corpEmail, data)
 	if err != nil {
 		reason = err
 		statusCode, errCode = convertDBError(err)
@@ -185,6 +221,90 @@ func (this *CorporationSigningController) Upload() {
 	}
 
 	body = "upload pdf of signature page successfully"
+
+	notifyCorpSigningStatus(claOrgID, corpEmail)
+}
+
+// @Title Review
+// @Description start to review, approve or reject the corporation signing whose pdf has been uploaded
+// @Param	:cla_org_id	path 	string					true		"cla org id"
+// @Param	:email		path 	string					true		"email of corp"
+// @Param	body		body 	models.CorporationSigningReviewOption	true		"body for review"
+// @Success 202 {int} map
+// @Failure util.ErrInvalidSigningStatus
+// @router /:cla_org_id/:email/review [post]
+func (this *CorporationSigningController) Review() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "review corp signing")
+	}()
+
+	if err := checkAPIStringParameter(&this.Controller, []string{":cla_org_id", ":email"}); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+	claOrgID := this.GetString(":cla_org_id")
+	corpEmail := this.GetString(":email")
+
+	var info models.CorporationSigningReviewOption
+	if err := fetchInputPayload(&this.Controller, &info); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	if c, err := (&info).Validate(); err != nil {
+		reason = err
+		errCode = c
+		statusCode = 400
+		return
+	}
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode, errCode = convertDBError(err)
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
+
+	before, err := models.CheckCorporationSigning(claOrgID, corpEmail)
+	if err != nil {
+		reason = err
+		statusCode, errCode = convertDBError(err)
+		return
+	}
+
+	if err := (&info).Review(claOrgID, corpEmail); err != nil {
+		reason = err
+		statusCode, errCode = convertDBError(err)
+		return
+	}
+
+	body = fmt.Sprintf("the status of signing is changed to %s", info.Status())
+
+	addAuditLog(&this.Controller, claOrg, models.AuditLog{
+		Action: models.AuditActionReviewCorpSigning,
+		CorpID: util.EmailSuffix(corpEmail),
+		Target: before.AdminEmail,
+		Before: map[string]string{"status": before.Status},
+		After:  map[string]string{"status": info.Status(), "comment": info.Comment},
+	})
+
+	notifyCorpSigningStatus(claOrgID, corpEmail)
 }
 
 // @Title Download
@@ -209,8 +329,14 @@ func (this *CorporationSigningController) Download() {
 		statusCode = 400
 		return
 	}
+	claOrgID := this.GetString(":cla_org_id")
+	corpEmail := this.GetString(":email")
 
-	pdf, err := models.DownloadCorporationSigningPDF(this.GetString(":cla_org_id"), this.GetString(":email"))
+	if statusCode, errCode, reason = this.canDownload(claOrgID, corpEmail); reason != nil {
+		return
+	}
+
+	pdf, err := models.DownloadCorporationSigningPDF(claOrgID, corpEmail)
 	if err != nil {
 		reason = err
 		statusCode, errCode = convertDBError(err)
@@ -227,6 +353,37 @@ func (this *CorporationSigningController) Download() {
 	}
 }
 
+// canDownload checks whether the caller is the corporation administrator of
+// the signing or the administrator of org which the binding belongs to.
+func (this *CorporationSigningController) canDownload(claOrgID, corpEmail string) (int, string, error) {
+	permission, err := getApiAccessPermission(&this.Controller)
+	if err != nil {
+		return 401, util.ErrUnknownToken, err
+	}
+
+	if permission == PermissionCorporAdmin {
+		corpClaOrgID, _, err := parseCorpManagerUser(&this.Controller)
+		if err != nil {
+			return 401, util.ErrUnknownToken, err
+		}
+		if corpClaOrgID != claOrgID {
+			return 403, util.ErrInvalidParameter, fmt.Errorf("not the signing of your corporation")
+		}
+		return checkSameCorp(&this.Controller, corpEmail)
+	}
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		statusCode, errCode := convertDBError(err)
+		return statusCode, errCode, err
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		return 403, util.ErrInvalidParameter, err
+	}
+	return 0, "", nil
+}
+
 // @Title SendVerifiCode
 // @Description send verification code when signing as Corporation
 // @Param	:cla_org_id	path 	string					true		"cla org id"
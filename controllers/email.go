@@ -9,24 +9,16 @@ import (
 	"github.com/opensourceways/app-cla-server/email"
 )
 
-const authURLState = "state-token-cla"
-
 type EmailController struct {
 	beego.Controller
 }
 
-func (this *EmailController) Prepare() {
-	if getRouterPattern(&this.Controller) == "/v1/email/authcodeurl/:platform" {
-		apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg}, nil)
-	}
-}
-
 // @Title Get
 // @Description get login info
 // @Success 200
 // @router /auth/:platform [get]
 func (this *EmailController) Auth() {
-	params := map[string]string{":platform": "", "code": "", "scope": "", "state": authURLState}
+	params := map[string]string{":platform": "", "code": "", "scope": "", "state": ""}
 	if err := checkAndVerifyAPIStringParameter(&this.Controller, params); err != nil {
 		sendResponse1(&this.Controller, 400, err, nil)
 		return
@@ -41,7 +33,13 @@ func (this *EmailController) Auth() {
 		return
 	}
 
-	opt, err := e.GetAuthorizedEmail(code, scope)
+	state, err := checkOAuthState(&this.Controller, emailAuthPurpose(platform))
+	if err != nil {
+		sendResponse1(&this.Controller, 400, err, nil)
+		return
+	}
+
+	opt, err := e.GetAuthorizedEmail(code, scope, state.Verifier)
 	if err != nil {
 		sendResponse1(&this.Controller, 400, err, nil)
 		return
@@ -55,15 +53,24 @@ func (this *EmailController) Auth() {
 
 	this.Ctx.SetCookie("email", opt.Email, "3600", "/")
 
-	http.Redirect(this.Ctx.ResponseWriter, this.Ctx.Request, e.WebRedirectDir(), http.StatusFound)
+	redirect, err := buildReturnURL(e.WebRedirectDir(), state.ReturnURL)
+	if err != nil {
+		redirect = e.WebRedirectDir()
+	}
+
+	http.Redirect(this.Ctx.ResponseWriter, this.Ctx.Request, redirect, http.StatusFound)
 }
 
 // @Title Get
 // @Description get auth code url
 // @Param	platform		path 	string	true		"The email platform"
+// @Param	return_url		query 	string	false		"the url to return to after authorization"
 // @Success 200 {object}
 // @Failure 403 :platform is empty
-// @router /authcodeurl/:platform [get]
+//
+// It is not routed, because the authorized email is not bound to any org and
+// the caller can't be checked whether administers the org using it.
+// router: /authcodeurl/:platform [get]
 func (this *EmailController) Get() {
 	var statusCode = 0
 	var reason error
@@ -87,7 +94,29 @@ func (this *EmailController) Get() {
 		return
 	}
 
+	returnURL := this.GetString("return_url")
+	if _, err := buildReturnURL(e.WebRedirectDir(), returnURL); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	state, verifier, err := newOAuthState(&this.Controller, emailAuthPurpose(platform), returnURL)
+	if err != nil {
+		reason = err
+		statusCode = 500
+		return
+	}
+
 	body = map[string]string{
-		"url": e.GetOauth2CodeURL(authURLState),
+		"url": e.GetOauth2CodeURL(state, verifier),
 	}
 }
+
+// @Title ListPlatforms
+// @Description list the email platforms which can be authorized
+// @Success 200 {object}
+// @router /platforms [get]
+func (this *EmailController) ListPlatforms() {
+	sendResponse1(&this.Controller, 0, nil, email.ListPlatforms())
+}
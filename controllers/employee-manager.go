@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/astaxie/beego"
 
@@ -14,10 +15,6 @@ type EmployeeManagerController struct {
 	beego.Controller
 }
 
-func (this *EmployeeManagerController) Prepare() {
-	apiPrepare(&this.Controller, []string{PermissionCorporAdmin}, nil)
-}
-
 // @Title Post
 // @Description add employee managers
 // @Param	body		body 	models.EmployeeManagerCreateOption	true		"body for employee manager"
@@ -38,6 +35,9 @@ func (this *EmployeeManagerController) Delete() {
 
 // @Title GetAll
 // @Description get all employee managers
+// @Param	page		query 	int	false		"page number, starts from 1"
+// @Param	per_page	query 	int	false		"number of items per page"
+// @Param	order		query 	string	false		"asc or desc by the email"
 // @Success 200 {object} dbmodels.CorporationManagerListResult
 // @router / [get]
 func (this *EmployeeManagerController) GetAll() {
@@ -58,15 +58,23 @@ func (this *EmployeeManagerController) GetAll() {
 		return
 	}
 
-	r, err := models.ListCorporationManagers(
-		claOrgID, corpEmail, dbmodels.RoleManager,
+	page, err := fetchPageOption(&this.Controller, dbmodels.SortByEmail)
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	r, total, err := models.ListCorporationManagers(
+		claOrgID, corpEmail, dbmodels.RoleManager, page,
 	)
 	if err != nil {
 		reason = err
 		return
 	}
 
-	body = r
+	body = listResult{Total: total, Items: r}
 }
 
 func (this *EmployeeManagerController) addOrDeleteManagers(toAdd bool) {
@@ -119,6 +127,12 @@ func (this *EmployeeManagerController) addOrDeleteManagers(toAdd bool) {
 		if err != nil {
 			reason = err
 		} else {
+			emails := make([]string, 0, len(added))
+			for _, item := range added {
+				emails = append(emails, item.Email)
+			}
+			this.addAuditLog(claOrg, adminEmail, models.AuditActionAddEmployeeManager, emails)
+
 			notifyCorpManagerWhenAdding(claOrg.OrgEmail, "Corporation Manager", added)
 		}
 
@@ -127,7 +141,30 @@ func (this *EmployeeManagerController) addOrDeleteManagers(toAdd bool) {
 		if err != nil {
 			reason = err
 		} else {
+			this.addAuditLog(claOrg, adminEmail, models.AuditActionDeleteEmployeeManager, deleted)
+
 			notifyCorpManagerWhenRemoving(claOrg.OrgEmail, deleted)
 		}
 	}
 }
+
+func (this *EmployeeManagerController) addAuditLog(claOrg *models.CLAOrg, adminEmail, action string, emails []string) {
+	if len(emails) == 0 {
+		return
+	}
+
+	managers := map[string]string{"managers": strings.Join(emails, ",")}
+
+	log := models.AuditLog{
+		Action: action,
+		CorpID: util.EmailSuffix(adminEmail),
+		Target: strings.Join(emails, ","),
+	}
+	if action == models.AuditActionAddEmployeeManager {
+		log.After = managers
+	} else {
+		log.Before = managers
+	}
+
+	addAuditLog(&this.Controller, claOrg, log)
+}
@@ -2,12 +2,13 @@ package controllers
 
 import (
 	"fmt"
-	"net/http"
+	"strconv"
 
 	"github.com/astaxie/beego"
 
 	"github.com/opensourceways/app-cla-server/dbmodels"
 	"github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/metrics"
 	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/util"
 	"github.com/opensourceways/app-cla-server/worker"
@@ -17,16 +18,6 @@ type EmployeeSigningController struct {
 	beego.Controller
 }
 
-func (this *EmployeeSigningController) Prepare() {
-	if getRequestMethod(&this.Controller) == http.MethodPost {
-		// sign as employee
-		apiPrepare(&this.Controller, []string{PermissionIndividualSigner}, nil)
-	} else {
-		// get, update and delete employee
-		apiPrepare(&this.Controller, []string{PermissionEmployeeManager}, nil)
-	}
-}
-
 // @Title Post
 // @Description sign as employee
 // @Param	:cla_org_id	path 	string				true		"cla org id"
@@ -62,6 +53,15 @@ func (this *EmployeeSigningController) Post() {
 		return
 	}
 
+	signer, err := getApiAccessUser(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+	info.Signer = signer
+
 	claOrg := &models.CLAOrg{ID: claOrgID}
 	if err := claOrg.Get(); err != nil {
 		reason = err
@@ -89,12 +89,22 @@ func (this *EmployeeSigningController) Post() {
 	}
 	body = "sign successfully"
 
+	metrics.IncSigning(metrics.SigningEmployee, claOrg.Platform, claOrg.OrgID)
+
 	d := email.EmployeeSigning{}
-	this.notifyManagers(corpSignedCla, info.Email, claOrg.OrgEmail, "Employee Signing", d)
+	this.notifyManagers(corpSignedCla, corpSign.AdminEmail, claOrg.OrgEmail, "Employee Signing", d)
 }
 
 // @Title GetAll
 // @Description get all the employees
+// @Param	page		query 	int	false		"page number, starts from 1"
+// @Param	per_page	query 	int	false		"number of items per page"
+// @Param	sort_by		query 	string	false		"date, name or email"
+// @Param	order		query 	string	false		"asc or desc"
+// @Param	enabled		query 	bool	false		"whether the employee signing is enabled"
+// @Param	date_from	query 	string	false		"signed on or after the date, such as 2006-01-02"
+// @Param	date_to		query 	string	false		"signed on or before the date"
+// @Param	email		query 	string	false		"substring of the email of employee"
 // @Success 200 {int} map
 // @router / [get]
 func (this *EmployeeSigningController) GetAll() {
@@ -121,17 +131,37 @@ func (this *EmployeeSigningController) GetAll() {
 		return
 	}
 
+	page, err := fetchPageOption(
+		&this.Controller, dbmodels.SortByDate, dbmodels.SortByName, dbmodels.SortByEmail,
+	)
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	filter, err := fetchSigningFilter(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
 	opt := models.EmployeeSigningListOption{
 		CLALanguage: this.GetString("cla_language"),
+		Filter:      filter,
+		Page:        page,
 	}
 
-	r, err := opt.List(corpEmail, claOrg.Platform, claOrg.OrgID, claOrg.RepoID)
+	r, total, err := opt.List(claOrgID, corpEmail, claOrg.Platform, claOrg.OrgID, claOrg.RepoID)
 	if err != nil {
 		reason = err
 		return
 	}
 
-	body = r
+	body = listResult{Total: total, Items: r}
 }
 
 // @Title Update
@@ -160,8 +190,8 @@ func (this *EmployeeSigningController) Update() {
 	employeeEmail := this.GetString(":email")
 	claOrgID := this.GetString(":cla_org_id")
 
-	orgEmail := ""
-	statusCode, errCode, orgEmail, reason = this.canHandleOnEmployee(claOrgID, employeeEmail)
+	var claOrg *models.CLAOrg
+	statusCode, errCode, claOrg, reason = this.canHandleOnEmployee(claOrgID, employeeEmail)
 	if reason != nil {
 		return
 	}
@@ -174,6 +204,8 @@ func (this *EmployeeSigningController) Update() {
 		return
 	}
 
+	before := employeeSigningState(claOrg, employeeEmail)
+
 	if err := (&info).Update(claOrgID, employeeEmail); err != nil {
 		reason = err
 		return
@@ -181,6 +213,18 @@ func (this *EmployeeSigningController) Update() {
 
 	body = "enabled employee successfully"
 
+	action := models.AuditActionDisableEmployee
+	if info.Enabled {
+		action = models.AuditActionEnableEmployee
+	}
+	addAuditLog(&this.Controller, claOrg, models.AuditLog{
+		Action: action,
+		CorpID: this.corpID(),
+		Target: employeeEmail,
+		Before: before,
+		After:  map[string]string{"enabled": strconv.FormatBool(info.Enabled)},
+	})
+
 	b := email.EmployeeNotification{}
 	subject := ""
 	if info.Enabled {
@@ -190,7 +234,7 @@ func (this *EmployeeSigningController) Update() {
 		b.Inactive = true
 		subject = "Inavtivate employee"
 	}
-	this.notifyEmployee(employeeEmail, orgEmail, subject, &b)
+	this.notifyEmployee(employeeEmail, claOrg.OrgEmail, subject, &b)
 }
 
 // @Title Delete
@@ -219,12 +263,14 @@ func (this *EmployeeSigningController) Delete() {
 	employeeEmail := this.GetString(":email")
 	claOrgID := this.GetString(":cla_org_id")
 
-	orgEmail := ""
-	statusCode, errCode, orgEmail, reason = this.canHandleOnEmployee(claOrgID, employeeEmail)
+	var claOrg *models.CLAOrg
+	statusCode, errCode, claOrg, reason = this.canHandleOnEmployee(claOrgID, employeeEmail)
 	if reason != nil {
 		return
 	}
 
+	before := employeeSigningState(claOrg, employeeEmail)
+
 	if err := models.DeleteEmployeeSigning(claOrgID, employeeEmail); err != nil {
 		reason = err
 		return
@@ -232,42 +278,56 @@ func (this *EmployeeSigningController) Delete() {
 
 	body = "delete employee successfully"
 
+	addAuditLog(&this.Controller, claOrg, models.AuditLog{
+		Action: models.AuditActionDeleteEmployee,
+		CorpID: this.corpID(),
+		Target: employeeEmail,
+		Before: before,
+	})
+
 	b := email.EmployeeNotification{Removing: true}
 	subject := "Remove employee"
-	this.notifyEmployee(employeeEmail, orgEmail, subject, &b)
+	this.notifyEmployee(employeeEmail, claOrg.OrgEmail, subject, &b)
 }
 
-func (this *EmployeeSigningController) canHandleOnEmployee(claOrgID, employeeEmail string) (int, string, string, error) {
+func (this *EmployeeSigningController) canHandleOnEmployee(claOrgID, employeeEmail string) (int, string, *models.CLAOrg, error) {
 	corpClaOrgID, corpEmail, err := parseCorpManagerUser(&this.Controller)
 	if err != nil {
-		return 401, util.ErrUnknownToken, "", err
+		return 401, util.ErrUnknownToken, nil, err
 	}
 
-	if !isSameCorp(corpEmail, employeeEmail) {
-		return 400, util.ErrNotSameCorp, "", fmt.Errorf("not same corp")
+	same, err := isSameCorp(corpClaOrgID, corpEmail, employeeEmail)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	if !same {
+		return 400, util.ErrNotSameCorp, nil, fmt.Errorf("not same corp")
 	}
 
 	claOrg := &models.CLAOrg{ID: claOrgID}
 	if err := claOrg.Get(); err != nil {
-		return 0, "", "", err
+		return 0, "", nil, err
 	}
 
 	corpClaOrg := &models.CLAOrg{ID: corpClaOrgID}
 	if err := corpClaOrg.Get(); err != nil {
-		return 0, "", "", err
+		return 0, "", nil, err
 	}
 
 	if claOrg.Platform != corpClaOrg.Platform ||
 		claOrg.OrgID != corpClaOrg.OrgID ||
 		claOrg.RepoID != corpClaOrg.RepoID {
-		return 400, util.ErrInvalidParameter, "", fmt.Errorf("not the same repo")
+		return 400, util.ErrInvalidParameter, nil, fmt.Errorf("not the same repo")
 	}
 
-	return 0, "", claOrg.OrgEmail, nil
+	return 0, "", claOrg, nil
 }
 
-func (this *EmployeeSigningController) notifyManagers(corpClaOrgID, employeeEmail, orgEmail, subject string, builder email.IEmailMessageBulder) {
-	managers, err := models.ListCorporationManagers(corpClaOrgID, employeeEmail, dbmodels.RoleManager)
+// notifyManagers notifies the employee managers of corporation which the admin belongs to.
+func (this *EmployeeSigningController) notifyManagers(corpClaOrgID, adminEmail, orgEmail, subject string, builder email.IEmailMessageBulder) {
+	managers, _, err := models.ListCorporationManagers(
+		corpClaOrgID, adminEmail, dbmodels.RoleManager, dbmodels.PageOption{},
+	)
 	if err != nil {
 		beego.Error(err)
 		return
@@ -307,3 +367,10 @@ func (this *EmployeeSigningController) notifyEmployee(employeeEmail, orgEmail, s
 
 	worker.GetEmailWorker().SendSimpleMessage(orgEmail, msg)
 }
+
+// corpID returns the id of corporation which the corp manager belongs to,
+// because the employee may use the other domain of corporation.
+func (this *EmployeeSigningController) corpID() string {
+	_, corpEmail, _ := parseCorpManagerUser(&this.Controller)
+	return util.EmailSuffix(corpEmail)
+}
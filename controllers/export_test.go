@@ -0,0 +1,34 @@
+package controllers
+
+// export the unexported ones for the tests of package controllers_test,
+// which can import routers without import cycle.
+var (
+	FindRoutePermission  = findRoutePermission
+	CheckRoutePermission = checkRoutePermission
+)
+
+// RouteOfOwner returns whether the api can be accessed by the owner of org
+// and whether it requires the token of code platform.
+func RouteOfOwner(key string) (bool, bool) {
+	for _, item := range routePermissions {
+		if item.key() != key {
+			continue
+		}
+
+		for _, p := range item.permissions {
+			if p == PermissionOwnerOfOrg {
+				return true, item.tokenType == tokenCodePlatform
+			}
+		}
+		return false, false
+	}
+	return false, false
+}
+
+func RoutePermissionKeys() []string {
+	r := make([]string, 0, len(routePermissions))
+	for _, item := range routePermissions {
+		r = append(r, item.key())
+	}
+	return r
+}
@@ -1,9 +1,14 @@
 package controllers
 
 import (
+	"fmt"
+
 	"github.com/astaxie/beego"
 
+	"github.com/opensourceways/app-cla-server/conf"
+	"github.com/opensourceways/app-cla-server/dbmodels"
 	// "github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/metrics"
 	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/util"
 	// "github.com/opensourceways/app-cla-server/worker"
@@ -13,14 +18,6 @@ type IndividualSigningController struct {
 	beego.Controller
 }
 
-func (this *IndividualSigningController) Prepare() {
-	if getRouterPattern(&this.Controller) == "/v1/individual-signing/:platform/:org/:repo" {
-		return
-	}
-
-	apiPrepare(&this.Controller, []string{PermissionIndividualSigner}, nil)
-}
-
 // @Title Post
 // @Description sign as individual
 // @Param	:cla_org_id	path 	string				true		"cla org id"
@@ -54,6 +51,15 @@ func (this *IndividualSigningController) Post() {
 		return
 	}
 
+	signer, err := getApiAccessUser(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+	info.Signer = signer
+
 	claOrg := &models.CLAOrg{ID: claOrgID}
 	if err := claOrg.Get(); err != nil {
 		reason = err
@@ -84,6 +90,8 @@ func (this *IndividualSigningController) Post() {
 
 	body = "sign successfully"
 
+	metrics.IncSigning(metrics.SigningIndividual, claOrg.Platform, claOrg.OrgID)
+
 	// worker.GetEmailWorker().SendSimpleMessage(emailCfg, msg)
 }
 
@@ -132,3 +140,155 @@ func (this *IndividualSigningController) Check() {
 		"signed": v,
 	}
 }
+
+// @Title GetAll
+// @Description get the signings and the signing history of current signer
+// @Success 200 {int} map
+// @router / [get]
+func (this *IndividualSigningController) GetAll() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "list individual signings of signer")
+	}()
+
+	signer, err := getApiAccessUser(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+
+	signings, err := models.ListIndividualSigningOfSigner(signer)
+	if err != nil {
+		reason = err
+		return
+	}
+
+	events, err := models.ListIndividualSigningEvents(signer)
+	if err != nil {
+		reason = err
+		return
+	}
+
+	body = map[string]interface{}{
+		"signings": signings,
+		"events":   events,
+	}
+}
+
+// @Title Revoke
+// @Description revoke the signing of current signer
+// @Param	:cla_org_id	path 	string					true		"cla org id"
+// @Param	body		body 	models.IndividualSigningRevokeOption	true		"body for revoking signing"
+// @Success 204 {int} map
+// @Failure util.ErrHasNotSigned
+// @Failure util.ErrWrongVerificationCode
+// @Failure util.ErrVerificationCodeExpired
+// @router /:cla_org_id [delete]
+func (this *IndividualSigningController) Revoke() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "revoke individual signing")
+	}()
+
+	claOrgID, err := fetchStringParameter(&this.Controller, ":cla_org_id")
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	var info models.IndividualSigningRevokeOption
+	if err := fetchInputPayload(&this.Controller, &info); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	if err := (&info).Validate(); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	signer, err := getApiAccessUser(&this.Controller)
+	if err != nil {
+		reason = err
+		errCode = util.ErrUnknownToken
+		statusCode = 401
+		return
+	}
+	info.Signer = signer
+
+	if err := (&info).Revoke(claOrgID); err != nil {
+		reason = err
+		return
+	}
+
+	body = "revoke successfully"
+}
+
+// @Title SendRevokeVerifiCode
+// @Description send verification code to revoke the signing which has no signer
+// @Param	:cla_org_id	path 	string					true		"cla org id"
+// @Param	:email		path 	string					true		"email of signing"
+// @Success 202 {int} map
+// @Failure util.ErrSendingEmail
+// @router /:cla_org_id/:email [put]
+func (this *IndividualSigningController) SendRevokeVerifiCode() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "send verification code")
+	}()
+
+	if err := checkAPIStringParameter(&this.Controller, []string{":cla_org_id", ":email"}); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+	claOrgID := this.GetString(":cla_org_id")
+	email := this.GetString(":email")
+
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		return
+	}
+
+	if claOrg.ApplyTo != dbmodels.ApplyToIndividual {
+		reason = fmt.Errorf("no signing on cla applied to individual")
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	expiry := conf.AppConfig.VerificationCodeExpiry
+	code, err := models.CreateIndividualSigningRevokeVerifCode(email, expiry)
+	if err != nil {
+		reason = err
+		return
+	}
+
+	body = map[string]int64{
+		"expiry": expiry,
+	}
+
+	sendVerificationCodeEmail(code, claOrg.OrgEmail, email)
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+type listResult struct {
+	Total int         `json:"total"`
+	Items interface{} `json:"items"`
+}
+
+// fetchPageOption parses the query parameters of page, per_page, sort_by and order.
+// The first one of sortKeys is the default key to sort.
+func fetchPageOption(c *beego.Controller, sortKeys ...string) (dbmodels.PageOption, error) {
+	opt := dbmodels.PageOption{}
+
+	page, err := c.GetInt("page", 1)
+	if err != nil || page < 1 {
+		return opt, fmt.Errorf("invalid page")
+	}
+
+	perPage, err := c.GetInt("per_page", defaultPerPage)
+	if err != nil || perPage < 1 || perPage > maxPerPage {
+		return opt, fmt.Errorf("per_page must be between 1 and %d", maxPerPage)
+	}
+
+	opt.Page = page
+	opt.PerPage = perPage
+
+	if len(sortKeys) > 0 {
+		opt.SortBy = c.GetString("sort_by", sortKeys[0])
+
+		valid := false
+		for _, k := range sortKeys {
+			if k == opt.SortBy {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return opt, fmt.Errorf("sort_by must be one of %s", strings.Join(sortKeys, ", "))
+		}
+	}
+
+	switch c.GetString("order", "asc") {
+	case "asc":
+	case "desc":
+		opt.Desc = true
+	default:
+		return opt, fmt.Errorf("order must be asc or desc")
+	}
+
+	return opt, nil
+}
+
+// fetchSigningFilter parses the query parameters of enabled, date_from, date_to and email.
+func fetchSigningFilter(c *beego.Controller) (dbmodels.SigningFilter, error) {
+	f := dbmodels.SigningFilter{
+		DateFrom: c.GetString("date_from"),
+		DateTo:   c.GetString("date_to"),
+		Email:    c.GetString("email"),
+	}
+
+	if c.GetString("enabled") != "" {
+		v, err := c.GetBool("enabled")
+		if err != nil {
+			return f, fmt.Errorf("invalid enabled")
+		}
+		f.Enabled = &v
+	}
+
+	for k, v := range map[string]string{"date_from": f.DateFrom, "date_to": f.DateTo} {
+		if v == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			return f, fmt.Errorf("%s must be in the format of 2006-01-02", k)
+		}
+	}
+
+	return f, nil
+}
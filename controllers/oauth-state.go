@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/conf"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/oauth2"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const oauthBindingCookie = "oauth_binding"
+
+func emailAuthPurpose(platform string) string {
+	return fmt.Sprintf("email/%s", platform)
+}
+
+func codePlatformAuthPurpose(platform, purpose string) string {
+	return fmt.Sprintf("code-platform/%s/%s", platform, purpose)
+}
+
+// newOAuthState creates a random state of oauth which is bound to the initiating
+// browser by cookie. It returns the state and the code verifier of PKCE.
+func newOAuthState(c *beego.Controller, purpose, returnURL string) (string, string, error) {
+	expiry := conf.AppConfig.OAuthStateExpiry
+	binding := util.RandStr(32, "alphanum")
+
+	s := dbmodels.OAuthState{
+		State:     util.RandStr(32, "alphanum"),
+		Purpose:   purpose,
+		Verifier:  oauth2.NewCodeVerifier(),
+		Binding:   hashToken(binding),
+		ReturnURL: returnURL,
+		Expiry:    util.Now() + expiry,
+	}
+	if err := models.AddOAuthState(s); err != nil {
+		return "", "", err
+	}
+
+	c.Ctx.SetCookie(oauthBindingCookie, binding, expiry, "/", "", false, true)
+
+	return s.State, s.Verifier, nil
+}
+
+// checkOAuthState checks the state passed to the callback of oauth and
+// returns the stored one. The state can only be used once.
+func checkOAuthState(c *beego.Controller, purpose string) (dbmodels.OAuthState, error) {
+	s, err := models.UseOAuthState(c.GetString("state"))
+	if err != nil {
+		return s, err
+	}
+
+	invalid := func(msg string) (dbmodels.OAuthState, error) {
+		return dbmodels.OAuthState{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidOAuthState,
+			Err:     fmt.Errorf(msg),
+		}
+	}
+
+	if s.Purpose != purpose {
+		return invalid("the oauth state is not for this authorization")
+	}
+
+	binding := c.Ctx.GetCookie(oauthBindingCookie)
+	if binding == "" || hashToken(binding) != s.Binding {
+		return invalid("the oauth state is not initiated by this browser")
+	}
+
+	// the binding is useless after the callback
+	c.Ctx.SetCookie(oauthBindingCookie, "", -1, "/")
+
+	return s, nil
+}
+
+// buildReturnURL returns the url to redirect to after authorization. The return
+// url must be relative to or at the same host as the default one, in case of
+// redirecting to a malicious site.
+func buildReturnURL(defaultURL, returnURL string) (string, error) {
+	if returnURL == "" {
+		return defaultURL, nil
+	}
+
+	base, err := url.Parse(defaultURL)
+	if err != nil {
+		return "", err
+	}
+
+	v, err := url.Parse(returnURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid return url: %s", err.Error())
+	}
+
+	r := base.ResolveReference(v)
+	if r.Scheme != base.Scheme || r.Host != base.Host {
+		return "", fmt.Errorf("the return url must be at the host of %s", base.Host)
+	}
+
+	return r.String(), nil
+}
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -15,10 +17,6 @@ type OrgSignatureController struct {
 	beego.Controller
 }
 
-func (this *OrgSignatureController) Prepare() {
-	apiPrepare(&this.Controller, []string{PermissionOwnerOfOrg}, nil)
-}
-
 // @Title Upload
 // @Description upload pdf of signature page
 // @Param	cla_org_id		path 	string	true		"the id of binding between cla and org"
@@ -40,6 +38,19 @@ func (this *OrgSignatureController) Post() {
 		return
 	}
 
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		statusCode = 403
+		return
+	}
+
 	f, _, err := this.GetFile("signature_page")
 	if err != nil {
 		reason = err
@@ -76,6 +87,13 @@ func (this *OrgSignatureController) Post() {
 	}
 
 	body = "upload pdf of signature page successfully"
+
+	sum := sha256.Sum256(data)
+	addAuditLog(&this.Controller, claOrg, models.AuditLog{
+		Action: models.AuditActionUploadOrgSignature,
+		Target: claOrgID,
+		After:  map[string]string{"sha256": hex.EncodeToString(sum[:])},
+	})
 }
 
 // @Title Get
@@ -99,6 +117,19 @@ func (this *OrgSignatureController) Get() {
 		return
 	}
 
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		reason = err
+		statusCode = 400
+		return
+	}
+
+	if err := checkOrgAdmin(&this.Controller, claOrg.Platform, claOrg.OrgID); err != nil {
+		reason = err
+		statusCode = 403
+		return
+	}
+
 	pdf, err := models.DownloadOrgSignature(claOrgID)
 	if err != nil {
 		reason = err
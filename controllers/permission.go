@@ -0,0 +1,346 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/astaxie/beego/context"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	// tokenNone means the api is public and no token is required.
+	tokenNone = iota
+	// tokenBasic means the api requires the token of accessController.
+	tokenBasic
+	// tokenCodePlatform means the api requires the token of codePlatformAuth.
+	tokenCodePlatform
+)
+
+type routePermission struct {
+	method      string
+	pattern     string
+	permissions []string
+	tokenType   int
+
+	// tokenOptional means the api can be accessed without token,
+	// but the token will be checked if it is passed.
+	tokenOptional bool
+}
+
+func (this routePermission) key() string {
+	return routeKey(this.method, this.pattern)
+}
+
+func (this routePermission) newAccessController() accessControllerInterface {
+	if this.tokenType == tokenCodePlatform {
+		return &codePlatformAuth{}
+	}
+	return &accessController{}
+}
+
+func (this routePermission) validate() error {
+	if this.tokenType == tokenNone {
+		if len(this.permissions) != 0 || this.tokenOptional {
+			return fmt.Errorf("public api can't set permissions")
+		}
+		return nil
+	}
+
+	if this.tokenType != tokenBasic && this.tokenType != tokenCodePlatform {
+		return fmt.Errorf("unknown token type: %d", this.tokenType)
+	}
+
+	if len(this.permissions) == 0 {
+		return fmt.Errorf("missing permissions")
+	}
+
+	for _, p := range this.permissions {
+		switch p {
+		case PermissionOwnerOfOrg, PermissionIndividualSigner,
+			PermissionCorporAdmin, PermissionEmployeeManager:
+		default:
+			return fmt.Errorf("unknown permission: %s", p)
+		}
+	}
+	return nil
+}
+
+func public(method, pattern string) routePermission {
+	return routePermission{method: method, pattern: pattern, tokenType: tokenNone}
+}
+
+func basic(method, pattern string, permissions ...string) routePermission {
+	return routePermission{
+		method:      method,
+		pattern:     pattern,
+		permissions: permissions,
+		tokenType:   tokenBasic,
+	}
+}
+
+// routePermissions is the permission table of all the apis.
+// The api which is not in this table is not allowed to access.
+var routePermissions = []routePermission{
+	// audit log
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/audit-log/:platform/:org_id",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	basic(http.MethodGet, "/v1/audit-log/", PermissionCorporAdmin),
+
+	// auth
+	public(http.MethodGet, "/v1/auth/:platform/:purpose"),
+	// beego doesn't add the prefix of namespace to the pattern which starts with it,
+	// so the pattern of /v1/auth/authcodeurl/:platform/:purpose is the one below.
+	public(http.MethodGet, "/v1/authcodeurl/:platform/:purpose"),
+	public(http.MethodPost, "/v1/auth/refresh"),
+	basic(
+		http.MethodPost, "/v1/auth/logout",
+		PermissionOwnerOfOrg, PermissionIndividualSigner,
+		PermissionCorporAdmin, PermissionEmployeeManager,
+	),
+
+	// cla
+	basic(http.MethodPost, "/v1/cla/", PermissionOwnerOfOrg),
+	basic(http.MethodDelete, "/v1/cla/:uid", PermissionOwnerOfOrg),
+	basic(http.MethodGet, "/v1/cla/:uid", PermissionOwnerOfOrg),
+	basic(http.MethodGet, "/v1/cla/", PermissionOwnerOfOrg),
+
+	// cla org
+	{
+		method:      http.MethodPost,
+		pattern:     "/v1/cla-org/",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodDelete,
+		pattern:     "/v1/cla-org/:uid",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/cla-org/:platform/:org_id",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		// the individual signer passes the token, but the corporation doesn't.
+		method:        http.MethodGet,
+		pattern:       "/v1/cla-org/:platform/:org_id/:apply_to",
+		permissions:   []string{PermissionIndividualSigner},
+		tokenType:     tokenBasic,
+		tokenOptional: true,
+	},
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/cla-org/blank-pdf/:cla_org_id",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodPost,
+		pattern:     "/v1/cla-org/:uid/version",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/cla-org/:uid/outdated-signing",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+
+	// corporation domain
+	basic(http.MethodPost, "/v1/corporation-domain/", PermissionCorporAdmin),
+	basic(http.MethodGet, "/v1/corporation-domain/", PermissionCorporAdmin),
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/corporation-domain/:cla_org_id",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodPut,
+		pattern:     "/v1/corporation-domain/:cla_org_id/:domain",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+
+	// corporation manager
+	public(http.MethodPost, "/v1/corporation-manager/auth"),
+	{
+		method:      http.MethodPut,
+		pattern:     "/v1/corporation-manager/:cla_org_id/:email",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	basic(http.MethodPatch, "/v1/corporation-manager/", PermissionCorporAdmin, PermissionEmployeeManager),
+
+	// corporation signing
+	public(http.MethodPost, "/v1/corporation-signing/:cla_org_id"),
+	public(http.MethodPut, "/v1/corporation-signing/:cla_org_id/:email"),
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/corporation-signing/",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodPatch,
+		pattern:     "/v1/corporation-signing/:cla_org_id/:email",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodPost,
+		pattern:     "/v1/corporation-signing/:cla_org_id/:email/review",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		// the token of corporation administrator is parsed as the one of code platform too.
+		method:      http.MethodGet,
+		pattern:     "/v1/corporation-signing/:cla_org_id/:email",
+		permissions: []string{PermissionOwnerOfOrg, PermissionCorporAdmin},
+		tokenType:   tokenCodePlatform,
+	},
+
+	// email
+	public(http.MethodGet, "/v1/email/auth/:platform"),
+	basic(http.MethodGet, "/v1/email/platforms", PermissionOwnerOfOrg),
+
+	// employee manager
+	basic(http.MethodPost, "/v1/employee-manager/", PermissionCorporAdmin),
+	basic(http.MethodDelete, "/v1/employee-manager/", PermissionCorporAdmin),
+	basic(http.MethodGet, "/v1/employee-manager/", PermissionCorporAdmin),
+
+	// employee signing
+	basic(http.MethodPost, "/v1/employee-signing/:cla_org_id", PermissionIndividualSigner),
+	basic(http.MethodGet, "/v1/employee-signing/", PermissionEmployeeManager),
+	basic(http.MethodPut, "/v1/employee-signing/:cla_org_id/:email", PermissionEmployeeManager),
+	basic(http.MethodDelete, "/v1/employee-signing/:cla_org_id/:email", PermissionEmployeeManager),
+
+	// individual signing
+	basic(http.MethodPost, "/v1/individual-signing/:cla_org_id", PermissionIndividualSigner),
+	public(http.MethodGet, "/v1/individual-signing/:platform/:org/:repo"),
+	basic(http.MethodGet, "/v1/individual-signing/", PermissionIndividualSigner),
+	basic(http.MethodDelete, "/v1/individual-signing/:cla_org_id", PermissionIndividualSigner),
+	basic(http.MethodPut, "/v1/individual-signing/:cla_org_id/:email", PermissionIndividualSigner),
+
+	// org signature
+	{
+		method:      http.MethodPost,
+		pattern:     "/v1/org-signature/:cla_org_id",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/org-signature/:cla_org_id",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+	basic(http.MethodGet, "/v1/org-signature/blank/:language", PermissionOwnerOfOrg),
+
+	// robot
+	public(http.MethodPost, "/v1/robot/:platform"),
+
+	// signing export
+	{
+		method:      http.MethodGet,
+		pattern:     "/v1/signing-export/:platform/:org_id",
+		permissions: []string{PermissionOwnerOfOrg},
+		tokenType:   tokenCodePlatform,
+	},
+}
+
+var routePermissionIndex = map[string]routePermission{}
+
+func init() {
+	if err := buildRoutePermissionIndex(routePermissions, routePermissionIndex); err != nil {
+		panic(err)
+	}
+}
+
+func routeKey(method, pattern string) string {
+	return strings.ToUpper(method) + " " + pattern
+}
+
+func buildRoutePermissionIndex(items []routePermission, index map[string]routePermission) error {
+	for _, item := range items {
+		k := item.key()
+
+		if err := item.validate(); err != nil {
+			return fmt.Errorf("invalid permission of api(%s): %s", k, err.Error())
+		}
+
+		if _, ok := index[k]; ok {
+			return fmt.Errorf("duplicate permission of api(%s)", k)
+		}
+		index[k] = item
+	}
+	return nil
+}
+
+func findRoutePermission(method, pattern string) (routePermission, bool) {
+	v, ok := routePermissionIndex[routeKey(method, pattern)]
+	return v, ok
+}
+
+// checkRoutePermission returns the access controller if the token passes the check.
+// The access controller is nil if the api doesn't require token.
+func checkRoutePermission(method, pattern, token string) (accessControllerInterface, int, string, error) {
+	rp, ok := findRoutePermission(method, pattern)
+	if !ok {
+		return nil, 403, util.ErrInvalidToken, fmt.Errorf("the api is not allowed to access")
+	}
+
+	if rp.tokenType == tokenNone || (token == "" && rp.tokenOptional) {
+		return nil, 0, "", nil
+	}
+
+	ac := rp.newAccessController()
+	if statusCode, errCode, err := checkApiAccessToken(token, rp.permissions, ac); err != nil {
+		return nil, statusCode, errCode, err
+	}
+	return ac, 0, "", nil
+}
+
+// requestMethod returns the method which beego uses to dispatch the request.
+func requestMethod(ctx *context.Context) string {
+	m := ctx.Request.Method
+	if m == http.MethodPost {
+		switch ctx.Input.Query("_method") {
+		case http.MethodPut:
+			return http.MethodPut
+		case http.MethodDelete:
+			return http.MethodDelete
+		}
+	}
+	return m
+}
+
+// CheckAPIPermission is the filter which checks the token of api by the permission table.
+func CheckAPIPermission(ctx *context.Context) {
+	pattern, _ := ctx.Input.GetData("RouterPattern").(string)
+
+	ac, statusCode, errCode, err := checkRoutePermission(
+		requestMethod(ctx), pattern, ctx.Input.Header(headerToken),
+	)
+	if err != nil {
+		ctx.Output.SetStatus(statusCode)
+		ctx.Output.JSON(buildErrorResponse(errCode, err), false, false)
+		return
+	}
+
+	if ac != nil {
+		ctx.Input.SetData(apiAccessController, ac)
+	}
+}
@@ -0,0 +1,201 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/controllers"
+	_ "github.com/opensourceways/app-cla-server/routers"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type registeredRoute struct {
+	method     string
+	pattern    string
+	controller string
+	// handlers is the map of http method to the method of controller, like map[GET:Get]
+	handlers string
+}
+
+func (this registeredRoute) hasHandler(handler string) bool {
+	for _, item := range strings.Fields(strings.Trim(this.handlers, "map[]")) {
+		if item == handler {
+			return true
+		}
+	}
+	return false
+}
+
+// registeredRoutes returns the apis which are registered to beego, and the
+// patterns of them are the ones which are checked by the permission filter.
+func registeredRoutes(t *testing.T) []registeredRoute {
+	data, ok := beego.PrintTree()["Data"].(beego.M)
+	if !ok {
+		t.Fatal("can't fetch the routes of beego")
+	}
+
+	r := []registeredRoute{}
+	for method, v := range data {
+		items, ok := v.(*[][]string)
+		if !ok {
+			t.Fatalf("can't fetch the routes of method: %s", method)
+		}
+
+		for _, item := range *items {
+			if !strings.HasPrefix(item[0], "/v1/") {
+				continue
+			}
+			r = append(r, registeredRoute{
+				method:     method,
+				pattern:    item[0],
+				handlers:   item[1],
+				controller: item[2],
+			})
+		}
+	}
+	return r
+}
+
+func TestEveryRouteHasPermission(t *testing.T) {
+	routes := registeredRoutes(t)
+
+	for key, comments := range beego.GlobalControllerRouter {
+		controller := "controllers." + key[strings.LastIndex(key, ":")+1:]
+
+		for _, c := range comments {
+			for _, m := range c.AllowHTTPMethods {
+				method := strings.ToUpper(m)
+				handler := fmt.Sprintf("%s:%s", method, c.Method)
+
+				var route *registeredRoute
+				for i := range routes {
+					item := &routes[i]
+					if item.method == method && item.controller == controller && item.hasHandler(handler) {
+						route = item
+						break
+					}
+				}
+				if route == nil {
+					t.Errorf("the api(%s %s) of %s is not registered", method, c.Router, controller)
+					continue
+				}
+
+				if _, ok := controllers.FindRoutePermission(method, route.pattern); !ok {
+					t.Errorf("the api(%s %s) of %s has no permission", method, route.pattern, controller)
+				}
+			}
+		}
+	}
+}
+
+func TestEveryPermissionHasRoute(t *testing.T) {
+	registered := map[string]bool{}
+	for _, item := range registeredRoutes(t) {
+		registered[item.method+" "+item.pattern] = true
+	}
+
+	for _, k := range controllers.RoutePermissionKeys() {
+		if !registered[k] {
+			t.Errorf("the permission of api(%s) is not used by any registered api", k)
+		}
+	}
+}
+
+// the owner of org must be checked by the token of code platform whether he/she
+// administers the org which the binding belongs to.
+func TestOwnerApisOfBindingRequireTokenOfCodePlatform(t *testing.T) {
+	for _, k := range controllers.RoutePermissionKeys() {
+		if !strings.Contains(k, ":cla_org_id") && !strings.Contains(k, "/v1/cla-org/:uid") {
+			continue
+		}
+
+		if owner, codePlatform := controllers.RouteOfOwner(k); owner && !codePlatform {
+			t.Errorf("the api(%s) of binding doesn't require the token of code platform", k)
+		}
+	}
+}
+
+func TestCheckRoutePermission(t *testing.T) {
+	cases := []struct {
+		name       string
+		method     string
+		pattern    string
+		statusCode int
+		errCode    string
+	}{
+		{
+			name:       "unknown api",
+			method:     http.MethodGet,
+			pattern:    "/v1/unknown",
+			statusCode: 403,
+			errCode:    util.ErrInvalidToken,
+		},
+		{
+			name:       "unknown method of api",
+			method:     http.MethodDelete,
+			pattern:    "/v1/auth/:platform/:purpose",
+			statusCode: 403,
+			errCode:    util.ErrInvalidToken,
+		},
+		{
+			name:    "public api",
+			method:  http.MethodGet,
+			pattern: "/v1/auth/:platform/:purpose",
+		},
+		{
+			name:    "token is optional",
+			method:  http.MethodGet,
+			pattern: "/v1/cla-org/:platform/:org_id/:apply_to",
+		},
+		{
+			name:       "missing basic token",
+			method:     http.MethodPost,
+			pattern:    "/v1/corporation-domain/",
+			statusCode: 401,
+			errCode:    util.ErrMissingToken,
+		},
+		{
+			name:       "disabled api",
+			method:     http.MethodGet,
+			pattern:    "/v1/email/authcodeurl/:platform",
+			statusCode: 403,
+			errCode:    util.ErrInvalidToken,
+		},
+		{
+			name:       "missing token of code platform",
+			method:     http.MethodGet,
+			pattern:    "/v1/audit-log/:platform/:org_id",
+			statusCode: 401,
+			errCode:    util.ErrMissingToken,
+		},
+	}
+
+	for _, c := range cases {
+		ac, statusCode, errCode, err := controllers.CheckRoutePermission(c.method, c.pattern, "")
+
+		if c.statusCode == 0 {
+			if err != nil {
+				t.Errorf("%s: expect allowed, but got error: %v", c.name, err)
+			}
+			if ac != nil {
+				t.Errorf("%s: expect no access controller", c.name)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("%s: expect denied, but it is allowed", c.name)
+			continue
+		}
+		if statusCode != c.statusCode || errCode != c.errCode {
+			t.Errorf(
+				"%s: expect %d(%s), but got %d(%s)",
+				c.name, c.statusCode, c.errCode, statusCode, errCode,
+			)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/robot"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type RobotController struct {
+	beego.Controller
+}
+
+// @Title Hook
+// @Description handle the event of pull request sent by the webhook of code platform
+// @Param	:platform	path 	string				true		"gitee/github"
+// @Success 202 {int} map
+// @Failure util.ErrNotSupportedPlatform
+// @router /:platform [post]
+func (this *RobotController) Hook() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+	var body interface{}
+
+	defer func() {
+		sendResponse(&this.Controller, statusCode, errCode, reason, body, "handle webhook event")
+	}()
+
+	platform, err := fetchStringParameter(&this.Controller, ":platform")
+	if err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	r, err := robot.GetRobot(platform)
+	if err != nil {
+		reason = err
+		errCode = util.ErrNotSupportedPlatform
+		statusCode = 400
+		return
+	}
+
+	header := this.Ctx.Request.Header.Clone()
+	payload := this.Ctx.Input.RequestBody
+
+	if err := r.VerifyWebhook(header, payload); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	body = "event accepted"
+
+	go func() {
+		if err := r.HandleEvent(header, payload); err != nil {
+			beego.Error(fmt.Sprintf("Failed to handle webhook event of %s: %s", platform, err.Error()))
+		}
+	}()
+}
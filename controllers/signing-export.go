@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/export"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+)
+
+type SigningExportController struct {
+	beego.Controller
+}
+
+// @Title Export
+// @Description export the individual, employee and corporation signings of org/repo
+// @Param	:platform	path 	string		true		"code platform"
+// @Param	:org_id		path 	string		true		"org"
+// @Param	repo_id		query 	string		false		"repo"
+// @Param	format		query 	string		false		"csv or xlsx, default is xlsx"
+// @Param	kind		query 	string		false		"individual, employee or corporation, it is required for csv"
+// @Success 200 {file} the exported file
+// @router /:platform/:org_id [get]
+func (this *SigningExportController) Export() {
+	var statusCode = 0
+	var errCode = ""
+	var reason error
+
+	defer func() {
+		// the response has been written if succeeded
+		if reason != nil {
+			sendResponse(&this.Controller, statusCode, errCode, reason, nil, "export signings")
+		}
+	}()
+
+	params := []string{":platform", ":org_id"}
+	if err := checkAPIStringParameter(&this.Controller, params); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	format := this.GetString("format", exportFormatXLSX)
+	kind := this.GetString("kind")
+	if err := checkExportParameter(format, kind); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 400
+		return
+	}
+
+	opt := models.SigningExportOption{
+		Platform: this.GetString(":platform"),
+		OrgID:    this.GetString(":org_id"),
+		RepoID:   this.GetString("repo_id"),
+	}
+
+	if err := checkOrgAdmin(&this.Controller, opt.Platform, opt.OrgID); err != nil {
+		reason = err
+		errCode = util.ErrInvalidParameter
+		statusCode = 403
+		return
+	}
+
+	tables, err := opt.Export()
+	if err != nil {
+		reason = err
+		return
+	}
+
+	name := opt.OrgID
+	if opt.RepoID != "" {
+		name = fmt.Sprintf("%s_%s", name, opt.RepoID)
+	}
+
+	w := this.Ctx.ResponseWriter
+	if format == exportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s_%s.csv", name, kind))
+
+		err = export.WriteCSV(w, findExportTable(tables, kind))
+	} else {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.xlsx", name))
+
+		err = export.WriteXLSX(w, tables)
+	}
+
+	// it is too late to send the error response after writing the data.
+	if err != nil {
+		beego.Error(fmt.Sprintf("export signings of %s/%s failed: %s", opt.Platform, name, err.Error()))
+	}
+}
+
+func checkExportParameter(format, kind string) error {
+	switch format {
+	case exportFormatXLSX:
+		return nil
+
+	case exportFormatCSV:
+		for _, item := range models.SigningKinds {
+			if item == kind {
+				return nil
+			}
+		}
+		return fmt.Errorf("kind must be one of %s", strings.Join(models.SigningKinds, ", "))
+
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+func findExportTable(tables []*export.Table, kind string) *export.Table {
+	for _, t := range tables {
+		if t.Name == kind {
+			return t
+		}
+	}
+	return &export.Table{Name: kind}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/astaxie/beego"
 
+	"github.com/opensourceways/app-cla-server/code-platform-auth/platforms"
 	"github.com/opensourceways/app-cla-server/conf"
 	"github.com/opensourceways/app-cla-server/dbmodels"
 	"github.com/opensourceways/app-cla-server/email"
@@ -37,22 +38,6 @@ func buildStatusAndErrCode(statusCode int, errCode string, reason error) (int, s
 }
 
 func sendResponse(c *beego.Controller, statusCode int, errCode string, reason error, body interface{}, doWhat string) {
-	if token, err := refreshAccessToken(c); err == nil {
-		// this code must run before `c.Ctx.ResponseWriter.WriteHeader`
-		// otherwise the header can't be set successfully.
-		// The reason is relevant to the variable of 'Response.Started' at
-		// beego/context/context.go
-		c.Ctx.Output.Header(headerToken, token)
-	}
-
-	f := func(data interface{}) {
-		c.Data["json"] = struct {
-			Data interface{} `json:"data"`
-		}{
-			Data: data,
-		}
-	}
-
 	if reason != nil {
 		statusCode, errCode := buildStatusAndErrCode(statusCode, errCode, reason)
 
@@ -63,37 +48,39 @@ func sendResponse(c *beego.Controller, statusCode int, errCode string, reason er
 			errCode = util.ErrSystemError
 		}
 
-		d := struct {
-			ErrCode string `json:"error_code"`
-			ErrMsg  string `json:"error_message"`
-		}{
-			ErrCode: fmt.Sprintf("cla.%s", errCode),
-			ErrMsg:  reason.Error(),
-		}
-
-		f(d)
+		c.Data["json"] = buildErrorResponse(errCode, reason)
 
 		// if success, don't set status code, otherwise the header set in c.ServeJSON
 		// will not work. The reason maybe the same as above.
 		c.Ctx.ResponseWriter.WriteHeader(statusCode)
 	} else {
 		if body != nil {
-			f(body)
+			c.Data["json"] = buildDataResponse(body)
 		}
 	}
 
 	c.ServeJSON()
 }
 
-func sendResponse1(c *beego.Controller, statusCode int, reason error, body interface{}) {
-	if token, err := refreshAccessToken(c); err == nil {
-		// this code must run before `c.Ctx.ResponseWriter.WriteHeader`
-		// otherwise the header can't be set successfully.
-		// The reason is relevant to the variable of 'Response.Started' at
-		// beego/context/context.go
-		c.Ctx.Output.Header(headerToken, token)
+func buildDataResponse(data interface{}) interface{} {
+	return struct {
+		Data interface{} `json:"data"`
+	}{
+		Data: data,
 	}
+}
 
+func buildErrorResponse(errCode string, reason error) interface{} {
+	return buildDataResponse(struct {
+		ErrCode string `json:"error_code"`
+		ErrMsg  string `json:"error_message"`
+	}{
+		ErrCode: fmt.Sprintf("cla.%s", errCode),
+		ErrMsg:  reason.Error(),
+	})
+}
+
+func sendResponse1(c *beego.Controller, statusCode int, reason error, body interface{}) {
 	f := func(data interface{}) {
 		c.Data["json"] = struct {
 			Data interface{} `json:"data"`
@@ -126,31 +113,7 @@ func getHeader(c *beego.Controller, h string) string {
 	return c.Ctx.Input.Header(h)
 }
 
-func newAccessToken(user, permission string) (string, error) {
-	ac := &accessController{
-		User:       user,
-		Permission: permission,
-		secret:     conf.AppConfig.APITokenKey,
-	}
-
-	return ac.NewToken(conf.AppConfig.APITokenExpiry)
-}
-
-func newAccessTokenAuthorizedByCodePlatform(user, permission, platformToken string) (string, error) {
-	ac := &codePlatformAuth{
-		accessController: accessController{
-			User:       user,
-			Permission: permission,
-			secret:     conf.AppConfig.APITokenKey,
-		},
-		PlatformToken: platformToken,
-	}
-
-	return ac.NewToken(conf.AppConfig.APITokenExpiry)
-}
-
-func checkApiAccessToken(c *beego.Controller, permission []string, ac accessControllerInterface) (int, string, error) {
-	token := getHeader(c, headerToken)
+func checkApiAccessToken(token string, permission []string, ac accessControllerInterface) (int, string, error) {
 	if token == "" {
 		return 401, util.ErrMissingToken, fmt.Errorf("no token passed")
 	}
@@ -165,19 +128,6 @@ func checkApiAccessToken(c *beego.Controller, permission []string, ac accessCont
 	return 0, "", nil
 }
 
-func apiPrepare(c *beego.Controller, permission []string, ac accessControllerInterface) {
-	if ac == nil {
-		ac = &accessController{}
-	}
-
-	if statusCode, errCode, err := checkApiAccessToken(c, permission, ac); err != nil {
-		sendResponse(c, statusCode, errCode, err, nil, "")
-		c.StopRun()
-	}
-
-	c.Data[apiAccessController] = ac
-}
-
 func getAccessController(c *beego.Controller) (accessControllerInterface, error) {
 	ac, ok := c.Data[apiAccessController]
 	if !ok {
@@ -199,12 +149,51 @@ func getApiAccessUser(c *beego.Controller) (string, error) {
 	return ac.GetUser(), nil
 }
 
-func refreshAccessToken(c *beego.Controller) (string, error) {
+func getApiAccessPermission(c *beego.Controller) (string, error) {
+	ac, err := getAccessController(c)
+	if err != nil {
+		return "", err
+	}
+	return ac.GetPermission(), nil
+}
+
+// getPlatformToken fetches the token of code platform from the session store.
+func getPlatformToken(c *beego.Controller) (string, error) {
 	ac, err := getAccessController(c)
 	if err != nil {
 		return "", err
 	}
-	return ac.NewToken(conf.AppConfig.APITokenExpiry)
+
+	v, ok := ac.(*codePlatformAuth)
+	if !ok {
+		return "", fmt.Errorf("the token is not authorized by code platform")
+	}
+	return v.GetPlatformToken()
+}
+
+// checkOrgAdmin checks whether the user is the administrator of org on the code platform.
+func checkOrgAdmin(c *beego.Controller, platform, org string) error {
+	token, err := getPlatformToken(c)
+	if err != nil {
+		return err
+	}
+
+	p, err := platforms.NewPlatform(token, "", platform)
+	if err != nil {
+		return err
+	}
+
+	orgs, err := p.ListOrg()
+	if err != nil {
+		return fmt.Errorf("failed to list orgs of user: %s", err.Error())
+	}
+
+	for _, item := range orgs {
+		if item == org {
+			return nil
+		}
+	}
+	return fmt.Errorf("not the administrator of org: %s", org)
 }
 
 func corporRoleToPermission(role string) string {
@@ -227,14 +216,6 @@ func actionToPermission(action string) string {
 	return ""
 }
 
-func getRouterPattern(c *beego.Controller) string {
-	v, ok := c.Data["RouterPattern"]
-	if ok {
-		return v.(string)
-	}
-	return ""
-}
-
 func checkAPIStringParameter(c *beego.Controller, params []string) error {
 	for _, p := range params {
 		if c.GetString(p) == "" {
@@ -287,17 +268,23 @@ func getEmailConfig(claOrgID string) (*models.CLAOrg, *models.OrgEmail, error) {
 	return claOrg, emailInfo, nil
 }
 
-func isSameCorp(email1, email2 string) bool {
-	return util.EmailSuffix(email1) == util.EmailSuffix(email2)
+// isSameCorp checks whether the email belongs to the corporation of corpEmail
+// which has signed on the binding of claOrgID.
+func isSameCorp(claOrgID, corpEmail, email string) (bool, error) {
+	return models.IsSameCorp(claOrgID, corpEmail, email)
 }
 
 func checkSameCorp(c *beego.Controller, email string) (int, string, error) {
-	_, corpEmail, err := parseCorpManagerUser(c)
+	claOrgID, corpEmail, err := parseCorpManagerUser(c)
 	if err != nil {
 		return 401, util.ErrUnknownToken, err
 	}
 
-	if !isSameCorp(corpEmail, email) {
+	same, err := isSameCorp(claOrgID, corpEmail, email)
+	if err != nil {
+		return 0, "", err
+	}
+	if !same {
 		return 400, util.ErrNotSameCorp, fmt.Errorf("not same corp")
 	}
 
@@ -336,10 +323,6 @@ func isNoClaBindingDoc(err error) bool {
 	return c == util.ErrNoCLABindingDoc
 }
 
-func getRequestMethod(c *beego.Controller) string {
-	return c.Ctx.Request.Method
-}
-
 func notifyCorpManagerWhenAdding(orgEmail, subject string, info []dbmodels.CorporationManagerCreateOption) {
 	for _, item := range info {
 		d := email.AddingCorpManager{
@@ -374,6 +357,37 @@ func notifyCorpManagerWhenRemoving(orgEmail string, info []string) {
 	}
 }
 
+// notifyCorpSigningStatus sends the current status of corp signing to its administrator.
+func notifyCorpSigningStatus(claOrgID, corpEmail string) {
+	claOrg := &models.CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		beego.Error(err)
+		return
+	}
+
+	detail, err := models.CheckCorporationSigning(claOrgID, corpEmail)
+	if err != nil {
+		beego.Error(err)
+		return
+	}
+
+	d := email.CorpSigningStatus{
+		CorporationName: detail.CorporationName,
+		Status:          detail.Status,
+		Comment:         detail.ReviewComment,
+	}
+	msg, err := d.GenEmailMsg()
+	if err != nil {
+		beego.Error(err)
+		return
+	}
+
+	msg.To = []string{detail.AdminEmail}
+	msg.Subject = "Status of Corporation Signing"
+
+	worker.GetEmailWorker().SendSimpleMessage(claOrg.OrgEmail, msg)
+}
+
 func sendVerificationCodeEmail(code, orgEmail, adminEmail string) {
 	d := email.CorpSigningVerificationCode{Code: code}
 	msg, err := d.GenEmailMsg()
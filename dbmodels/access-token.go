@@ -0,0 +1,20 @@
+package dbmodels
+
+type RefreshToken struct {
+	// ID is the hash of refresh token, the token itself is never stored.
+	ID         string
+	SessionID  string
+	User       string
+	Permission string
+	// SessionExpiry is the absolute deadline of session which can't be extended by refreshing.
+	SessionExpiry int64
+	Expiry        int64
+}
+
+// AccessSession keeps the sensitive data of session at server side.
+type AccessSession struct {
+	ID string
+	// PlatformToken is the encrypted access token of code platform.
+	PlatformToken []byte
+	Expiry        int64
+}
@@ -0,0 +1,33 @@
+package dbmodels
+
+// AuditLog is the record of administrative action, which can't be changed
+// or deleted once it is added.
+type AuditLog struct {
+	ID       string `json:"id"`
+	Actor    string `json:"actor"`
+	Action   string `json:"action"`
+	Platform string `json:"platform"`
+	OrgID    string `json:"org_id"`
+	RepoID   string `json:"repo_id,omitempty"`
+	CLAOrgID string `json:"cla_org_id"`
+	// CorpID is the email suffix of corporation which the action is on.
+	// It is empty if the action is not on any corporation.
+	CorpID   string            `json:"corp_id,omitempty"`
+	Target   string            `json:"target"`
+	Before   map[string]string `json:"before,omitempty"`
+	After    map[string]string `json:"after,omitempty"`
+	SourceIP string            `json:"source_ip"`
+	Time     int64             `json:"time"`
+}
+
+// AuditLogListOption lists the audit logs of org. The empty CorpID and Action
+// will not be used to filter.
+type AuditLogListOption struct {
+	Platform string
+	OrgID    string
+	CorpID   string
+	Action   string
+
+	// Page only supports sorting by time.
+	Page PageOption
+}
@@ -12,6 +12,49 @@ type CLAOrg struct {
 	Enabled              bool   `json:"enabled"`
 	Submitter            string `json:"submitter" required:"true"`
 	OrgSignatureUploaded bool   `json:"org_signature_uploaded"`
+
+	// CLAVersion is the version of current cla. It increases when a new cla is published.
+	CLAVersion int `json:"cla_version"`
+	// ResignRequired means the signers of outdated version must sign the current version
+	// after ResignDeadline which is a unix timestamp.
+	ResignRequired bool         `json:"resign_required"`
+	ResignDeadline int64        `json:"resign_deadline"`
+	CLAVersions    []CLAVersion `json:"cla_versions,omitempty"`
+}
+
+type CLAVersion struct {
+	Version     int    `json:"version"`
+	CLAID       string `json:"cla_id"`
+	PublishedAt int64  `json:"published_at"`
+}
+
+type CLAVersionPublishOption struct {
+	CLAID          string `json:"cla_id"`
+	ResignRequired bool   `json:"resign_required"`
+	ResignDeadline int64  `json:"resign_deadline"`
+}
+
+// SigningVersion is the version of cla which the individual or corporation signed.
+type SigningVersion struct {
+	CLAOrgID       string `json:"cla_org_id"`
+	Version        int    `json:"version"`
+	CLAVersion     int    `json:"cla_version"`
+	ResignRequired bool   `json:"resign_required"`
+	ResignDeadline int64  `json:"resign_deadline"`
+}
+
+func (this SigningVersion) IsOutdated() bool {
+	return this.Version < this.CLAVersion
+}
+
+// OutdatedSignings contains one page of the outdated individual and corporation
+// signings respectively and the total number of each of them.
+type OutdatedSignings struct {
+	CLAVersion       int                           `json:"cla_version"`
+	Individuals      []IndividualSigningBasicInfo  `json:"individuals"`
+	IndividualTotal  int                           `json:"individual_total"`
+	Corporations     []CorporationSigningBasicInfo `json:"corporations"`
+	CorporationTotal int                           `json:"corporation_total"`
 }
 
 type CLAOrgListOption struct {
@@ -19,4 +62,7 @@ type CLAOrgListOption struct {
 	OrgID    string `json:"org_id"`
 	RepoID   string `json:"repo_id"`
 	ApplyTo  string `json:"apply_to"`
+
+	// Page is only used to list bindings and only supports sorting by date.
+	Page PageOption `json:"-"`
 }
@@ -0,0 +1,24 @@
+package dbmodels
+
+const (
+	CorpDomainStatusPending  = "pending"
+	CorpDomainStatusApproved = "approved"
+	CorpDomainStatusRejected = "rejected"
+)
+
+// CorporationDomain is an email domain owned by the corporation besides the
+// one of its administrator, which is the id of corporation. It is verified
+// only after it is approved by the org owner.
+type CorporationDomain struct {
+	CorpID    string `json:"corp_id"`
+	Domain    string `json:"domain"`
+	Status    string `json:"status"`
+	Requester string `json:"requester"`
+	Date      string `json:"date"`
+}
+
+type CorporationDomainListOption struct {
+	// CorpID and Status match all if they are empty.
+	CorpID string
+	Status string
+}
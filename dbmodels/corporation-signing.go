@@ -7,6 +7,46 @@ type CorporationSigningBasicInfo struct {
 	AdminName       string `json:"admin_name"`
 	CorporationName string `json:"corporation_name"`
 	Date            string `json:"date"`
+	// CLAVersion is the version of cla signed, it is set by server.
+	CLAVersion int `json:"cla_version"`
+}
+
+const (
+	CorpSigningStatusSubmitted    = "submitted"
+	CorpSigningStatusPDFUploaded  = "pdf_uploaded"
+	CorpSigningStatusUnderReview  = "under_review"
+	CorpSigningStatusApproved     = "approved"
+	CorpSigningStatusRejected     = "rejected"
+	CorpSigningStatusAdminCreated = "admin_created"
+)
+
+// corpSigningTransitions maps the status of corporation signing
+// to the ones which it can be changed from.
+var corpSigningTransitions = map[string][]string{
+	CorpSigningStatusPDFUploaded: {
+		CorpSigningStatusSubmitted,
+		CorpSigningStatusPDFUploaded,
+		CorpSigningStatusRejected,
+	},
+	CorpSigningStatusUnderReview:  {CorpSigningStatusPDFUploaded},
+	CorpSigningStatusApproved:     {CorpSigningStatusUnderReview},
+	CorpSigningStatusRejected:     {CorpSigningStatusUnderReview},
+	CorpSigningStatusAdminCreated: {CorpSigningStatusApproved},
+}
+
+// CorpSigningStatusFrom returns the statuses which can be changed to status.
+func CorpSigningStatusFrom(status string) []string {
+	return corpSigningTransitions[status]
+}
+
+// CanChangeCorpSigningStatus checks whether the status can be changed from 'from' to 'to'.
+func CanChangeCorpSigningStatus(from, to string) bool {
+	for _, item := range corpSigningTransitions[to] {
+		if item == from {
+			return true
+		}
+	}
+	return false
 }
 
 type CorporationSigningDetail struct {
@@ -14,6 +54,24 @@ type CorporationSigningDetail struct {
 
 	PDFUploaded bool `json:"pdf_uploaded"`
 	AdminAdded  bool `json:"admin_added"`
+
+	Status string `json:"status"`
+	// ReviewComment is the comment of the latest review.
+	ReviewComment string `json:"review_comment,omitempty"`
+}
+
+// CorporationSigningStatusUpdate changes the status of corporation signing
+// and replaces the review comment.
+type CorporationSigningStatusUpdate struct {
+	Status  string
+	Comment string
+}
+
+// CorporationSigningFullInfo includes all the information of corporation signing except pdf.
+type CorporationSigningFullInfo struct {
+	CorporationSigningDetail
+
+	Info TypeSigningInfo `json:"info"`
 }
 
 type CorporationSigningInfo struct {
@@ -27,4 +85,16 @@ type CorporationSigningListOption struct {
 	OrgID       string `json:"org_id"`
 	RepoID      string `json:"repo_id"`
 	CLALanguage string `json:"cla_language"`
+
+	// Filter.Enabled matches whether the administrator of corporation has been added.
+	Filter SigningFilter `json:"-"`
+	// Status matches the status of signing if it is set.
+	Status string     `json:"-"`
+	Page   PageOption `json:"-"`
+}
+
+type CorporationSigningListItem struct {
+	CLAOrgID string `json:"cla_org_id"`
+
+	CorporationSigningDetail
 }
@@ -13,21 +13,38 @@ func GetDB() IDB {
 type IDB interface {
 	ICorporationSigning
 	ICorporationManager
+	ICorporationDomain
 	IOrgEmail
 	ICLAOrg
 	IIndividualSigning
 	ICLA
 	IVerifiCode
 	IPDF
+	IEmailOutbox
+	IAccessToken
+	IOAuthState
+	IAuditLog
 }
 
 type ICorporationSigning interface {
 	SignAsCorporation(claOrgID, platform, org, repo string, info CorporationSigningInfo) error
-	ListCorporationSigning(CorporationSigningListOption) (map[string][]CorporationSigningDetail, error)
+	ListCorporationSigning(CorporationSigningListOption) ([]CorporationSigningListItem, int, error)
+	ListCorporationSigningInfo(CorporationSigningListOption) (map[string][]CorporationSigningFullInfo, error)
 	GetCorporationSigningDetail(platform, org, repo, email string) (string, CorporationSigningDetail, error)
 	UploadCorporationSigningPDF(claOrgID, adminEmail string, pdf []byte) error
 	DownloadCorporationSigningPDF(claOrgID, email string) ([]byte, error)
 	CheckCorporationSigning(claOrgID, email string) (CorporationSigningDetail, error)
+	UpdateCorporationSigningStatus(claOrgID, email string, opt CorporationSigningStatusUpdate) error
+}
+
+type ICorporationDomain interface {
+	AddCorporationDomain(claOrgID string, info CorporationDomain) error
+	// UpdateCorporationDomainStatus approves or rejects the pending domain.
+	UpdateCorporationDomainStatus(claOrgID, domain, status string) error
+	ListCorporationDomain(claOrgID string, opt CorporationDomainListOption) ([]CorporationDomain, error)
+	// GetCorporationID returns the id of corporation which owns the approved domain
+	// of email, or the domain itself if it is not approved for any corporation.
+	GetCorporationID(claOrgID, email string) (string, error)
 }
 
 type ICorporationManager interface {
@@ -35,20 +52,25 @@ type ICorporationManager interface {
 	AddCorporationManager(claOrgID string, opt []CorporationManagerCreateOption, managerNumber int) ([]CorporationManagerCreateOption, error)
 	DeleteCorporationManager(claOrgID string, opt []CorporationManagerCreateOption) ([]string, error)
 	ResetCorporationManagerPassword(string, string, CorporationManagerResetPassword) error
-	ListCorporationManager(claOrgID, email, role string) ([]CorporationManagerListResult, error)
+	ListCorporationManager(claOrgID, email, role string, page PageOption) ([]CorporationManagerListResult, int, error)
 }
 
 type IOrgEmail interface {
 	CreateOrgEmail(opt OrgEmailCreateInfo) error
 	GetOrgEmailInfo(email string) (OrgEmailCreateInfo, error)
+	ListOrgEmails() ([]OrgEmailCreateInfo, error)
+	// UpdateOrgEmailToken updates the token only if it is still encrypted by the key of oldKeyID.
+	UpdateOrgEmailToken(email, oldKeyID string, info OrgEmailCreateInfo) error
 }
 
 type ICLAOrg interface {
-	ListBindingBetweenCLAAndOrg(CLAOrgListOption) ([]CLAOrg, error)
+	ListBindingBetweenCLAAndOrg(CLAOrgListOption) ([]CLAOrg, int, error)
 	ListBindingForSigningPage(CLAOrgListOption) ([]CLAOrg, error)
 	GetBindingBetweenCLAAndOrg(string) (CLAOrg, error)
 	CreateBindingBetweenCLAAndOrg(CLAOrg) (string, error)
 	DeleteBindingBetweenCLAAndOrg(string) error
+	PublishCLAVersion(claOrgID string, opt CLAVersionPublishOption) (int, error)
+	ListOutdatedSignings(claOrgID string, page PageOption) (OutdatedSignings, error)
 }
 
 type IIndividualSigning interface {
@@ -56,7 +78,12 @@ type IIndividualSigning interface {
 	DeleteIndividualSigning(claOrgID, email string) error
 	UpdateIndividualSigning(claOrgID, email string, enabled bool) error
 	IsIndividualSigned(platform, orgID, repoId, email string) (bool, error)
-	ListIndividualSigning(opt IndividualSigningListOption) (map[string][]IndividualSigningBasicInfo, error)
+	GetIndividualSigningVersion(platform, orgID, repoId, email string) (SigningVersion, error)
+	ListIndividualSigning(opt IndividualSigningListOption) ([]IndividualSigningListItem, int, error)
+	ListIndividualSigningInfo(opt IndividualSigningListOption) (map[string][]IndividualSigningInfo, error)
+	RevokeIndividualSigning(claOrgID string, opt IndividualSigningRevokeOption) error
+	ListIndividualSigningOfSigner(signer string) (map[string][]IndividualSigningBasicInfo, error)
+	ListIndividualSigningEvents(signer string) ([]IndividualSigningEvent, error)
 }
 
 type ICLA interface {
@@ -79,3 +106,41 @@ type IPDF interface {
 	UploadBlankSignature(language string, pdf []byte) error
 	DownloadBlankSignature(language string) ([]byte, error)
 }
+
+type IEmailOutbox interface {
+	AddEmailJob(EmailJob) (string, error)
+	// ClaimEmailJob picks a job which is due and leases it for lease seconds.
+	// A job whose lease is expired, such as the worker crashed, will be claimed again
+	// unless it has run out of attempts.
+	ClaimEmailJob(now, lease int64) (*EmailJob, error)
+	// FinishEmailJob, RetryEmailJob and KillEmailJob update the claimed job. They fail
+	// with ErrEmailJobLeaseLost if the job has been claimed by others after the lease.
+	FinishEmailJob(job *EmailJob) error
+	RetryEmailJob(job *EmailJob, nextRunAt int64, reason string) error
+	KillEmailJob(job *EmailJob, reason string) error
+	CountEmailJobs(status string) (int, error)
+}
+
+type IAccessToken interface {
+	AddRefreshToken(RefreshToken) error
+	// UseRefreshToken deletes the refresh token and returns it, so that it can only be used once.
+	UseRefreshToken(tokenID string) (RefreshToken, error)
+	DeleteRefreshTokensOfSession(sessionID string) error
+	RevokeAccessSession(sessionID string, expiry int64) error
+	IsAccessSessionRevoked(sessionID string) (bool, error)
+
+	SaveAccessSession(AccessSession) error
+	GetAccessSession(sessionID string) (AccessSession, error)
+	DeleteAccessSession(sessionID string) error
+}
+
+type IOAuthState interface {
+	AddOAuthState(OAuthState) error
+	// UseOAuthState deletes the state and returns it, so that it can only be used once.
+	UseOAuthState(state string) (OAuthState, error)
+}
+
+type IAuditLog interface {
+	AddAuditLog(AuditLog) error
+	ListAuditLog(AuditLogListOption) ([]AuditLog, int, error)
+}
@@ -0,0 +1,267 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func testCLA(t *testing.T, db dbmodels.IDB) {
+	id := createCLA(t, db, "cla1", dbmodels.ApplyToIndividual)
+
+	_, err := db.CreateCLA(dbmodels.CLA{
+		Name: "cla1", Text: "text", Submitter: "owner", Language: "chinese", ApplyTo: dbmodels.ApplyToIndividual,
+	})
+	mustFail(t, err)
+
+	cla, err := db.GetCLA(id)
+	mustNil(t, err)
+	mustEqual(t, "cla", cla, dbmodels.CLA{
+		ID:        id,
+		Name:      "cla1",
+		Text:      "text of cla1",
+		Language:  "english",
+		Submitter: "owner",
+		ApplyTo:   dbmodels.ApplyToIndividual,
+		Fields: []dbmodels.Field{
+			{ID: "1", Title: "name", Type: "string", Required: true},
+		},
+	})
+
+	clas, err := db.ListCLA(dbmodels.CLAListOptions{Submitter: "owner"})
+	mustNil(t, err)
+	mustEqual(t, "number of clas", len(clas), 1)
+
+	clas, err = db.ListCLA(dbmodels.CLAListOptions{Submitter: "owner", Language: "chinese"})
+	mustNil(t, err)
+	mustEqual(t, "number of chinese clas", len(clas), 0)
+
+	clas, err = db.ListCLAByIDs([]string{id, unknownID})
+	mustNil(t, err)
+	mustEqual(t, "number of clas by ids", len(clas), 1)
+
+	_, err = db.ListCLAByIDs([]string{"invalid id"})
+	mustErrCode(t, err, util.ErrInvalidParameter)
+
+	// the cla which is bound can't be deleted
+	_, err = db.CreateBindingBetweenCLAAndOrg(dbmodels.CLAOrg{
+		Platform: platform, OrgID: "org", CLAID: id, CLALanguage: "english",
+		ApplyTo: dbmodels.ApplyToIndividual, OrgEmail: "cla@org.com", Enabled: true, Submitter: "owner",
+	})
+	mustNil(t, err)
+	mustFail(t, db.DeleteCLA(id))
+
+	id2 := createCLA(t, db, "cla2", dbmodels.ApplyToIndividual)
+	mustNil(t, db.DeleteCLA(id2))
+
+	_, err = db.GetCLA(id2)
+	mustFail(t, err)
+}
+
+func testBinding(t *testing.T, db dbmodels.IDB) {
+	b1 := createBinding(t, db, "org", "", dbmodels.ApplyToIndividual)
+
+	binding, err := db.GetBindingBetweenCLAAndOrg(b1)
+	mustNil(t, err)
+	mustEqual(t, "enabled", binding.Enabled, true)
+	mustEqual(t, "cla version", binding.CLAVersion, 1)
+	mustEqual(t, "number of cla versions", len(binding.CLAVersions), 1)
+
+	// the org can't be bound the cla of same language twice
+	binding.ID = ""
+	_, err = db.CreateBindingBetweenCLAAndOrg(binding)
+	mustFail(t, err)
+
+	b2 := createBinding(t, db, "org", "repo", dbmodels.ApplyToIndividual)
+	b3 := createBinding(t, db, "org", "", dbmodels.ApplyToCorporation)
+	createBinding(t, db, "org1", "", dbmodels.ApplyToIndividual)
+
+	list := func(opt dbmodels.CLAOrgListOption) ([]string, int) {
+		t.Helper()
+
+		opt.Platform = platform
+		v, total, err := db.ListBindingBetweenCLAAndOrg(opt)
+		mustNil(t, err)
+
+		ids := make([]string, 0, len(v))
+		for _, item := range v {
+			ids = append(ids, item.ID)
+		}
+		return ids, total
+	}
+
+	ids, total := list(dbmodels.CLAOrgListOption{OrgID: "org"})
+	mustEqual(t, "all bindings", ids, []string{b1, b2, b3})
+	mustEqual(t, "total", total, 3)
+
+	ids, total = list(dbmodels.CLAOrgListOption{
+		OrgID: "org", Page: dbmodels.PageOption{Page: 2, PerPage: 2},
+	})
+	mustEqual(t, "second page", ids, []string{b3})
+	mustEqual(t, "total of page", total, 3)
+
+	ids, _ = list(dbmodels.CLAOrgListOption{OrgID: "org", ApplyTo: dbmodels.ApplyToCorporation})
+	mustEqual(t, "bindings of corporation", ids, []string{b3})
+
+	ids, _ = list(dbmodels.CLAOrgListOption{OrgID: "org", RepoID: "repo"})
+	mustEqual(t, "bindings of repo", ids, []string{b2})
+
+	signingPage := func(repo string) []string {
+		t.Helper()
+
+		v, err := db.ListBindingForSigningPage(dbmodels.CLAOrgListOption{
+			Platform: platform, OrgID: "org", RepoID: repo, ApplyTo: dbmodels.ApplyToIndividual,
+		})
+		mustNil(t, err)
+
+		ids := make([]string, 0, len(v))
+		for _, item := range v {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	mustEqual(t, "signing page of repo", signingPage("repo"), []string{b2})
+	mustEqual(t, "signing page of unbound repo", signingPage("repo1"), []string{b1})
+	mustEqual(t, "signing page of org", signingPage(""), []string{b1})
+
+	mustNil(t, db.DeleteBindingBetweenCLAAndOrg(b2))
+
+	binding, err = db.GetBindingBetweenCLAAndOrg(b2)
+	mustNil(t, err)
+	mustEqual(t, "enabled of deleted binding", binding.Enabled, false)
+
+	_, total = list(dbmodels.CLAOrgListOption{OrgID: "org"})
+	mustEqual(t, "total after deleting", total, 2)
+	mustEqual(t, "signing page after deleting", signingPage("repo"), []string{b1})
+
+	_, err = db.GetBindingBetweenCLAAndOrg(unknownID)
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+
+	_, err = db.GetBindingBetweenCLAAndOrg("invalid id")
+	mustErrCode(t, err, util.ErrInvalidParameter)
+}
+
+func testCLAVersion(t *testing.T, db dbmodels.IDB) {
+	b := createBinding(t, db, "org", "", dbmodels.ApplyToIndividual)
+
+	binding, err := db.GetBindingBetweenCLAAndOrg(b)
+	mustNil(t, err)
+
+	email := "a@x.com"
+	signer := "gitee/a"
+	mustNil(t, db.SignAsIndividual(b, platform, "org", "", individualSigning(email, signer, true)))
+
+	_, err = db.PublishCLAVersion(b, dbmodels.CLAVersionPublishOption{CLAID: binding.CLAID})
+	mustErrCode(t, err, util.ErrInvalidParameter)
+
+	claID := createCLA(t, db, "cla-v2", dbmodels.ApplyToIndividual)
+	deadline := util.Now() + 3600
+
+	v, err := db.PublishCLAVersion(b, dbmodels.CLAVersionPublishOption{
+		CLAID: claID, ResignRequired: true, ResignDeadline: deadline,
+	})
+	mustNil(t, err)
+	mustEqual(t, "new version", v, 2)
+
+	binding, err = db.GetBindingBetweenCLAAndOrg(b)
+	mustNil(t, err)
+	mustEqual(t, "cla of binding", binding.CLAID, claID)
+	mustEqual(t, "cla version of binding", binding.CLAVersion, 2)
+	mustEqual(t, "number of cla versions", len(binding.CLAVersions), 2)
+
+	sv, err := db.GetIndividualSigningVersion(platform, "org", "", email)
+	mustNil(t, err)
+	mustEqual(t, "signing version", sv, dbmodels.SigningVersion{
+		CLAOrgID:       b,
+		Version:        1,
+		CLAVersion:     2,
+		ResignRequired: true,
+		ResignDeadline: deadline,
+	})
+
+	outdated, err := db.ListOutdatedSignings(b, dbmodels.PageOption{})
+	mustNil(t, err)
+	mustEqual(t, "cla version of outdated", outdated.CLAVersion, 2)
+	mustEqual(t, "number of outdated individuals", len(outdated.Individuals), 1)
+	mustEqual(t, "total of outdated individuals", outdated.IndividualTotal, 1)
+	mustEqual(t, "number of outdated corporations", len(outdated.Corporations), 0)
+
+	outdated, err = db.ListOutdatedSignings(b, dbmodels.PageOption{Page: 2, PerPage: 1})
+	mustNil(t, err)
+	mustEqual(t, "number of outdated individuals in page 2", len(outdated.Individuals), 0)
+	mustEqual(t, "total of outdated individuals in page 2", outdated.IndividualTotal, 1)
+
+	// re-sign the new version
+	mustNil(t, db.SignAsIndividual(b, platform, "org", "", individualSigning(email, signer, true)))
+
+	sv, err = db.GetIndividualSigningVersion(platform, "org", "", email)
+	mustNil(t, err)
+	mustEqual(t, "version after re-signing", sv.Version, 2)
+
+	err = db.SignAsIndividual(b, platform, "org", "", individualSigning(email, signer, true))
+	mustErrCode(t, err, util.ErrHasSigned)
+
+	outdated, err = db.ListOutdatedSignings(b, dbmodels.PageOption{})
+	mustNil(t, err)
+	mustEqual(t, "number of outdated individuals after re-signing", len(outdated.Individuals), 0)
+
+	events, err := db.ListIndividualSigningEvents(signer)
+	mustNil(t, err)
+
+	actions := make([]string, 0, len(events))
+	for _, e := range events {
+		actions = append(actions, e.Action)
+	}
+	mustEqual(t, "actions", actions, []string{dbmodels.SigningEventSign, dbmodels.SigningEventResign})
+
+	_, err = db.PublishCLAVersion(unknownID, dbmodels.CLAVersionPublishOption{CLAID: claID})
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+}
+
+func testCorporationCLAVersion(t *testing.T, db dbmodels.IDB) {
+	b := createBinding(t, db, "org", "", dbmodels.ApplyToCorporation)
+
+	mustNil(t, db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp.com", "corp")))
+	mustNil(t, db.UploadCorporationSigningPDF(b, "a@corp.com", []byte("pdf of corp")))
+
+	claID := createCLA(t, db, "cla-v2", dbmodels.ApplyToCorporation)
+	_, err := db.PublishCLAVersion(b, dbmodels.CLAVersionPublishOption{CLAID: claID, ResignRequired: true})
+	mustNil(t, err)
+
+	outdated, err := db.ListOutdatedSignings(b, dbmodels.PageOption{})
+	mustNil(t, err)
+	mustEqual(t, "number of outdated corporations", len(outdated.Corporations), 1)
+
+	// only the administrator can re-sign for the corporation
+	err = db.SignAsCorporation(b, platform, "org", "", corpSigning("b@corp.com", "corp"))
+	mustErrCode(t, err, util.ErrHasSigned)
+
+	// re-sign the new version
+	mustNil(t, db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp.com", "corp-v2")))
+
+	detail, err := db.CheckCorporationSigning(b, "a@corp.com")
+	mustNil(t, err)
+	mustEqual(t, "signing after re-signing", detail, dbmodels.CorporationSigningDetail{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			AdminEmail:      "a@corp.com",
+			AdminName:       "admin of corp-v2",
+			CorporationName: "corp-v2",
+			Date:            util.Date(),
+			CLAVersion:      2,
+		},
+		Status: dbmodels.CorpSigningStatusSubmitted,
+	})
+
+	// the pdf of old version must be uploaded again
+	_, err = db.DownloadCorporationSigningPDF(b, "a@corp.com")
+	mustErrCode(t, err, util.ErrPDFHasNotUploaded)
+
+	err = db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp.com", "corp-v2"))
+	mustErrCode(t, err, util.ErrHasSigned)
+
+	outdated, err = db.ListOutdatedSignings(b, dbmodels.PageOption{})
+	mustNil(t, err)
+	mustEqual(t, "number of outdated corporations after re-signing", len(outdated.Corporations), 0)
+}
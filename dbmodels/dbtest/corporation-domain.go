@@ -0,0 +1,102 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func testCorporationDomain(t *testing.T, db dbmodels.IDB) {
+	b := createBinding(t, db, "org", "", dbmodels.ApplyToCorporation)
+	mustNil(t, db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp.com", "corp")))
+	mustNil(t, db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp2.com", "corp2")))
+
+	domain := func(corpID, d string) dbmodels.CorporationDomain {
+		return dbmodels.CorporationDomain{
+			CorpID:    corpID,
+			Domain:    d,
+			Status:    dbmodels.CorpDomainStatusPending,
+			Requester: "a@" + corpID,
+			Date:      util.Date(),
+		}
+	}
+
+	mustNil(t, db.AddCorporationDomain(b, domain("corp.com", "corp1.com")))
+
+	// the domain can't be requested twice, or be the one of a signed corporation.
+	mustErrCode(t, db.AddCorporationDomain(b, domain("corp2.com", "corp1.com")), util.ErrCorpDomainExists)
+	mustErrCode(t, db.AddCorporationDomain(b, domain("corp.com", "corp.com")), util.ErrCorpDomainExists)
+	mustErrCode(t, db.AddCorporationDomain(b, domain("corp.com", "corp2.com")), util.ErrCorpDomainExists)
+	mustErrCode(t, db.AddCorporationDomain(b, domain("corp3.com", "corp4.com")), util.ErrHasNotSigned)
+	mustErrCode(t, db.AddCorporationDomain(unknownID, domain("corp.com", "corp4.com")), util.ErrNoCLABindingDoc)
+
+	// the pending domain is not resolved
+	id, err := db.GetCorporationID(b, "someone@corp1.com")
+	mustNil(t, err)
+	mustEqual(t, "corp id of pending domain", id, "corp1.com")
+
+	_, _, err = db.GetCorporationSigningDetail(platform, "org", "", "someone@corp1.com")
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	mustNil(t, db.UpdateCorporationDomainStatus(b, "corp1.com", dbmodels.CorpDomainStatusApproved))
+	err = db.UpdateCorporationDomainStatus(b, "corp1.com", dbmodels.CorpDomainStatusRejected)
+	mustErrCode(t, err, util.ErrNoPendingCorpDomain)
+
+	id, err = db.GetCorporationID(b, "someone@corp1.com")
+	mustNil(t, err)
+	mustEqual(t, "corp id of approved domain", id, "corp.com")
+
+	_, detail, err := db.GetCorporationSigningDetail(platform, "org", "", "someone@corp1.com")
+	mustNil(t, err)
+	mustEqual(t, "signing resolved by domain", detail.AdminEmail, "a@corp.com")
+
+	// the corporation of approved domain can't sign again
+	err = db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp1.com", "corp1"))
+	mustErrCode(t, err, util.ErrHasSigned)
+
+	// the rejected domain can be requested again
+	mustNil(t, db.AddCorporationDomain(b, domain("corp2.com", "corp5.com")))
+	mustNil(t, db.UpdateCorporationDomainStatus(b, "corp5.com", dbmodels.CorpDomainStatusRejected))
+	mustNil(t, db.AddCorporationDomain(b, domain("corp.com", "corp5.com")))
+
+	list := func(opt dbmodels.CorporationDomainListOption) []string {
+		t.Helper()
+
+		v, err := db.ListCorporationDomain(b, opt)
+		mustNil(t, err)
+
+		r := make([]string, 0, len(v))
+		for _, item := range v {
+			r = append(r, item.CorpID+"/"+item.Domain+"/"+item.Status)
+		}
+		return r
+	}
+
+	mustEqual(t, "domains of corp", list(dbmodels.CorporationDomainListOption{CorpID: "corp.com"}), []string{
+		"corp.com/corp1.com/approved", "corp.com/corp5.com/pending",
+	})
+	mustEqual(t, "pending domains", list(dbmodels.CorporationDomainListOption{
+		Status: dbmodels.CorpDomainStatusPending,
+	}), []string{"corp.com/corp5.com/pending"})
+
+	_, err = db.ListCorporationDomain(unknownID, dbmodels.CorporationDomainListOption{})
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+
+	// the employees of all the domains are listed
+	ib := createBinding(t, db, "org", "", dbmodels.ApplyToIndividual)
+	for _, email := range []string{"e@corp.com", "e@corp1.com", "e@corp2.com"} {
+		mustNil(t, db.SignAsIndividual(ib, platform, "org", "", individualSigning(email, "", true)))
+	}
+
+	v, total, err := db.ListIndividualSigning(dbmodels.IndividualSigningListOption{
+		Platform:           platform,
+		OrgID:              "org",
+		CorporationEmail:   "a@corp.com",
+		CorporationDomains: []string{"corp1.com"},
+		Page:               dbmodels.PageOption{SortBy: dbmodels.SortByEmail},
+	})
+	mustNil(t, err)
+	mustEqual(t, "number of employees", total, 2)
+	mustEqual(t, "employees", []string{v[0].Email, v[1].Email}, []string{"e@corp.com", "e@corp1.com"})
+}
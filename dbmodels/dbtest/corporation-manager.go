@@ -0,0 +1,126 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func testCorporationManager(t *testing.T, db dbmodels.IDB) {
+	b := createBinding(t, db, "org", "", dbmodels.ApplyToCorporation)
+	mustNil(t, db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp.com", "corp")))
+
+	admin := []dbmodels.CorporationManagerCreateOption{
+		{Role: dbmodels.RoleAdmin, Email: "a@corp.com", Password: "pw"},
+	}
+
+	added, err := db.AddCorporationManager(b, admin, 1)
+	mustNil(t, err)
+	mustEqual(t, "number of added admin", len(added), 1)
+
+	detail, err := db.CheckCorporationSigning(b, "a@corp.com")
+	mustNil(t, err)
+	mustEqual(t, "admin added", detail.AdminAdded, true)
+	mustEqual(t, "status after adding admin", detail.Status, dbmodels.CorpSigningStatusAdminCreated)
+
+	added, err = db.AddCorporationManager(b, admin, 1)
+	mustNil(t, err)
+	mustEqual(t, "number of admin added twice", len(added), 0)
+
+	// it is all or nothing when adding managers
+	managers := []dbmodels.CorporationManagerCreateOption{
+		{Role: dbmodels.RoleManager, Email: "m1@corp.com", Password: "pw1"},
+		{Role: dbmodels.RoleManager, Email: "m2@corp.com", Password: "pw2"},
+	}
+
+	_, err = db.AddCorporationManager(b, managers, 1)
+	mustErrCode(t, err, util.ErrNumOfCorpManagersExceeded)
+
+	added, err = db.AddCorporationManager(b, managers, 5)
+	mustNil(t, err)
+	mustEqual(t, "number of added managers", len(added), 2)
+
+	list := func(role string, page dbmodels.PageOption) ([]string, int) {
+		t.Helper()
+
+		v, total, err := db.ListCorporationManager(b, "a@corp.com", role, page)
+		mustNil(t, err)
+
+		emails := make([]string, 0, len(v))
+		for _, item := range v {
+			emails = append(emails, item.Email)
+		}
+		return emails, total
+	}
+
+	emails, total := list(dbmodels.RoleManager, dbmodels.PageOption{})
+	mustEqual(t, "managers", emails, []string{"m1@corp.com", "m2@corp.com"})
+	mustEqual(t, "total", total, 2)
+
+	emails, total = list(dbmodels.RoleManager, dbmodels.PageOption{Page: 2, PerPage: 1})
+	mustEqual(t, "second page of managers", emails, []string{"m2@corp.com"})
+	mustEqual(t, "total of page", total, 2)
+
+	_, total = list("", dbmodels.PageOption{})
+	mustEqual(t, "total of all roles", total, 3)
+
+	r, err := db.CheckCorporationManagerExist(dbmodels.CorporationManagerCheckInfo{
+		User: "m1@corp.com", Password: "pw1",
+	})
+	mustNil(t, err)
+	mustEqual(t, "login", r, map[string][]dbmodels.CorporationManagerCheckResult{
+		b: {{
+			Role:     dbmodels.RoleManager,
+			Email:    "m1@corp.com",
+			Platform: platform,
+			OrgID:    "org",
+		}},
+	})
+
+	r, err = db.CheckCorporationManagerExist(dbmodels.CorporationManagerCheckInfo{
+		User: "m1@corp.com", Password: "pw2",
+	})
+	mustNil(t, err)
+	mustEqual(t, "login with wrong password", len(r), 0)
+
+	err = db.ResetCorporationManagerPassword(b, "m1@corp.com", dbmodels.CorporationManagerResetPassword{
+		OldPassword: "pw2", NewPassword: "new",
+	})
+	mustErrCode(t, err, util.ErrInvalidParameter)
+
+	mustNil(t, db.ResetCorporationManagerPassword(b, "m1@corp.com", dbmodels.CorporationManagerResetPassword{
+		OldPassword: "pw1", NewPassword: "new",
+	}))
+
+	r, err = db.CheckCorporationManagerExist(dbmodels.CorporationManagerCheckInfo{
+		User: "m1@corp.com", Password: "new",
+	})
+	mustNil(t, err)
+	mustEqual(t, "number of login after resetting", len(r[b]), 1)
+	mustEqual(t, "initial password changed", r[b][0].InitialPWChanged, true)
+
+	deleted, err := db.DeleteCorporationManager(b, []dbmodels.CorporationManagerCreateOption{
+		{Role: dbmodels.RoleManager, Email: "m1@corp.com"},
+		{Role: dbmodels.RoleManager, Email: "m3@corp.com"},
+	})
+	mustNil(t, err)
+	mustEqual(t, "deleted managers", deleted, []string{"m1@corp.com"})
+
+	emails, _ = list(dbmodels.RoleManager, dbmodels.PageOption{})
+	mustEqual(t, "managers after deleting", emails, []string{"m2@corp.com"})
+
+	// the admin can't be added if the corporation has not signed,
+	// and nothing is changed.
+	_, err = db.AddCorporationManager(b, []dbmodels.CorporationManagerCreateOption{
+		{Role: dbmodels.RoleAdmin, Email: "a@corp1.com", Password: "pw"},
+	}, 1)
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	_, total, err = db.ListCorporationManager(b, "a@corp1.com", "", dbmodels.PageOption{})
+	mustNil(t, err)
+	mustEqual(t, "managers of unsigned corporation", total, 0)
+
+	_, _, err = db.ListCorporationManager(unknownID, "a@corp.com", "", dbmodels.PageOption{})
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+}
@@ -0,0 +1,163 @@
+// Package dbtest is the contract which all the implementations of dbmodels.IDB must satisfy.
+// An implementation runs it in its own test like this:
+//
+//	func TestContract(t *testing.T) {
+//		dbtest.Run(t, func(t *testing.T) dbmodels.IDB { return memorydb.NewDB() })
+//	}
+package dbtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	platform = "gitee"
+	// unknownID is a valid id which never exists.
+	unknownID = "5f5f5f5f5f5f5f5f5f5f5f5f"
+)
+
+type testCase struct {
+	name string
+	f    func(t *testing.T, db dbmodels.IDB)
+}
+
+var testCases = []testCase{
+	{"CLA", testCLA},
+	{"Binding", testBinding},
+	{"CLAVersion", testCLAVersion},
+	{"CorporationCLAVersion", testCorporationCLAVersion},
+	{"IndividualSigning", testIndividualSigning},
+	{"IndividualSigningOfRepo", testIndividualSigningOfRepo},
+	{"CorporationSigning", testCorporationSigning},
+	{"CorporationManager", testCorporationManager},
+	{"CorporationDomain", testCorporationDomain},
+	{"VerificationCode", testVerificationCode},
+	{"OrgEmail", testOrgEmail},
+	{"PDF", testPDF},
+	{"EmailOutbox", testEmailOutbox},
+	{"AccessToken", testAccessToken},
+	{"OAuthState", testOAuthState},
+	{"AuditLog", testAuditLog},
+}
+
+// Run runs all the cases of contract. newDB must return an empty database
+// every time, because each case is run on its own database.
+func Run(t *testing.T, newDB func(t *testing.T) dbmodels.IDB) {
+	for _, tc := range testCases {
+		f := tc.f
+		t.Run(tc.name, func(t *testing.T) {
+			f(t, newDB(t))
+		})
+	}
+}
+
+func mustNil(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func mustFail(t *testing.T, err error) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatal("expect an error, but got nil")
+	}
+}
+
+func mustErrCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("expect error of %s, but got nil", code)
+	}
+
+	e, ok := dbmodels.IsDBError(err)
+	if !ok {
+		t.Fatalf("expect error of %s, but got: %v", code, err)
+	}
+	if e.ErrCode != code {
+		t.Fatalf("expect error of %s, but got %s: %v", code, e.ErrCode, err)
+	}
+}
+
+func mustEqual(t *testing.T, name string, got, want interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: got %#v, want %#v", name, got, want)
+	}
+}
+
+func createCLA(t *testing.T, db dbmodels.IDB, name, applyTo string) string {
+	t.Helper()
+
+	id, err := db.CreateCLA(dbmodels.CLA{
+		Name:      name,
+		Text:      "text of " + name,
+		Language:  "english",
+		Submitter: "owner",
+		ApplyTo:   applyTo,
+		Fields: []dbmodels.Field{
+			{ID: "1", Title: "name", Type: "string", Required: true},
+		},
+	})
+	mustNil(t, err)
+	return id
+}
+
+// createBinding creates a cla and binds it to the org/repo.
+func createBinding(t *testing.T, db dbmodels.IDB, org, repo, applyTo string) string {
+	t.Helper()
+
+	claID := createCLA(t, db, org+"/"+repo+"/"+applyTo, applyTo)
+
+	id, err := db.CreateBindingBetweenCLAAndOrg(dbmodels.CLAOrg{
+		Platform:    platform,
+		OrgID:       org,
+		RepoID:      repo,
+		CLAID:       claID,
+		CLALanguage: "english",
+		ApplyTo:     applyTo,
+		OrgEmail:    "cla@" + org + ".com",
+		Enabled:     true,
+		Submitter:   "owner",
+	})
+	mustNil(t, err)
+	return id
+}
+
+func individualSigning(email, signer string, enabled bool) dbmodels.IndividualSigningInfo {
+	return dbmodels.IndividualSigningInfo{
+		IndividualSigningBasicInfo: dbmodels.IndividualSigningBasicInfo{
+			Email:   email,
+			Name:    "name of " + email,
+			Date:    util.Date(),
+			Enabled: enabled,
+		},
+		Info:   dbmodels.TypeSigningInfo{"1": email},
+		Signer: signer,
+	}
+}
+
+func corpSigning(email, corp string) dbmodels.CorporationSigningInfo {
+	return dbmodels.CorporationSigningInfo{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			AdminEmail:      email,
+			AdminName:       "admin of " + corp,
+			CorporationName: corp,
+			Date:            util.Date(),
+		},
+		Info: dbmodels.TypeSigningInfo{"1": corp},
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,324 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func testVerificationCode(t *testing.T, db dbmodels.IDB) {
+	code := func(c string, expiry int64) dbmodels.VerificationCode {
+		return dbmodels.VerificationCode{
+			Email: "a@x.com", Code: c, Purpose: "signing", Expiry: expiry,
+		}
+	}
+
+	now := util.Now()
+	mustNil(t, db.CreateVerificationCode(code("1", now+60)))
+	mustNil(t, db.CreateVerificationCode(code("2", now+60)))
+
+	// the new code replaces the old one
+	mustErrCode(t, db.CheckVerificationCode(code("1", 0)), util.ErrWrongVerificationCode)
+
+	v := code("2", 0)
+	v.Purpose = "other"
+	mustErrCode(t, db.CheckVerificationCode(v), util.ErrWrongVerificationCode)
+
+	mustNil(t, db.CheckVerificationCode(code("2", 0)))
+
+	// the code can be used only once
+	mustErrCode(t, db.CheckVerificationCode(code("2", 0)), util.ErrWrongVerificationCode)
+
+	mustNil(t, db.CreateVerificationCode(code("3", now-1)))
+	mustErrCode(t, db.CheckVerificationCode(code("3", 0)), util.ErrVerificationCodeExpired)
+}
+
+func testOrgEmail(t *testing.T, db dbmodels.IDB) {
+	_, err := db.GetOrgEmailInfo("cla@org.com")
+	mustErrCode(t, err, util.ErrNoOrgEmail)
+
+	mustNil(t, db.CreateOrgEmail(dbmodels.OrgEmailCreateInfo{
+		Email: "cla@org.com", Platform: "gmail", Token: []byte("token"),
+	}))
+
+	v, err := db.GetOrgEmailInfo("cla@org.com")
+	mustNil(t, err)
+	mustEqual(t, "platform", v.Platform, "gmail")
+	mustEqual(t, "token", v.Token, []byte("token"))
+
+	all, err := db.ListOrgEmails()
+	mustNil(t, err)
+	mustEqual(t, "number of org emails", len(all), 1)
+
+	info := dbmodels.OrgEmailCreateInfo{
+		Token: []byte("encrypted"), KeyID: "k1", EncryptedKey: []byte("key"),
+	}
+
+	// the token is updated only if it is encrypted by the old key
+	mustFail(t, db.UpdateOrgEmailToken("cla@org.com", "k0", info))
+	mustNil(t, db.UpdateOrgEmailToken("cla@org.com", "", info))
+	mustFail(t, db.UpdateOrgEmailToken("cla@org.com", "", info))
+
+	v, err = db.GetOrgEmailInfo("cla@org.com")
+	mustNil(t, err)
+	mustEqual(t, "updated token", v.Token, info.Token)
+	mustEqual(t, "key id", v.KeyID, info.KeyID)
+	mustEqual(t, "encrypted key", v.EncryptedKey, info.EncryptedKey)
+}
+
+func testPDF(t *testing.T, db dbmodels.IDB) {
+	b := createBinding(t, db, "org", "", dbmodels.ApplyToCorporation)
+
+	_, err := db.DownloadOrgSignature(b)
+	mustFail(t, err)
+
+	mustNil(t, db.UploadOrgSignature(b, []byte("signature")))
+
+	v, err := db.DownloadOrgSignature(b)
+	mustNil(t, err)
+	mustEqual(t, "org signature", v, []byte("signature"))
+
+	binding, err := db.GetBindingBetweenCLAAndOrg(b)
+	mustNil(t, err)
+	mustEqual(t, "org signature uploaded", binding.OrgSignatureUploaded, true)
+
+	_, err = db.DownloadBlankSignature("english")
+	mustFail(t, err)
+
+	mustNil(t, db.UploadBlankSignature("english", []byte("blank")))
+
+	v, err = db.DownloadBlankSignature("english")
+	mustNil(t, err)
+	mustEqual(t, "blank signature", v, []byte("blank"))
+}
+
+func testEmailOutbox(t *testing.T, db dbmodels.IDB) {
+	job, err := db.ClaimEmailJob(util.Now(), 60)
+	mustNil(t, err)
+	mustEqual(t, "job of empty outbox", job, (*dbmodels.EmailJob)(nil))
+
+	now := util.Now()
+	add := func(nextRunAt int64) string {
+		t.Helper()
+
+		id, err := db.AddEmailJob(dbmodels.EmailJob{
+			Kind: "test", OrgEmail: "cla@org.com", Payload: []byte("payload"),
+			MaxAttempts: 3, NextRunAt: nextRunAt,
+		})
+		mustNil(t, err)
+		return id
+	}
+
+	j1 := add(now - 10)
+	j2 := add(now - 20)
+	j3 := add(now + 100)
+
+	claim := func(now int64) *dbmodels.EmailJob {
+		t.Helper()
+
+		job, err := db.ClaimEmailJob(now, 60)
+		mustNil(t, err)
+		if job != nil {
+			mustEqual(t, "status of claimed job", job.Status, dbmodels.EmailJobStatusRunning)
+			mustEqual(t, "lease of claimed job", job.LeaseUntil, now+60)
+		}
+		return job
+	}
+	claimID := func(now int64) string {
+		t.Helper()
+
+		if job := claim(now); job != nil {
+			return job.ID
+		}
+		return ""
+	}
+
+	// the job which is due earlier is claimed first
+	job2 := claim(now)
+	mustEqual(t, "first claimed", job2.ID, j2)
+	job1 := claim(now)
+	mustEqual(t, "second claimed", job1.ID, j1)
+	mustEqual(t, "third claimed", claimID(now), "")
+
+	mustNil(t, db.RetryEmailJob(job2, now-5, "failed"))
+
+	job, err = db.ClaimEmailJob(now, 60)
+	mustNil(t, err)
+	mustEqual(t, "retried job", job.ID, j2)
+	mustEqual(t, "attempts", job.Attempts, 2)
+	mustEqual(t, "last error", job.LastError, "failed")
+	mustEqual(t, "payload", job.Payload, []byte("payload"))
+
+	// the lease of first claim is lost after the job is claimed again
+	mustErrCode(t, db.FinishEmailJob(job2), util.ErrEmailJobLeaseLost)
+	mustNil(t, db.FinishEmailJob(job))
+	mustErrCode(t, db.FinishEmailJob(job), util.ErrEmailJobLeaseLost)
+
+	// the job is claimed by others after the lease is expired
+	reclaimed := claim(now + 61)
+	mustEqual(t, "reclaimed job", reclaimed.ID, j1)
+	if reclaimed.LeaseOwner == job1.LeaseOwner {
+		t.Fatal("the owner of lease must be changed after the job is claimed again")
+	}
+	mustErrCode(t, db.KillEmailJob(job1, "dead"), util.ErrEmailJobLeaseLost)
+	mustNil(t, db.KillEmailJob(reclaimed, "dead"))
+
+	// the finished and dead jobs are never claimed again
+	mustEqual(t, "job after the lease", claimID(now+1000), j3)
+	mustEqual(t, "no job", claimID(now+1000), "")
+
+	// the job which has run out of attempts is never claimed again
+	mustEqual(t, "second attempt", claimID(now+2000), j3)
+	mustEqual(t, "third attempt", claimID(now+3000), j3)
+	mustEqual(t, "no attempt", claimID(now+4000), "")
+
+	count := func(status string) int {
+		t.Helper()
+
+		n, err := db.CountEmailJobs(status)
+		mustNil(t, err)
+		return n
+	}
+	mustEqual(t, "pending jobs", count(dbmodels.EmailJobStatusPending), 0)
+	mustEqual(t, "running jobs", count(dbmodels.EmailJobStatusRunning), 1)
+	mustEqual(t, "done jobs", count(dbmodels.EmailJobStatusDone), 1)
+	mustEqual(t, "dead jobs", count(dbmodels.EmailJobStatusDead), 1)
+
+	mustErrCode(t, db.FinishEmailJob(&dbmodels.EmailJob{ID: "invalid id"}), util.ErrInvalidParameter)
+}
+
+func testAccessToken(t *testing.T, db dbmodels.IDB) {
+	now := util.Now()
+
+	token := dbmodels.RefreshToken{
+		ID: "t1", SessionID: "s1", User: "user", Permission: "owner",
+		SessionExpiry: now + 100, Expiry: now + 100,
+	}
+	mustNil(t, db.AddRefreshToken(token))
+
+	v, err := db.UseRefreshToken("t1")
+	mustNil(t, err)
+	mustEqual(t, "refresh token", v, token)
+
+	// the refresh token can be used only once
+	_, err = db.UseRefreshToken("t1")
+	mustErrCode(t, err, util.ErrInvalidRefreshToken)
+
+	mustNil(t, db.AddRefreshToken(dbmodels.RefreshToken{ID: "t2", SessionID: "s1", Expiry: now - 1}))
+	_, err = db.UseRefreshToken("t2")
+	mustErrCode(t, err, util.ErrInvalidRefreshToken)
+
+	mustNil(t, db.AddRefreshToken(dbmodels.RefreshToken{ID: "t3", SessionID: "s2", Expiry: now + 100}))
+	mustNil(t, db.DeleteRefreshTokensOfSession("s2"))
+	_, err = db.UseRefreshToken("t3")
+	mustErrCode(t, err, util.ErrInvalidRefreshToken)
+
+	revoked, err := db.IsAccessSessionRevoked("s1")
+	mustNil(t, err)
+	mustEqual(t, "revoked before revoking", revoked, false)
+
+	mustNil(t, db.RevokeAccessSession("s1", now+100))
+
+	revoked, err = db.IsAccessSessionRevoked("s1")
+	mustNil(t, err)
+	mustEqual(t, "revoked", revoked, true)
+
+	_, err = db.GetAccessSession("s1")
+	mustErrCode(t, err, util.ErrSessionExpired)
+
+	session := dbmodels.AccessSession{ID: "s1", PlatformToken: []byte("token"), Expiry: now + 100}
+	mustNil(t, db.SaveAccessSession(session))
+
+	s, err := db.GetAccessSession("s1")
+	mustNil(t, err)
+	mustEqual(t, "session", s, session)
+
+	mustNil(t, db.DeleteAccessSession("s1"))
+	_, err = db.GetAccessSession("s1")
+	mustErrCode(t, err, util.ErrSessionExpired)
+
+	mustNil(t, db.SaveAccessSession(dbmodels.AccessSession{ID: "s2", Expiry: now - 1}))
+	_, err = db.GetAccessSession("s2")
+	mustErrCode(t, err, util.ErrSessionExpired)
+}
+
+func testOAuthState(t *testing.T, db dbmodels.IDB) {
+	now := util.Now()
+
+	state := dbmodels.OAuthState{
+		State: "state", Purpose: "login", Verifier: "verifier",
+		Binding: "binding", ReturnURL: "/", Expiry: now + 100,
+	}
+	mustNil(t, db.AddOAuthState(state))
+
+	v, err := db.UseOAuthState("state")
+	mustNil(t, err)
+	mustEqual(t, "oauth state", v, state)
+
+	// the state can be used only once
+	_, err = db.UseOAuthState("state")
+	mustErrCode(t, err, util.ErrInvalidOAuthState)
+
+	mustNil(t, db.AddOAuthState(dbmodels.OAuthState{State: "expired", Expiry: now - 1}))
+	_, err = db.UseOAuthState("expired")
+	mustErrCode(t, err, util.ErrInvalidOAuthState)
+}
+
+func testAuditLog(t *testing.T, db dbmodels.IDB) {
+	add := func(org, corp, action string, time int64) {
+		t.Helper()
+
+		mustNil(t, db.AddAuditLog(dbmodels.AuditLog{
+			Actor: "owner", Action: action, Platform: platform, OrgID: org,
+			CorpID: corp, Target: action + "@" + corp,
+			Before: map[string]string{"enabled": "false"},
+			After:  map[string]string{"enabled": "true"},
+			Time:   time,
+		}))
+	}
+
+	add("org1", "a.com", "enable", 3)
+	add("org1", "b.com", "enable", 1)
+	add("org1", "a.com", "delete", 2)
+	add("org2", "a.com", "enable", 4)
+
+	list := func(opt dbmodels.AuditLogListOption) ([]string, int) {
+		t.Helper()
+
+		opt.Platform = platform
+		v, total, err := db.ListAuditLog(opt)
+		mustNil(t, err)
+
+		r := make([]string, 0, len(v))
+		for _, item := range v {
+			r = append(r, item.Target)
+		}
+		return r, total
+	}
+
+	v, total := list(dbmodels.AuditLogListOption{OrgID: "org1"})
+	mustEqual(t, "logs of org", v, []string{"enable@b.com", "delete@a.com", "enable@a.com"})
+	mustEqual(t, "total of org", total, 3)
+
+	v, total = list(dbmodels.AuditLogListOption{OrgID: "org1", CorpID: "a.com"})
+	mustEqual(t, "logs of corp", v, []string{"delete@a.com", "enable@a.com"})
+	mustEqual(t, "total of corp", total, 2)
+
+	v, _ = list(dbmodels.AuditLogListOption{OrgID: "org1", Action: "enable"})
+	mustEqual(t, "logs of action", v, []string{"enable@b.com", "enable@a.com"})
+
+	v, total = list(dbmodels.AuditLogListOption{
+		OrgID: "org1",
+		Page:  dbmodels.PageOption{Page: 2, PerPage: 2, Desc: true},
+	})
+	mustEqual(t, "second page", v, []string{"enable@b.com"})
+	mustEqual(t, "total of page", total, 3)
+
+	logs, _, err := db.ListAuditLog(dbmodels.AuditLogListOption{Platform: platform, OrgID: "org2"})
+	mustNil(t, err)
+	mustEqual(t, "number of logs", len(logs), 1)
+	mustEqual(t, "before", logs[0].Before, map[string]string{"enabled": "false"})
+	mustEqual(t, "after", logs[0].After, map[string]string{"enabled": "true"})
+	mustEqual(t, "has id", logs[0].ID != "", true)
+}
@@ -0,0 +1,327 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func testIndividualSigning(t *testing.T, db dbmodels.IDB) {
+	err := db.SignAsIndividual(unknownID, platform, "org", "", individualSigning("a@x.com", "gitee/a", true))
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+
+	b := createBinding(t, db, "org", "", dbmodels.ApplyToIndividual)
+
+	mustNil(t, db.SignAsIndividual(b, platform, "org", "", individualSigning("a@x.com", "gitee/a", true)))
+	mustNil(t, db.SignAsIndividual(b, platform, "org", "", individualSigning("b@y.com", "gitee/b", false)))
+
+	err = db.SignAsIndividual(b, platform, "org", "", individualSigning("a@x.com", "gitee/a", true))
+	mustErrCode(t, err, util.ErrHasSigned)
+
+	signed, err := db.IsIndividualSigned(platform, "org", "", "a@x.com")
+	mustNil(t, err)
+	mustEqual(t, "a is signed", signed, true)
+
+	signed, err = db.IsIndividualSigned(platform, "org", "", "b@y.com")
+	mustNil(t, err)
+	mustEqual(t, "b is signed", signed, false)
+
+	_, err = db.IsIndividualSigned(platform, "org", "", "c@x.com")
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	_, err = db.IsIndividualSigned(platform, "org1", "", "a@x.com")
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+
+	mustNil(t, db.UpdateIndividualSigning(b, "b@y.com", true))
+	mustErrCode(t, db.UpdateIndividualSigning(b, "b@y.com", true), util.ErrInvalidParameter)
+
+	list := func(opt dbmodels.IndividualSigningListOption) ([]string, int) {
+		t.Helper()
+
+		opt.Platform = platform
+		opt.OrgID = "org"
+		v, total, err := db.ListIndividualSigning(opt)
+		mustNil(t, err)
+
+		emails := make([]string, 0, len(v))
+		for _, item := range v {
+			mustEqual(t, "binding of signing", item.CLAOrgID, b)
+			emails = append(emails, item.Email)
+		}
+		return emails, total
+	}
+
+	emails, total := list(dbmodels.IndividualSigningListOption{
+		Page: dbmodels.PageOption{SortBy: dbmodels.SortByEmail},
+	})
+	mustEqual(t, "all signings", emails, []string{"a@x.com", "b@y.com"})
+	mustEqual(t, "total", total, 2)
+
+	emails, total = list(dbmodels.IndividualSigningListOption{
+		Page: dbmodels.PageOption{SortBy: dbmodels.SortByEmail, Desc: true, Page: 1, PerPage: 1},
+	})
+	mustEqual(t, "first page in desc", emails, []string{"b@y.com"})
+	mustEqual(t, "total of page", total, 2)
+
+	emails, _ = list(dbmodels.IndividualSigningListOption{
+		Filter: dbmodels.SigningFilter{Email: "A@X"},
+	})
+	mustEqual(t, "filter by email", emails, []string{"a@x.com"})
+
+	emails, _ = list(dbmodels.IndividualSigningListOption{CorporationEmail: "someone@y.com"})
+	mustEqual(t, "employees", emails, []string{"b@y.com"})
+
+	emails, total = list(dbmodels.IndividualSigningListOption{
+		Filter: dbmodels.SigningFilter{DateFrom: "2000-01-01", DateTo: "2000-12-31"},
+	})
+	mustEqual(t, "filter by date", len(emails), 0)
+	mustEqual(t, "total of filter by date", total, 0)
+
+	infos, err := db.ListIndividualSigningInfo(dbmodels.IndividualSigningListOption{
+		Platform: platform, OrgID: "org", CorporationEmail: "someone@x.com",
+	})
+	mustNil(t, err)
+	mustEqual(t, "number of signing info", len(infos[b]), 1)
+	mustEqual(t, "signing info", infos[b][0].Info, dbmodels.TypeSigningInfo{"1": "a@x.com"})
+
+	ofSigner, err := db.ListIndividualSigningOfSigner("gitee/a")
+	mustNil(t, err)
+	mustEqual(t, "number of signings of signer", len(ofSigner[b]), 1)
+
+	// only the signer can revoke the signing
+	err = db.RevokeIndividualSigning(b, dbmodels.IndividualSigningRevokeOption{
+		Email: "a@x.com", Signer: "gitee/b",
+	})
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	mustNil(t, db.RevokeIndividualSigning(b, dbmodels.IndividualSigningRevokeOption{
+		Email: "a@x.com", Signer: "gitee/a", Reason: "mistake",
+	}))
+
+	_, err = db.IsIndividualSigned(platform, "org", "", "a@x.com")
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	// the signing without signer can be revoked only by the verified email
+	mustNil(t, db.SignAsIndividual(b, platform, "org", "", individualSigning("d@x.com", "", true)))
+
+	err = db.RevokeIndividualSigning(b, dbmodels.IndividualSigningRevokeOption{
+		Email: "d@x.com", Signer: "gitee/d",
+	})
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	mustNil(t, db.RevokeIndividualSigning(b, dbmodels.IndividualSigningRevokeOption{
+		Email: "d@x.com", Signer: "gitee/d", Reason: "mistake", EmailVerified: true,
+	}))
+
+	mustNil(t, db.DeleteIndividualSigning(b, "b@y.com"))
+	mustNil(t, db.DeleteIndividualSigning(b, "b@y.com"))
+
+	_, total = list(dbmodels.IndividualSigningListOption{})
+	mustEqual(t, "total after deleting", total, 0)
+
+	events, err := db.ListIndividualSigningEvents("gitee/a")
+	mustNil(t, err)
+	mustEqual(t, "number of events", len(events), 2)
+	mustEqual(t, "revoking event", events[1], dbmodels.IndividualSigningEvent{
+		CLAOrgID: b,
+		Email:    "a@x.com",
+		Signer:   "gitee/a",
+		Action:   dbmodels.SigningEventRevoke,
+		Reason:   "mistake",
+		Time:     events[1].Time,
+	})
+
+	events, err = db.ListIndividualSigningEvents("gitee/b")
+	mustNil(t, err)
+	mustEqual(t, "number of events of b", len(events), 2)
+	mustEqual(t, "deleting event", events[1].Action, dbmodels.SigningEventDelete)
+
+	// the signing of binding which is deleted is invisible
+	mustNil(t, db.SignAsIndividual(b, platform, "org", "", individualSigning("c@x.com", "gitee/c", true)))
+	mustNil(t, db.DeleteBindingBetweenCLAAndOrg(b))
+
+	ofSigner, err = db.ListIndividualSigningOfSigner("gitee/c")
+	mustNil(t, err)
+	mustEqual(t, "signings of deleted binding", len(ofSigner), 0)
+
+	mustErrCode(t, db.DeleteIndividualSigning(b, "c@x.com"), util.ErrInvalidParameter)
+}
+
+func testIndividualSigningOfRepo(t *testing.T, db dbmodels.IDB) {
+	orgBinding := createBinding(t, db, "org", "", dbmodels.ApplyToIndividual)
+	repoBinding := createBinding(t, db, "org", "repo", dbmodels.ApplyToIndividual)
+
+	mustNil(t, db.SignAsIndividual(orgBinding, platform, "org", "", individualSigning("a@x.com", "gitee/a", true)))
+	mustNil(t, db.SignAsIndividual(repoBinding, platform, "org", "repo", individualSigning("b@x.com", "gitee/b", true)))
+
+	// the signing of repo is required if the repo has been bound cla
+	_, err := db.IsIndividualSigned(platform, "org", "repo", "a@x.com")
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	signed, err := db.IsIndividualSigned(platform, "org", "repo", "b@x.com")
+	mustNil(t, err)
+	mustEqual(t, "signed for repo", signed, true)
+
+	// the signing of org is used if the repo has not been bound cla
+	signed, err = db.IsIndividualSigned(platform, "org", "repo1", "a@x.com")
+	mustNil(t, err)
+	mustEqual(t, "signed for org", signed, true)
+
+	_, err = db.IsIndividualSigned(platform, "org", "", "b@x.com")
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	sv, err := db.GetIndividualSigningVersion(platform, "org", "repo", "b@x.com")
+	mustNil(t, err)
+	mustEqual(t, "binding of signing version", sv.CLAOrgID, repoBinding)
+}
+
+func testCorporationSigning(t *testing.T, db dbmodels.IDB) {
+	b := createBinding(t, db, "org", "", dbmodels.ApplyToCorporation)
+
+	mustNil(t, db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp.com", "corp")))
+
+	// the corporation can sign only once
+	err := db.SignAsCorporation(b, platform, "org", "", corpSigning("b@corp.com", "corp"))
+	mustErrCode(t, err, util.ErrHasSigned)
+
+	id, detail, err := db.GetCorporationSigningDetail(platform, "org", "", "someone@corp.com")
+	mustNil(t, err)
+	mustEqual(t, "binding of corporation signing", id, b)
+	mustEqual(t, "corporation signing", detail, dbmodels.CorporationSigningDetail{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			AdminEmail:      "a@corp.com",
+			AdminName:       "admin of corp",
+			CorporationName: "corp",
+			Date:            util.Date(),
+			CLAVersion:      1,
+		},
+		Status: dbmodels.CorpSigningStatusSubmitted,
+	})
+
+	_, _, err = db.GetCorporationSigningDetail(platform, "org", "", "someone@corp1.com")
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	_, err = db.CheckCorporationSigning(b, "someone@corp.com")
+	mustNil(t, err)
+
+	_, err = db.CheckCorporationSigning(b, "someone@corp1.com")
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	_, err = db.CheckCorporationSigning(unknownID, "someone@corp.com")
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+
+	_, err = db.DownloadCorporationSigningPDF(b, "a@corp.com")
+	mustErrCode(t, err, util.ErrPDFHasNotUploaded)
+
+	pdf := []byte("pdf of corp")
+	mustErrCode(t, db.UploadCorporationSigningPDF(b, "a@corp1.com", pdf), util.ErrInvalidParameter)
+	mustNil(t, db.UploadCorporationSigningPDF(b, "a@corp.com", pdf))
+
+	v, err := db.DownloadCorporationSigningPDF(b, "someone@corp.com")
+	mustNil(t, err)
+	mustEqual(t, "pdf", v, pdf)
+
+	detail, err = db.CheckCorporationSigning(b, "a@corp.com")
+	mustNil(t, err)
+	mustEqual(t, "pdf uploaded", detail.PDFUploaded, true)
+	mustEqual(t, "status after uploading", detail.Status, dbmodels.CorpSigningStatusPDFUploaded)
+
+	testCorporationSigningReview(t, db, b, pdf)
+
+	mustNil(t, db.SignAsCorporation(b, platform, "org", "", corpSigning("a@corp1.com", "corp1")))
+
+	list := func(opt dbmodels.CorporationSigningListOption) ([]string, int) {
+		t.Helper()
+
+		opt.Platform = platform
+		opt.OrgID = "org"
+		v, total, err := db.ListCorporationSigning(opt)
+		mustNil(t, err)
+
+		emails := make([]string, 0, len(v))
+		for _, item := range v {
+			emails = append(emails, item.AdminEmail)
+		}
+		return emails, total
+	}
+
+	emails, total := list(dbmodels.CorporationSigningListOption{
+		Page: dbmodels.PageOption{SortBy: dbmodels.SortByName, Desc: true},
+	})
+	mustEqual(t, "all corporation signings", emails, []string{"a@corp1.com", "a@corp.com"})
+	mustEqual(t, "total", total, 2)
+
+	emails, _ = list(dbmodels.CorporationSigningListOption{
+		Filter: dbmodels.SigningFilter{Enabled: boolPtr(true)},
+	})
+	mustEqual(t, "signings whose admin is added", len(emails), 0)
+
+	emails, _ = list(dbmodels.CorporationSigningListOption{
+		Filter: dbmodels.SigningFilter{Email: "corp1"},
+	})
+	mustEqual(t, "filter by email", emails, []string{"a@corp1.com"})
+
+	emails, _ = list(dbmodels.CorporationSigningListOption{
+		Status: dbmodels.CorpSigningStatusApproved,
+	})
+	mustEqual(t, "filter by status", emails, []string{"a@corp.com"})
+
+	infos, err := db.ListCorporationSigningInfo(dbmodels.CorporationSigningListOption{
+		Platform: platform, OrgID: "org",
+	})
+	mustNil(t, err)
+	mustEqual(t, "number of signing info", len(infos[b]), 2)
+}
+
+// testCorporationSigningReview reviews the signing of a@corp.com whose pdf has been uploaded.
+// The signing is approved at the end.
+func testCorporationSigningReview(t *testing.T, db dbmodels.IDB, b string, pdf []byte) {
+	update := func(status, comment string) error {
+		return db.UpdateCorporationSigningStatus(b, "a@corp.com", dbmodels.CorporationSigningStatusUpdate{
+			Status: status, Comment: comment,
+		})
+	}
+
+	checkStatus := func(status, comment string) {
+		t.Helper()
+
+		detail, err := db.CheckCorporationSigning(b, "a@corp.com")
+		mustNil(t, err)
+		mustEqual(t, "status", detail.Status, status)
+		mustEqual(t, "review comment", detail.ReviewComment, comment)
+	}
+
+	// it can't be approved before being reviewed
+	mustErrCode(t, update(dbmodels.CorpSigningStatusApproved, ""), util.ErrInvalidSigningStatus)
+	mustErrCode(t, update(dbmodels.CorpSigningStatusAdminCreated, ""), util.ErrInvalidSigningStatus)
+
+	mustNil(t, update(dbmodels.CorpSigningStatusUnderReview, ""))
+	checkStatus(dbmodels.CorpSigningStatusUnderReview, "")
+
+	// the pdf can't be replaced during review
+	mustErrCode(t, db.UploadCorporationSigningPDF(b, "a@corp.com", pdf), util.ErrInvalidSigningStatus)
+
+	mustNil(t, update(dbmodels.CorpSigningStatusRejected, "unsigned"))
+	checkStatus(dbmodels.CorpSigningStatusRejected, "unsigned")
+
+	// the pdf can be uploaded again after rejection
+	mustNil(t, db.UploadCorporationSigningPDF(b, "a@corp.com", pdf))
+	checkStatus(dbmodels.CorpSigningStatusPDFUploaded, "unsigned")
+
+	mustNil(t, update(dbmodels.CorpSigningStatusUnderReview, ""))
+	mustNil(t, update(dbmodels.CorpSigningStatusApproved, "ok"))
+	checkStatus(dbmodels.CorpSigningStatusApproved, "ok")
+
+	mustErrCode(t, update(dbmodels.CorpSigningStatusRejected, ""), util.ErrInvalidSigningStatus)
+
+	err := db.UpdateCorporationSigningStatus(b, "a@corp1.com", dbmodels.CorporationSigningStatusUpdate{
+		Status: dbmodels.CorpSigningStatusUnderReview,
+	})
+	mustErrCode(t, err, util.ErrHasNotSigned)
+
+	err = db.UpdateCorporationSigningStatus(unknownID, "a@corp.com", dbmodels.CorporationSigningStatusUpdate{
+		Status: dbmodels.CorpSigningStatusUnderReview,
+	})
+	mustErrCode(t, err, util.ErrNoCLABindingDoc)
+}
@@ -0,0 +1,25 @@
+package dbmodels
+
+const (
+	EmailJobStatusPending = "pending"
+	EmailJobStatusRunning = "running"
+	EmailJobStatusDone    = "done"
+	EmailJobStatusDead    = "dead"
+)
+
+type EmailJob struct {
+	ID          string `json:"id"`
+	Kind        string `json:"kind"`
+	OrgEmail    string `json:"org_email"`
+	Payload     []byte `json:"-"`
+	Status      string `json:"status"`
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"max_attempts"`
+	NextRunAt   int64  `json:"next_run_at"`
+	LastError   string `json:"last_error"`
+
+	// LeaseOwner and LeaseUntil are set when the job is claimed. The job can
+	// only be updated by the one who holds the lease.
+	LeaseOwner string `json:"-"`
+	LeaseUntil int64  `json:"lease_until"`
+}
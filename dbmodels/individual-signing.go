@@ -5,12 +5,17 @@ type IndividualSigningBasicInfo struct {
 	Name    string `json:"name"`
 	Date    string `json:"date"`
 	Enabled bool   `json:"enabled"`
+	// CLAVersion is the version of cla signed, it is set by server.
+	CLAVersion int `json:"cla_version"`
 }
 
 type IndividualSigningInfo struct {
 	IndividualSigningBasicInfo
 
 	Info TypeSigningInfo `json:"info"`
+
+	// Signer is the account of code platform who signs, such as gitee/someone.
+	Signer string `json:"-"`
 }
 
 type IndividualSigningListOption struct {
@@ -19,4 +24,43 @@ type IndividualSigningListOption struct {
 	RepoID           string `json:"repo_id"`
 	CLALanguage      string `json:"cla_language"`
 	CorporationEmail string `json:"corporation_email"`
+	// CorporationDomains are the other domains of corporation whose employees are matched too.
+	CorporationDomains []string `json:"-"`
+
+	Filter SigningFilter `json:"-"`
+	Page   PageOption    `json:"-"`
+}
+
+type IndividualSigningListItem struct {
+	CLAOrgID string `json:"cla_org_id"`
+
+	IndividualSigningBasicInfo
+}
+
+const (
+	SigningEventSign   = "sign"
+	SigningEventResign = "re-sign"
+	SigningEventRevoke = "revoke"
+	SigningEventDelete = "delete"
+)
+
+// IndividualSigningEvent is the history of individual signing, which can't be changed.
+type IndividualSigningEvent struct {
+	CLAOrgID string `json:"cla_org_id"`
+	Email    string `json:"email"`
+	Signer   string `json:"signer"`
+	Action   string `json:"action"`
+	Reason   string `json:"reason,omitempty"`
+	Time     int64  `json:"time"`
+}
+
+type IndividualSigningRevokeOption struct {
+	Email  string `json:"email"`
+	Signer string `json:"-"`
+	Reason string `json:"reason"`
+
+	// EmailVerified means the email has been verified, so the signing
+	// which has no signer, such as the one signed before the signer
+	// was recorded, can be revoked too.
+	EmailVerified bool `json:"-"`
 }
@@ -0,0 +1,34 @@
+package dbmodels
+
+const (
+	SortByDate  = "date"
+	SortByName  = "name"
+	SortByEmail = "email"
+)
+
+// PageOption is the option of pagination and sorting.
+// All the items will be returned if PerPage is 0.
+type PageOption struct {
+	Page    int
+	PerPage int
+	SortBy  string
+	Desc    bool
+}
+
+// Skip returns the number of items before the page.
+func (this PageOption) Skip() int {
+	if this.PerPage <= 0 || this.Page <= 1 {
+		return 0
+	}
+	return (this.Page - 1) * this.PerPage
+}
+
+// SigningFilter filters the signings. The empty field will not be used to filter.
+type SigningFilter struct {
+	Enabled *bool
+	// DateFrom and DateTo are in the format of 2006-01-02 and both inclusive.
+	DateFrom string
+	DateTo   string
+	// Email is a substring of the email which is case insensitive.
+	Email string
+}
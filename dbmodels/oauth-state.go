@@ -0,0 +1,12 @@
+package dbmodels
+
+type OAuthState struct {
+	State   string
+	Purpose string
+	// Verifier is the code verifier of PKCE.
+	Verifier string
+	// Binding is the hash of the value which is set in the cookie of initiating browser.
+	Binding   string
+	ReturnURL string
+	Expiry    int64
+}
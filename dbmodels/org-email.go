@@ -4,4 +4,9 @@ type OrgEmailCreateInfo struct {
 	Email    string `json:"email" required:"true"`
 	Platform string `json:"platform" required:"true"`
 	Token    []byte `json:"-"`
+
+	// KeyID is the id of master key which encrypts the data key of token.
+	// The token was stored as plaintext if it is empty.
+	KeyID        string `json:"-"`
+	EncryptedKey []byte `json:"-"`
 }
@@ -1,5 +1,11 @@
 package email
 
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
 type emailConfigs struct {
 	WebRedirectDir string `json:"web_redirect_dir" required:"true"`
 
@@ -10,3 +16,26 @@ type platformConfig struct {
 	Platform    string `json:"platform" required:"true"`
 	Credentials string `json:"credentials" required:"true"`
 }
+
+func (this *emailConfigs) validate() error {
+	if len(this.Configs) == 0 {
+		return fmt.Errorf("no email platform is configured")
+	}
+
+	m := map[string]bool{}
+	for _, item := range this.Configs {
+		if _, ok := factories[item.Platform]; !ok {
+			return fmt.Errorf("unsupported email platform: %s", item.Platform)
+		}
+
+		if m[item.Platform] {
+			return fmt.Errorf("email platform: %s is configured repeatedly", item.Platform)
+		}
+		m[item.Platform] = true
+
+		if util.IsFileNotExist(item.Credentials) {
+			return fmt.Errorf("the credentials file of email platform: %s is not exist", item.Platform)
+		}
+	}
+	return nil
+}
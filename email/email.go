@@ -2,52 +2,159 @@ package email
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/astaxie/beego"
 	"golang.org/x/oauth2"
 
 	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
-var emails = map[string]IEmail{}
+// factories includes all the supported email platforms.
+var factories = map[string]func() IEmail{}
+
+var reg = &registry{}
 
 type IEmail interface {
-	GetOauth2CodeURL(state string) string
-	GetAuthorizedEmail(code, scope string) (*models.OrgEmail, error)
+	GetOauth2CodeURL(state, verifier string) string
+	GetAuthorizedEmail(code, scope, verifier string) (*models.OrgEmail, error)
 	SendEmail(token *oauth2.Token, msg *EmailMessage) error
 	WebRedirectDir() string
 	initialize(credentials, webRedirectDir string) error
 }
 
+func registerFactory(platform string, f func() IEmail) {
+	factories[platform] = f
+}
+
 func GetEmailClient(platform string) (IEmail, error) {
-	e, ok := emails[platform]
+	e, ok := reg.get(platform)
 	if !ok {
-		return nil, fmt.Errorf("it only supports gmail platform currently")
+		return nil, fmt.Errorf("unsupported email platform: %s", platform)
 	}
 
 	return e, nil
 }
 
+// ListPlatforms returns the email platforms which are available for authorization.
+func ListPlatforms() []string {
+	return reg.list()
+}
+
 func RegisterPlatform(configFile string) error {
 	if err := initTemplate(); err != nil {
 		return err
 	}
 
+	return reg.load(configFile)
+}
+
+// WatchPlatforms reloads the email platforms when the config file or
+// any credentials file of the platforms is changed. The platforms will
+// be kept unchanged if the new configuration is invalid.
+func WatchPlatforms(interval time.Duration) {
+	for range time.Tick(interval) {
+		if !reg.isChanged() {
+			continue
+		}
+
+		if err := reg.load(reg.configFile); err != nil {
+			beego.Error(fmt.Sprintf("failed to reload email platforms: %s", err.Error()))
+			continue
+		}
+		beego.Info("email platforms are reloaded")
+	}
+}
+
+type registry struct {
+	lock    sync.RWMutex
+	clients map[string]IEmail
+
+	configFile string
+	// modTimes records the modification time of the config file and credentials files.
+	modTimes map[string]time.Time
+}
+
+func (this *registry) get(platform string) (IEmail, bool) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	e, ok := this.clients[platform]
+	return e, ok
+}
+
+func (this *registry) list() []string {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	r := make([]string, 0, len(this.clients))
+	for k := range this.clients {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+func (this *registry) load(configFile string) error {
 	cfg := emailConfigs{}
 	if err := util.LoadFromYaml(configFile, &cfg); err != nil {
 		return err
 	}
 
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	files := []string{configFile}
+	clients := map[string]IEmail{}
 	for _, item := range cfg.Configs {
-		e, err := GetEmailClient(item.Platform)
-		if err != nil {
+		e := factories[item.Platform]()
+		if err := e.initialize(item.Credentials, cfg.WebRedirectDir); err != nil {
 			return err
 		}
-		return e.initialize(item.Credentials, cfg.WebRedirectDir)
+
+		clients[item.Platform] = e
+		files = append(files, item.Credentials)
 	}
+
+	modTimes := map[string]time.Time{}
+	for _, f := range files {
+		modTimes[f] = modTime(f)
+	}
+
+	this.lock.Lock()
+	this.clients = clients
+	this.configFile = configFile
+	this.modTimes = modTimes
+	this.lock.Unlock()
+
 	return nil
 }
 
+func (this *registry) isChanged() bool {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	for f, t := range this.modTimes {
+		if !modTime(f).Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func modTime(file string) time.Time {
+	v, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return v.ModTime()
+}
+
 type EmailMessage struct {
 	From       string   `json:"from"`
 	To         []string `json:"to"`
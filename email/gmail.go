@@ -21,7 +21,7 @@ import (
 )
 
 func init() {
-	emails["gmail"] = &gmailClient{}
+	registerFactory("gmail", func() IEmail { return &gmailClient{} })
 }
 
 type gmailClient struct {
@@ -55,12 +55,12 @@ func (this *gmailClient) WebRedirectDir() string {
 	return this.webRedirectDir
 }
 
-func (this *gmailClient) GetAuthorizedEmail(code, scope string) (*models.OrgEmail, error) {
+func (this *gmailClient) GetAuthorizedEmail(code, scope, verifier string) (*models.OrgEmail, error) {
 	if this.cfg == nil {
 		return nil, fmt.Errorf("gmail has not been initialized")
 	}
 
-	token, err := myoauth2.FetchOauth2Token(this.cfg, code)
+	token, err := myoauth2.FetchOauth2Token(this.cfg, code, verifier)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +82,8 @@ func (this *gmailClient) GetAuthorizedEmail(code, scope string) (*models.OrgEmai
 	}, nil
 }
 
-func (this *gmailClient) GetOauth2CodeURL(state string) string {
-	return myoauth2.GetOauth2CodeURL(state, this.cfg)
+func (this *gmailClient) GetOauth2CodeURL(state, verifier string) string {
+	return myoauth2.GetOauth2CodeURL(state, verifier, this.cfg)
 }
 
 func (this *gmailClient) SendEmail(token *oauth2.Token, msg *EmailMessage) error {
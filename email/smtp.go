@@ -0,0 +1,353 @@
+package email
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	smtpSecurityNone     = "none"
+	smtpSecurityStartTLS = "starttls"
+	smtpSecurityTLS      = "tls"
+
+	smtpAuthPlain = "plain"
+	smtpAuthLogin = "login"
+
+	// smtpAuthCode is the fake code used to complete the authorization flow,
+	// because there is no authorization server for smtp.
+	smtpAuthCode = "smtp"
+)
+
+func init() {
+	registerFactory("smtp", func() IEmail { return &smtpClient{} })
+}
+
+type smtpConfig struct {
+	Host     string `json:"host" required:"true"`
+	Port     int    `json:"port" required:"true"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// From is the email address of the sender, it is username by default.
+	From string `json:"from"`
+	// Security is one of none, starttls and tls. It is starttls by default.
+	Security string `json:"security"`
+	// Auth is one of plain and login. It is plain by default.
+	Auth string `json:"auth"`
+	// SkipVerify skips verifying the certificate of server, only for test.
+	SkipVerify bool `json:"skip_verify"`
+	// AuthCallbackURL is the url of api: /v1/email/auth/smtp
+	AuthCallbackURL string `json:"auth_callback_url" required:"true"`
+}
+
+func (this *smtpConfig) setDefault() {
+	if this.From == "" {
+		this.From = this.Username
+	}
+	if this.Security == "" {
+		this.Security = smtpSecurityStartTLS
+	}
+	if this.Auth == "" {
+		this.Auth = smtpAuthPlain
+	}
+}
+
+func (this *smtpConfig) validate() error {
+	if this.Host == "" || this.Port <= 0 {
+		return fmt.Errorf("invalid smtp server address")
+	}
+
+	if this.From == "" {
+		return fmt.Errorf("missing the email address of sender")
+	}
+
+	switch this.Security {
+	case smtpSecurityNone, smtpSecurityStartTLS, smtpSecurityTLS:
+	default:
+		return fmt.Errorf("unknown security: %s", this.Security)
+	}
+
+	switch this.Auth {
+	case smtpAuthPlain, smtpAuthLogin:
+	default:
+		return fmt.Errorf("unknown auth: %s", this.Auth)
+	}
+
+	if this.AuthCallbackURL == "" {
+		return fmt.Errorf("missing auth_callback_url")
+	}
+	return nil
+}
+
+type smtpClient struct {
+	cfg *smtpConfig
+
+	webRedirectDir string
+}
+
+func (this *smtpClient) initialize(path, webRedirectDir string) error {
+	cfg := &smtpConfig{}
+	if err := util.LoadFromYaml(path, cfg); err != nil {
+		return fmt.Errorf("Failtd to initialize smtp client: %s", err.Error())
+	}
+
+	cfg.setDefault()
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("Failtd to initialize smtp client: %s", err.Error())
+	}
+
+	this.cfg = cfg
+	this.webRedirectDir = webRedirectDir
+	return nil
+}
+
+func (this *smtpClient) WebRedirectDir() string {
+	return this.webRedirectDir
+}
+
+// GetOauth2CodeURL returns the callback url directly, because the smtp
+// server was authorized by the configuration. So, the verifier is useless.
+func (this *smtpClient) GetOauth2CodeURL(state, verifier string) string {
+	v := url.Values{}
+	v.Set("code", smtpAuthCode)
+	v.Set("scope", smtpAuthCode)
+	v.Set("state", state)
+
+	s := this.cfg.AuthCallbackURL
+	if strings.Contains(s, "?") {
+		return s + "&" + v.Encode()
+	}
+	return s + "?" + v.Encode()
+}
+
+func (this *smtpClient) GetAuthorizedEmail(code, scope, verifier string) (*models.OrgEmail, error) {
+	if this.cfg == nil {
+		return nil, fmt.Errorf("smtp has not been initialized")
+	}
+
+	if code != smtpAuthCode {
+		return nil, fmt.Errorf("invalid code")
+	}
+
+	return &models.OrgEmail{
+		Email: this.cfg.From,
+		Token: &oauth2.Token{},
+	}, nil
+}
+
+// SendEmail sends email by the configured account. The token is not used.
+func (this *smtpClient) SendEmail(token *oauth2.Token, msg *EmailMessage) error {
+	if this.cfg == nil {
+		return fmt.Errorf("smtp has not been initialized")
+	}
+
+	data, err := this.createMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	c, err := this.dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if this.cfg.Username != "" {
+		if err := c.Auth(this.auth()); err != nil {
+			return fmt.Errorf("smtp auth failed: %s", err.Error())
+		}
+	}
+
+	if err := c.Mail(this.cfg.From); err != nil {
+		return err
+	}
+
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func (this *smtpClient) dial() (*smtp.Client, error) {
+	cfg := this.cfg
+	addr := net.JoinHostPort(cfg.Host, fmt.Sprintf("%d", cfg.Port))
+	tlsCfg := &tls.Config{
+		ServerName:         cfg.Host,
+		InsecureSkipVerify: cfg.SkipVerify,
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if cfg.Security == smtpSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsCfg)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect smtp server: %s", err.Error())
+	}
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if cfg.Security == smtpSecurityStartTLS {
+		if err := c.StartTLS(tlsCfg); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("smtp starttls failed: %s", err.Error())
+		}
+	}
+
+	return c, nil
+}
+
+func (this *smtpClient) auth() smtp.Auth {
+	if this.cfg.Auth == smtpAuthLogin {
+		return &loginAuth{username: this.cfg.Username, password: this.cfg.Password}
+	}
+	return smtp.PlainAuth("", this.cfg.Username, this.cfg.Password, this.cfg.Host)
+}
+
+func (this *smtpClient) createMessage(msg *EmailMessage) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", this.cfg.From)
+	header.Set("To", strings.Join(msg.To, ", "))
+	header.Set("Subject", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("MIME-Version", "1.0")
+
+	if msg.Attachment == "" {
+		header.Set("Content-Type", `text/plain; charset="UTF-8"`)
+		header.Set("Content-Transfer-Encoding", "base64")
+		writeHeader(buf, header)
+		writeBase64(buf, []byte(msg.Content))
+		return buf.Bytes(), nil
+	}
+
+	fileBytes, err := ioutil.ReadFile(msg.Attachment)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read file for attachment: %s", err.Error())
+	}
+
+	mw := multipart.NewWriter(buf)
+
+	header.Set("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%s", mw.Boundary()))
+	writeHeader(buf, header)
+
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {`text/plain; charset="UTF-8"`},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeBase64(pw, []byte(msg.Content))
+
+	fileName := path.Base(msg.Attachment)
+	pw, err = mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {fmt.Sprintf("%s; name=%q", http.DetectContentType(fileBytes), fileName)},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", fileName)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeBase64(pw, fileBytes)
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeHeader(w *bytes.Buffer, header textproto.MIMEHeader) {
+	for k, v := range header {
+		fmt.Fprintf(w, "%s: %s\r\n", k, strings.Join(v, ", "))
+	}
+	w.WriteString("\r\n")
+}
+
+// writeBase64 writes data in base64 with lines of 76 characters as RFC 2045 required.
+func writeBase64(w io.Writer, data []byte) {
+	s := base64.StdEncoding.EncodeToString(data)
+	for len(s) > 76 {
+		w.Write([]byte(s[:76] + "\r\n"))
+		s = s[76:]
+	}
+	w.Write([]byte(s + "\r\n"))
+}
+
+// loginAuth implements the LOGIN authentication mechanism which is not
+// supported by net/smtp.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (this *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, fmt.Errorf("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (this *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(this.username), nil
+	case "password:":
+		return []byte(this.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", string(fromServer))
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
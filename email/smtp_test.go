@@ -0,0 +1,385 @@
+package email
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession is what the stub server received in a session.
+type smtpSession struct {
+	tls      bool
+	mech     string
+	username string
+	password string
+	from     string
+	to       []string
+	data     []byte
+	err      error
+}
+
+// smtpStub is a local smtp server which accepts one session.
+type smtpStub struct {
+	ln       net.Listener
+	tlsCfg   *tls.Config
+	implicit bool
+	session  chan smtpSession
+}
+
+func newSMTPStub(t *testing.T, implicitTLS bool) *smtpStub {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &smtpStub{
+		ln:       ln,
+		tlsCfg:   &tls.Config{Certificates: []tls.Certificate{newTestCert(t)}},
+		implicit: implicitTLS,
+		session:  make(chan smtpSession, 1),
+	}
+	go s.serve()
+	return s
+}
+
+func (this *smtpStub) port() int {
+	return this.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (this *smtpStub) close() {
+	this.ln.Close()
+}
+
+func (this *smtpStub) serve() {
+	conn, err := this.ln.Accept()
+	if err != nil {
+		return
+	}
+
+	s := smtpSession{}
+	if this.implicit {
+		conn = tls.Server(conn, this.tlsCfg)
+		s.tls = true
+	}
+	defer func() { conn.Close() }()
+
+	s.err = this.handle(&conn, &s)
+	this.session <- s
+}
+
+func (this *smtpStub) handle(conn *net.Conn, s *smtpSession) error {
+	tp := textproto.NewConn(*conn)
+
+	reply := func(lines ...string) error {
+		return tp.PrintfLine("%s", strings.Join(lines, "\r\n"))
+	}
+
+	if err := reply("220 localhost ESMTP stub"); err != nil {
+		return err
+	}
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return err
+		}
+
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		arg := strings.TrimSpace(strings.TrimPrefix(line, strings.SplitN(line, " ", 2)[0]))
+
+		switch cmd {
+		case "EHLO":
+			ext := []string{"250-localhost"}
+			if !s.tls {
+				ext = append(ext, "250-STARTTLS")
+			}
+			err = reply(append(ext, "250 AUTH PLAIN LOGIN")...)
+
+		case "STARTTLS":
+			if err := reply("220 ready to start tls"); err != nil {
+				return err
+			}
+			tc := tls.Server(*conn, this.tlsCfg)
+			if err := tc.Handshake(); err != nil {
+				return err
+			}
+			*conn = tc
+			tp = textproto.NewConn(tc)
+			s.tls = true
+
+		case "AUTH":
+			err = this.auth(tp, arg, s)
+			if err == nil {
+				err = reply("235 authenticated")
+			}
+
+		case "MAIL":
+			s.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			err = reply("250 ok")
+
+		case "RCPT":
+			s.to = append(s.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			err = reply("250 ok")
+
+		case "DATA":
+			if err := reply("354 go ahead"); err != nil {
+				return err
+			}
+			if s.data, err = tp.ReadDotBytes(); err != nil {
+				return err
+			}
+			err = reply("250 queued")
+
+		case "QUIT":
+			reply("221 bye")
+			return nil
+
+		default:
+			err = reply("502 unknown command")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (this *smtpStub) auth(tp *textproto.Conn, arg string, s *smtpSession) error {
+	v := strings.SplitN(arg, " ", 2)
+	s.mech = strings.ToUpper(v[0])
+
+	readBase64 := func() (string, error) {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		b, err := base64.StdEncoding.DecodeString(line)
+		return string(b), err
+	}
+
+	switch s.mech {
+	case "PLAIN":
+		if len(v) < 2 {
+			return tp.PrintfLine("501 missing initial response")
+		}
+		b, err := base64.StdEncoding.DecodeString(v[1])
+		if err != nil {
+			return err
+		}
+		items := strings.Split(string(b), "\x00")
+		if len(items) == 3 {
+			s.username, s.password = items[1], items[2]
+		}
+
+	case "LOGIN":
+		var err error
+		if err = tp.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte("Username:"))); err != nil {
+			return err
+		}
+		if s.username, err = readBase64(); err != nil {
+			return err
+		}
+		if err = tp.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte("Password:"))); err != nil {
+			return err
+		}
+		if s.password, err = readBase64(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func newTestSMTPClient(port int, security, auth string) *smtpClient {
+	cfg := &smtpConfig{
+		Host:       "127.0.0.1",
+		Port:       port,
+		Username:   "robot@community.org",
+		Password:   "secret",
+		Security:   security,
+		Auth:       auth,
+		SkipVerify: true,
+	}
+	cfg.setDefault()
+	return &smtpClient{cfg: cfg}
+}
+
+func TestSMTPSendEmail(t *testing.T) {
+	cases := []struct {
+		security string
+		auth     string
+	}{
+		{smtpSecurityNone, smtpAuthPlain},
+		{smtpSecurityNone, smtpAuthLogin},
+		{smtpSecurityStartTLS, smtpAuthPlain},
+		{smtpSecurityStartTLS, smtpAuthLogin},
+		{smtpSecurityTLS, smtpAuthPlain},
+	}
+
+	for _, c := range cases {
+		name := c.security + "/" + c.auth
+
+		stub := newSMTPStub(t, c.security == smtpSecurityTLS)
+		cli := newTestSMTPClient(stub.port(), c.security, c.auth)
+
+		err := cli.SendEmail(nil, &EmailMessage{
+			To:      []string{"a@corp.com", "b@corp.com"},
+			Subject: "CLA",
+			Content: "hello",
+		})
+		stub.close()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		s := <-stub.session
+		if s.err != nil {
+			t.Errorf("%s: stub server failed: %v", name, s.err)
+			continue
+		}
+
+		if s.tls != (c.security != smtpSecurityNone) {
+			t.Errorf("%s: expect tls to be %v", name, !s.tls)
+		}
+		if s.mech != strings.ToUpper(c.auth) {
+			t.Errorf("%s: expect auth %s, but got %s", name, c.auth, s.mech)
+		}
+		if s.username != "robot@community.org" || s.password != "secret" {
+			t.Errorf("%s: unexpected credential: %s/%s", name, s.username, s.password)
+		}
+		if s.from != "robot@community.org" {
+			t.Errorf("%s: unexpected sender: %s", name, s.from)
+		}
+		if strings.Join(s.to, ",") != "a@corp.com,b@corp.com" {
+			t.Errorf("%s: unexpected receivers: %v", name, s.to)
+		}
+		if len(s.data) == 0 {
+			t.Errorf("%s: no message is sent", name)
+		}
+	}
+}
+
+func TestSMTPCreateMessageWithAttachment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "smtp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	attachment := bytes.Repeat([]byte("%PDF-1.4 signed cla "), 20)
+	file := filepath.Join(dir, "cla.pdf")
+	if err := ioutil.WriteFile(file, attachment, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := newTestSMTPClient(25, smtpSecurityNone, smtpAuthPlain)
+	data, err := cli.createMessage(&EmailMessage{
+		To:         []string{"a@corp.com"},
+		Subject:    "签署 CLA",
+		Content:    "please check the attachment",
+		Attachment: file,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "签署 CLA" {
+		t.Errorf("unexpected subject: %s, %v", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("unexpected content type: %s, %v", mediaType, err)
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+
+	var parts []*multipart.Part
+	var bodies [][]byte
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+
+		encoded, err := ioutil.ReadAll(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(string(encoded)), "\r\n")
+		for _, line := range lines {
+			if len(line) > 76 {
+				t.Fatalf("the line of base64 is too long: %s", line)
+			}
+		}
+
+		b, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, p)
+		bodies = append(bodies, b)
+	}
+
+	if len(parts) != 2 {
+		t.Fatalf("expect 2 parts, but got %d", len(parts))
+	}
+
+	if string(bodies[0]) != "please check the attachment" {
+		t.Errorf("unexpected content: %s", bodies[0])
+	}
+
+	if parts[1].FileName() != "cla.pdf" {
+		t.Errorf("unexpected file name: %s", parts[1].FileName())
+	}
+	if !bytes.Equal(bodies[1], attachment) {
+		t.Error("the attachment is changed")
+	}
+}
@@ -18,6 +18,8 @@ const (
 	TmplActivatingEmployee    = "activating employee"
 	TmplInactivaingEmployee   = "inactivating employee"
 	TmplRemovingingEmployee   = "removing employee"
+	TmplCorpSigningStatus     = "corp signing status"
+	TmplCorpDomainStatus      = "corp domain status"
 )
 
 var msgTmpl = map[string]*template.Template{}
@@ -34,6 +36,8 @@ func initTemplate() error {
 		TmplActivatingEmployee:    "./conf/email-template/activating-employee.tmpl",
 		TmplInactivaingEmployee:   "./conf/email-template/inactivating-employee.tmpl",
 		TmplRemovingingEmployee:   "./conf/email-template/removing-employee.tmpl",
+		TmplCorpSigningStatus:     "./conf/email-template/corp-signing-status.tmpl",
+		TmplCorpDomainStatus:      "./conf/email-template/corp-domain-status.tmpl",
 	}
 
 	for name, path := range items {
@@ -140,3 +144,26 @@ func (this EmployeeNotification) GenEmailMsg() (*EmailMessage, error) {
 
 	return nil, fmt.Errorf("do nothing")
 }
+
+// CorpSigningStatus notifies the administrator of corporation
+// that the status of signing is changed.
+type CorpSigningStatus struct {
+	CorporationName string
+	Status          string
+	Comment         string
+}
+
+func (this CorpSigningStatus) GenEmailMsg() (*EmailMessage, error) {
+	return genEmailMsg(TmplCorpSigningStatus, this)
+}
+
+// CorpDomainStatus notifies the administrator of corporation
+// that the requested domain is approved or rejected.
+type CorpDomainStatus struct {
+	Domain   string
+	Approved bool
+}
+
+func (this CorpDomainStatus) GenEmailMsg() (*EmailMessage, error) {
+	return genEmailMsg(TmplCorpDomainStatus, this)
+}
@@ -0,0 +1,39 @@
+package encryption
+
+import (
+	"fmt"
+)
+
+type keysConfig struct {
+	// PrimaryKey is the id of master key which is used to encrypt the new data.
+	PrimaryKey string      `json:"primary_key" required:"true"`
+	Keys       []keyConfig `json:"keys" required:"true"`
+}
+
+type keyConfig struct {
+	ID     string `json:"id" required:"true"`
+	Secret string `json:"secret" required:"true"`
+}
+
+func (this *keysConfig) validate() error {
+	m := map[string]bool{}
+	for _, item := range this.Keys {
+		if item.ID == "" {
+			return fmt.Errorf("missing the id of master key")
+		}
+
+		if m[item.ID] {
+			return fmt.Errorf("master key: %s is configured repeatedly", item.ID)
+		}
+		m[item.ID] = true
+
+		if len(item.Secret) < 20 {
+			return fmt.Errorf("the length of master key: %s should be bigger than 20", item.ID)
+		}
+	}
+
+	if !m[this.PrimaryKey] {
+		return fmt.Errorf("the primary key: %s is not configured", this.PrimaryKey)
+	}
+	return nil
+}
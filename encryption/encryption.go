@@ -0,0 +1,111 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var keys = &masterKeys{}
+
+type masterKeys struct {
+	primary string
+	keys    map[string][]byte
+}
+
+// Envelope is the data encrypted by a random data key, and
+// the data key is encrypted by the master key of KeyID.
+type Envelope struct {
+	KeyID        string
+	EncryptedKey []byte
+	Ciphertext   []byte
+}
+
+func RegisterKeys(configFile string) error {
+	cfg := keysConfig{}
+	if err := util.LoadFromYaml(configFile, &cfg); err != nil {
+		return err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	m := map[string][]byte{}
+	for _, item := range cfg.Keys {
+		m[item.ID] = util.DeriveKey(item.Secret)
+	}
+
+	keys = &masterKeys{primary: cfg.PrimaryKey, keys: m}
+	return nil
+}
+
+// PrimaryKeyID returns the id of master key which encrypts the new data.
+func PrimaryKeyID() string {
+	return keys.primary
+}
+
+// Encrypt encrypts the plaintext by the primary master key. The ciphertext is bound to
+// the master key and the owner, such as the org email, so it can't be used by others.
+func Encrypt(plaintext []byte, owner string) (Envelope, error) {
+	mk, ok := keys.keys[keys.primary]
+	if !ok {
+		return Envelope{}, fmt.Errorf("no master key is available")
+	}
+
+	dk := make([]byte, 32)
+	if _, err := rand.Read(dk); err != nil {
+		return Envelope{}, err
+	}
+
+	ad := additionalData(keys.primary, owner)
+
+	ciphertext, err := util.Encrypt(plaintext, dk, ad)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	ek, err := util.Encrypt(dk, mk, ad)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	return Envelope{
+		KeyID:        keys.primary,
+		EncryptedKey: ek,
+		Ciphertext:   ciphertext,
+	}, nil
+}
+
+// Decrypt decrypts the envelope which is encrypted for the owner.
+func Decrypt(e Envelope, owner string) ([]byte, error) {
+	return decrypt(e, additionalData(e.KeyID, owner))
+}
+
+// DecryptLegacy decrypts the envelope which was encrypted without
+// the additional data by the early versions.
+func DecryptLegacy(e Envelope) ([]byte, error) {
+	return decrypt(e, nil)
+}
+
+func decrypt(e Envelope, ad []byte) ([]byte, error) {
+	mk, ok := keys.keys[e.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key: %s", e.KeyID)
+	}
+
+	dk, err := util.Decrypt(e.EncryptedKey, mk, ad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %s", err.Error())
+	}
+
+	return util.Decrypt(e.Ciphertext, dk, ad)
+}
+
+// additionalData is prefixed with the length of key id, so that
+// the key id and the owner can't be shifted between each other.
+func additionalData(keyID, owner string) []byte {
+	return []byte(strconv.Itoa(len(keyID)) + ":" + keyID + owner)
+}
@@ -0,0 +1,232 @@
+package encryption
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	testOwner   = "cla@org.com"
+	testSecret1 = "secret-of-master-key-1"
+	testSecret2 = "secret-of-master-key-2"
+)
+
+// registerKeys registers the master keys by the config file.
+func registerKeys(t *testing.T, content string) error {
+	dir, err := ioutil.TempDir("", "encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "keys.yaml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return RegisterKeys(path)
+}
+
+func mustRegisterKeys(t *testing.T, primary string, secrets map[string]string) {
+	t.Helper()
+
+	content := "primary_key: " + primary + "\nkeys:\n"
+	for id, secret := range secrets {
+		content += "- id: " + id + "\n  secret: " + secret + "\n"
+	}
+
+	if err := registerKeys(t, content); err != nil {
+		t.Fatalf("register keys: %v", err)
+	}
+}
+
+func mustEncrypt(t *testing.T, plaintext []byte, owner string) Envelope {
+	t.Helper()
+
+	e, err := Encrypt(plaintext, owner)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	return e
+}
+
+func TestRegisterKeys(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{
+			name:    "valid",
+			content: "primary_key: k1\nkeys:\n- id: k1\n  secret: " + testSecret1 + "\n",
+			valid:   true,
+		},
+		{
+			name:    "primary key is not configured",
+			content: "primary_key: k2\nkeys:\n- id: k1\n  secret: " + testSecret1 + "\n",
+		},
+		{
+			name: "key is configured repeatedly",
+			content: "primary_key: k1\nkeys:\n- id: k1\n  secret: " + testSecret1 +
+				"\n- id: k1\n  secret: " + testSecret2 + "\n",
+		},
+		{
+			name:    "short secret",
+			content: "primary_key: k1\nkeys:\n- id: k1\n  secret: short\n",
+		},
+	}
+
+	for _, c := range cases {
+		err := registerKeys(t, c.content)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expect an error, but got nil", c.name)
+		}
+	}
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	mustRegisterKeys(t, "k1", map[string]string{"k1": testSecret1})
+
+	plaintext := []byte("token of org email")
+	e := mustEncrypt(t, plaintext, testOwner)
+
+	if e.KeyID != "k1" {
+		t.Errorf("expect key id k1, but got %s", e.KeyID)
+	}
+	if bytes.Contains(e.Ciphertext, plaintext) {
+		t.Error("the ciphertext includes the plaintext")
+	}
+
+	b, err := Decrypt(e, testOwner)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if !bytes.Equal(b, plaintext) {
+		t.Errorf("expect %q, but got %q", plaintext, b)
+	}
+
+	// the data key is random, so the same plaintext is encrypted differently
+	if e1 := mustEncrypt(t, plaintext, testOwner); bytes.Equal(e1.Ciphertext, e.Ciphertext) {
+		t.Error("the ciphertexts of same plaintext must be different")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	mustRegisterKeys(t, "k1", map[string]string{"k1": testSecret1})
+	old := mustEncrypt(t, []byte("old"), testOwner)
+
+	// k2 becomes the primary key, but k1 is kept to decrypt the old data
+	mustRegisterKeys(t, "k2", map[string]string{"k1": testSecret1, "k2": testSecret2})
+
+	if PrimaryKeyID() != "k2" {
+		t.Errorf("expect primary key k2, but got %s", PrimaryKeyID())
+	}
+
+	if b, err := Decrypt(old, testOwner); err != nil || string(b) != "old" {
+		t.Errorf("decrypt the data of old key: %q, %v", b, err)
+	}
+
+	e := mustEncrypt(t, []byte("new"), testOwner)
+	if e.KeyID != "k2" {
+		t.Errorf("expect the new data to be encrypted by k2, but got %s", e.KeyID)
+	}
+
+	// k1 is removed after all the data is re-encrypted
+	mustRegisterKeys(t, "k2", map[string]string{"k2": testSecret2})
+
+	if _, err := Decrypt(old, testOwner); err == nil {
+		t.Error("expect an error when the master key is removed")
+	}
+	if b, err := Decrypt(e, testOwner); err != nil || string(b) != "new" {
+		t.Errorf("decrypt the data of new key: %q, %v", b, err)
+	}
+}
+
+func TestDecryptByWrongKey(t *testing.T) {
+	mustRegisterKeys(t, "k1", map[string]string{"k1": testSecret1})
+	e := mustEncrypt(t, []byte("token"), testOwner)
+
+	// the key of same id is configured with other secret
+	mustRegisterKeys(t, "k1", map[string]string{"k1": testSecret2})
+
+	if _, err := Decrypt(e, testOwner); err == nil {
+		t.Error("expect an error when decrypting by the wrong key")
+	}
+}
+
+func TestDecryptTamperedEnvelope(t *testing.T) {
+	mustRegisterKeys(t, "k1", map[string]string{"k1": testSecret1, "k2": testSecret2})
+	e := mustEncrypt(t, []byte("token"), testOwner)
+
+	flip := func(b []byte) []byte {
+		v := append([]byte(nil), b...)
+		v[len(v)-1] ^= 1
+		return v
+	}
+
+	// the envelope is encrypted by k2 for k1, so only the additional data
+	// can tell that its key id is changed to k2.
+	mk := util.DeriveKey(testSecret2)
+	dk := make([]byte, 32)
+	ek, err := util.Encrypt(dk, mk, additionalData("k1", testOwner))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := util.Encrypt([]byte("token"), dk, additionalData("k1", testOwner))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		e     Envelope
+		owner string
+	}{
+		{"other owner", e, "cla@other.com"},
+		{"tampered ciphertext", Envelope{KeyID: e.KeyID, EncryptedKey: e.EncryptedKey, Ciphertext: flip(e.Ciphertext)}, testOwner},
+		{"tampered data key", Envelope{KeyID: e.KeyID, EncryptedKey: flip(e.EncryptedKey), Ciphertext: e.Ciphertext}, testOwner},
+		{"truncated ciphertext", Envelope{KeyID: e.KeyID, EncryptedKey: e.EncryptedKey, Ciphertext: e.Ciphertext[:4]}, testOwner},
+		{"other key id", Envelope{KeyID: "k2", EncryptedKey: ek, Ciphertext: ciphertext}, testOwner},
+	}
+
+	for _, c := range cases {
+		if _, err := Decrypt(c.e, c.owner); err == nil {
+			t.Errorf("%s: expect an error, but got nil", c.name)
+		}
+	}
+
+	if _, err := DecryptLegacy(e); err == nil {
+		t.Error("the envelope with additional data can't be decrypted as legacy one")
+	}
+}
+
+func TestDecryptLegacy(t *testing.T) {
+	mustRegisterKeys(t, "k1", map[string]string{"k1": testSecret1})
+
+	// the early versions encrypted the data without the additional data
+	dk := make([]byte, 32)
+	ek, err := util.Encrypt(dk, util.DeriveKey(testSecret1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := util.Encrypt([]byte("token"), dk, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Envelope{KeyID: "k1", EncryptedKey: ek, Ciphertext: ciphertext}
+
+	if b, err := DecryptLegacy(e); err != nil || string(b) != "token" {
+		t.Errorf("decrypt legacy envelope: %q, %v", b, err)
+	}
+
+	if _, err := Decrypt(e, testOwner); err == nil {
+		t.Error("the legacy envelope can't be decrypted with the additional data")
+	}
+}
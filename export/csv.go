@@ -0,0 +1,40 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the table as csv to w row by row.
+func WriteCSV(w io.Writer, t *Table) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(escapeCSVRow(t.Header)); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		if err := cw.Write(escapeCSVRow(row)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeCSVRow prevents the cell from being interpreted as formula by spreadsheet,
+// because the values, such as the name of signer, are input by users.
+func escapeCSVRow(row []string) []string {
+	r := make([]string, len(row))
+	for i, v := range row {
+		if v != "" {
+			switch v[0] {
+			case '=', '+', '-', '@', '\t', '\r':
+				v = "'" + v
+			}
+		}
+		r[i] = v
+	}
+	return r
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	table := &Table{
+		Name:   "individual",
+		Header: []string{"name", "email"},
+	}
+	table.AddRow([]string{"alice", "a@x.com"})
+	table.AddRow([]string{"=HYPERLINK(\"http://x\")", "+1"})
+	table.AddRow([]string{"-1", "@SUM(A1)"})
+	table.AddRow([]string{"\tname", "\rname"})
+	table.AddRow([]string{"", "a=b, \"quoted\"\nline"})
+
+	var b bytes.Buffer
+	if err := WriteCSV(&b, table); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+
+	want := [][]string{
+		{"name", "email"},
+		{"alice", "a@x.com"},
+		{"'=HYPERLINK(\"http://x\")", "'+1"},
+		{"'-1", "'@SUM(A1)"},
+		{"'\tname", "'\rname"},
+		{"", "a=b, \"quoted\"\nline"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("expect %q, but got %q", want, rows)
+	}
+}
@@ -0,0 +1,12 @@
+package export
+
+// Table is the data to be exported. It is a sheet of xlsx file.
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]string
+}
+
+func (this *Table) AddRow(row []string) {
+	this.Rows = append(this.Rows, row)
+}
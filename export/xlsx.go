@@ -0,0 +1,157 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+%s</Types>`
+
+	xlsxContentTypeOfSheet = `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets>
+%s</sheets>
+</workbook>`
+
+	xlsxWorkbookSheet = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>
+`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+%s</Relationships>`
+
+	xlsxWorkbookRelOfSheet = `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>
+`
+
+	xlsxSheetBegin = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>
+`
+	xlsxSheetEnd = `</sheetData>
+</worksheet>`
+
+	// the max length of sheet name which is limited by excel
+	maxSheetNameLength = 31
+)
+
+// WriteXLSX writes the tables as the sheets of a xlsx file to w.
+// All the cells are written as inline strings.
+func WriteXLSX(w io.Writer, tables []*Table) error {
+	zw := zip.NewWriter(w)
+
+	contentTypes := ""
+	sheets := ""
+	rels := ""
+	for i, t := range tables {
+		n := i + 1
+
+		contentTypes += fmt.Sprintf(xlsxContentTypeOfSheet, n)
+		sheets += fmt.Sprintf(xlsxWorkbookSheet, escapeXML(sheetName(t.Name, n)), n, n)
+		rels += fmt.Sprintf(xlsxWorkbookRelOfSheet, n, n)
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, contentTypes)},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, sheets)},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, rels)},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+
+	for i, t := range tables {
+		fw, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+
+		if err := writeSheet(fw, t); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeSheet(w io.Writer, t *Table) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(xlsxSheetBegin)
+
+	writeSheetRow(bw, 1, t.Header)
+	for i, row := range t.Rows {
+		writeSheetRow(bw, i+2, row)
+	}
+
+	bw.WriteString(xlsxSheetEnd)
+
+	return bw.Flush()
+}
+
+func writeSheetRow(bw *bufio.Writer, n int, row []string) {
+	rn := strconv.Itoa(n)
+
+	bw.WriteString(`<row r="` + rn + `">`)
+	for i, v := range row {
+		bw.WriteString(`<c r="` + columnName(i) + rn + `" t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(bw, []byte(v))
+		bw.WriteString(`</t></is></c>`)
+	}
+	bw.WriteString("</row>\n")
+}
+
+// columnName returns the name of column, such as A, Z, AA.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func sheetName(name string, n int) string {
+	if name == "" {
+		return fmt.Sprintf("Sheet%d", n)
+	}
+
+	if r := []rune(name); len(r) > maxSheetNameLength {
+		return string(r[:maxSheetNameLength])
+	}
+	return name
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
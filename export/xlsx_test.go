@@ -0,0 +1,153 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testSheet struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R       string    `xml:"r,attr"`
+			T       string    `xml:"t,attr"`
+			Formula *struct{} `xml:"f"`
+			Text    string    `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type testWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+func readXLSXFile(t *testing.T, r *zip.Reader, name string, v interface{}) {
+	t.Helper()
+
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rc.Close()
+
+		b, err := ioutil.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := xml.Unmarshal(b, v); err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		return
+	}
+	t.Fatalf("missing %s", name)
+}
+
+// readXLSX parses the xlsx file back to the names of sheets and their rows.
+func readXLSX(t *testing.T, data []byte) ([]string, [][][]string) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+
+	var wb testWorkbook
+	readXLSXFile(t, r, "xl/workbook.xml", &wb)
+
+	names := make([]string, 0, len(wb.Sheets))
+	sheets := make([][][]string, 0, len(wb.Sheets))
+	for i, s := range wb.Sheets {
+		names = append(names, s.Name)
+
+		var sheet testSheet
+		readXLSXFile(t, r, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), &sheet)
+
+		rows := make([][]string, 0, len(sheet.Rows))
+		for j, row := range sheet.Rows {
+			rn := fmt.Sprint(j + 1)
+			if row.R != rn {
+				t.Errorf("expect row %s, but got %s", rn, row.R)
+			}
+
+			cells := make([]string, 0, len(row.Cells))
+			for k, c := range row.Cells {
+				if c.T != "inlineStr" || c.Formula != nil {
+					t.Errorf("the cell %s must be an inline string", c.R)
+				}
+				if ref := columnName(k) + rn; c.R != ref {
+					t.Errorf("expect cell %s, but got %s", ref, c.R)
+				}
+				cells = append(cells, c.Text)
+			}
+			rows = append(rows, cells)
+		}
+		sheets = append(sheets, rows)
+	}
+
+	return names, sheets
+}
+
+func TestWriteXLSX(t *testing.T) {
+	individual := &Table{
+		Name:   "individual",
+		Header: []string{"name", "email"},
+	}
+	individual.AddRow([]string{"=1+1", "a@x.com"})
+	individual.AddRow([]string{"<b>&\"name\"</b>", " spaces "})
+
+	wide := &Table{
+		Name:   strings.Repeat("s", maxSheetNameLength+5),
+		Header: make([]string, 28),
+	}
+	for i := range wide.Header {
+		wide.Header[i] = fmt.Sprint(i)
+	}
+
+	tables := []*Table{individual, wide, {}}
+
+	var b bytes.Buffer
+	if err := WriteXLSX(&b, tables); err != nil {
+		t.Fatal(err)
+	}
+
+	names, sheets := readXLSX(t, b.Bytes())
+
+	wantNames := []string{"individual", strings.Repeat("s", maxSheetNameLength), "Sheet3"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("expect sheets %v, but got %v", wantNames, names)
+	}
+
+	// the formula is kept as text, because all the cells are inline strings.
+	want := [][]string{
+		{"name", "email"},
+		{"=1+1", "a@x.com"},
+		{"<b>&\"name\"</b>", " spaces "},
+	}
+	if !reflect.DeepEqual(sheets[0], want) {
+		t.Errorf("expect %q, but got %q", want, sheets[0])
+	}
+
+	if !reflect.DeepEqual(sheets[1], [][]string{wide.Header}) {
+		t.Errorf("expect %q, but got %q", wide.Header, sheets[1])
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for i, want := range cases {
+		if got := columnName(i); got != want {
+			t.Errorf("column %d: expect %s, but got %s", i, want, got)
+		}
+	}
+}
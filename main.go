@@ -1,7 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/astaxie/beego"
 
@@ -9,8 +14,13 @@ import (
 	"github.com/opensourceways/app-cla-server/conf"
 	"github.com/opensourceways/app-cla-server/dbmodels"
 	"github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/encryption"
+	"github.com/opensourceways/app-cla-server/metrics"
+	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/mongodb"
 	"github.com/opensourceways/app-cla-server/pdf"
+	"github.com/opensourceways/app-cla-server/postgresql"
+	"github.com/opensourceways/app-cla-server/robot"
 	_ "github.com/opensourceways/app-cla-server/routers"
 	"github.com/opensourceways/app-cla-server/worker"
 )
@@ -27,38 +37,161 @@ func main() {
 	}
 	AppConfig := conf.AppConfig
 
-	c, err := mongodb.RegisterDatabase(AppConfig.MongodbConn, AppConfig.DBName)
+	c, err := registerDatabase()
 	if err != nil {
 		beego.Error(err)
 		os.Exit(1)
 	}
 	dbmodels.RegisterDB(c)
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(c, os.Args[2:])
+		return
+	}
+
+	if err := c.CheckSchemaVersion(); err != nil {
+		beego.Error(err)
+		os.Exit(1)
+	}
+
+	if err := encryption.RegisterKeys(AppConfig.MasterKeyConfigFile); err != nil {
+		beego.Error(err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1])
+		return
+	}
+
 	if err = email.RegisterPlatform(AppConfig.EmailPlatformConfigFile); err != nil {
 		beego.Error(err)
 		os.Exit(1)
 	}
+	go email.WatchPlatforms(time.Minute)
 
 	if err := platformAuth.RegisterPlatform(AppConfig.CodePlatformConfigFile); err != nil {
 		beego.Error(err)
 		os.Exit(1)
 	}
 
+	// the robot is optional
+	if AppConfig.RobotConfigFile != "" {
+		if err := robot.RegisterRobot(AppConfig.RobotConfigFile); err != nil {
+			beego.Error(err)
+			os.Exit(1)
+		}
+	}
+
 	if err := pdf.GenBlankSignaturePage(); err != nil {
 		beego.Info(err)
 		return
 	}
 
 	if err := pdf.InitPDFGenerator(
-		AppConfig.PythonBin,
 		AppConfig.PDFOutDir,
 		AppConfig.PDFOrgSignatureDir,
+		AppConfig.PDFFontConfigFile,
 	); err != nil {
 		beego.Error(err)
 		os.Exit(1)
 	}
 
-	worker.InitEmailWorker(pdf.GetPDFGenerator())
+	worker.InitEmailWorker(
+		pdf.GetPDFGenerator(),
+		AppConfig.EmailWorkerNumber,
+		AppConfig.EmailMaxAttempts,
+	)
+
+	go exitOnSignal()
+
+	beego.RunWithMiddleWares("", metrics.Middleware)
+}
+
+func exitOnSignal() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+
+	<-sig
+	beego.Info("server is shutting down")
+
+	worker.GetEmailWorker().Shutdown()
+	os.Exit(0)
+}
+
+// runCommand runs the admin command instead of starting the server.
+func runCommand(cmd string) {
+	switch cmd {
+	case "rotate-keys":
+		n, err := models.RotateOrgEmailKeys()
+		beego.Info(fmt.Sprintf("the tokens of %d org emails are re-encrypted", n))
+		if err != nil {
+			beego.Error(err)
+			os.Exit(1)
+		}
+
+	default:
+		beego.Error(fmt.Sprintf("unknown command: %s", cmd))
+		os.Exit(1)
+	}
+}
+
+type dbMigrator interface {
+	SchemaVersion() (int, error)
+	Migrate(target int) (int, error)
+}
+
+// database is the implementation of dbmodels.IDB whose schema can be migrated.
+type database interface {
+	dbmodels.IDB
+	dbMigrator
+	CheckSchemaVersion() error
+}
+
+// registerDatabase connects to the database which is chosen by db_driver.
+func registerDatabase() (database, error) {
+	cfg := conf.AppConfig
+
+	switch cfg.DBDriver {
+	case conf.DBDriverPostgresql:
+		c, err := postgresql.RegisterDatabase(cfg.PostgresqlConn)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+
+	default:
+		c, err := mongodb.RegisterDatabase(cfg.MongodbConn, cfg.DBName)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+// runMigrate migrates the database to the version passed by args,
+// or the latest version if it is not passed.
+func runMigrate(db dbMigrator, args []string) {
+	target := -1
+	if len(args) > 0 {
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 0 {
+			beego.Error(fmt.Sprintf("invalid schema version: %s", args[0]))
+			os.Exit(1)
+		}
+		target = v
+	}
+
+	current, err := db.SchemaVersion()
+	if err != nil {
+		beego.Error(err)
+		os.Exit(1)
+	}
 
-	beego.Run()
+	v, err := db.Migrate(target)
+	beego.Info(fmt.Sprintf("the schema version of database is migrated from %d to %d", current, v))
+	if err != nil {
+		beego.Error(err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,133 @@
+package memorydb
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (c *client) AddRefreshToken(token dbmodels.RefreshToken) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// clean up the expired tokens by the way
+	now := util.Now()
+	for k, v := range c.refreshTokens {
+		if v.Expiry < now {
+			delete(c.refreshTokens, k)
+		}
+	}
+
+	if _, ok := c.refreshTokens[token.ID]; ok {
+		return fmt.Errorf("failed to add refresh token: duplicate token")
+	}
+
+	c.refreshTokens[token.ID] = token
+	return nil
+}
+
+func (c *client) UseRefreshToken(tokenID string) (dbmodels.RefreshToken, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.refreshTokens[tokenID]
+	if !ok {
+		return dbmodels.RefreshToken{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidRefreshToken,
+			Err:     fmt.Errorf("unknown refresh token"),
+		}
+	}
+	delete(c.refreshTokens, tokenID)
+
+	if v.Expiry < util.Now() {
+		return dbmodels.RefreshToken{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidRefreshToken,
+			Err:     fmt.Errorf("refresh token is expired"),
+		}
+	}
+	return v, nil
+}
+
+func (c *client) DeleteRefreshTokensOfSession(sessionID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for k, v := range c.refreshTokens {
+		if v.SessionID == sessionID {
+			delete(c.refreshTokens, k)
+		}
+	}
+	return nil
+}
+
+func (c *client) RevokeAccessSession(sessionID string, expiry int64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// the session which has expired needn't be kept in the revocation list
+	now := util.Now()
+	for k, v := range c.revokedSession {
+		if v < now {
+			delete(c.revokedSession, k)
+		}
+	}
+
+	c.revokedSession[sessionID] = expiry
+	return nil
+}
+
+func (c *client) IsAccessSessionRevoked(sessionID string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, ok := c.revokedSession[sessionID]
+	return ok, nil
+}
+
+func (c *client) SaveAccessSession(session dbmodels.AccessSession) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := util.Now()
+	for k, v := range c.accessSessions {
+		if v.Expiry < now {
+			delete(c.accessSessions, k)
+		}
+	}
+
+	session.PlatformToken = cloneBytes(session.PlatformToken)
+	c.accessSessions[session.ID] = session
+	return nil
+}
+
+func (c *client) GetAccessSession(sessionID string) (dbmodels.AccessSession, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.accessSessions[sessionID]
+	if !ok {
+		return dbmodels.AccessSession{}, dbmodels.DBError{
+			ErrCode: util.ErrSessionExpired,
+			Err:     fmt.Errorf("unknown session"),
+		}
+	}
+
+	if v.Expiry < util.Now() {
+		return dbmodels.AccessSession{}, dbmodels.DBError{
+			ErrCode: util.ErrSessionExpired,
+			Err:     fmt.Errorf("session is expired"),
+		}
+	}
+
+	v.PlatformToken = cloneBytes(v.PlatformToken)
+	return v, nil
+}
+
+func (c *client) DeleteAccessSession(sessionID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.accessSessions, sessionID)
+	return nil
+}
@@ -0,0 +1,67 @@
+package memorydb
+
+import (
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func cloneStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	return r
+}
+
+func cloneAuditLog(v dbmodels.AuditLog) dbmodels.AuditLog {
+	v.Before = cloneStringMap(v.Before)
+	v.After = cloneStringMap(v.After)
+	return v
+}
+
+func (c *client) AddAuditLog(log dbmodels.AuditLog) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	log.ID, _ = c.newID()
+	c.auditLogs = append(c.auditLogs, cloneAuditLog(log))
+
+	return nil
+}
+
+func (c *client) ListAuditLog(opt dbmodels.AuditLogListOption) ([]dbmodels.AuditLog, int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v := make([]dbmodels.AuditLog, 0)
+	for _, item := range c.auditLogs {
+		if item.Platform == opt.Platform && item.OrgID == opt.OrgID &&
+			(opt.CorpID == "" || item.CorpID == opt.CorpID) &&
+			(opt.Action == "" || item.Action == opt.Action) {
+			v = append(v, item)
+		}
+	}
+
+	sort.SliceStable(v, func(i, j int) bool {
+		if v[i].Time != v[j].Time {
+			if opt.Page.Desc {
+				return v[i].Time > v[j].Time
+			}
+			return v[i].Time < v[j].Time
+		}
+		return v[i].ID < v[j].ID
+	})
+
+	start, end := pageRange(len(v), opt.Page)
+
+	r := make([]dbmodels.AuditLog, 0, end-start)
+	for _, item := range v[start:end] {
+		r = append(r, cloneAuditLog(item))
+	}
+	return r, len(v), nil
+}
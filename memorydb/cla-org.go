@@ -0,0 +1,335 @@
+package memorydb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type bindingItem struct {
+	dbmodels.CLAOrg
+
+	seq          int
+	orgSignature []byte
+}
+
+func (item *bindingItem) toModel() dbmodels.CLAOrg {
+	r := item.CLAOrg
+	r.CLAVersion = normalizeCLAVersion(r.CLAVersion)
+	if len(r.CLAVersions) == 0 {
+		r.CLAVersions = nil
+	} else {
+		r.CLAVersions = append([]dbmodels.CLAVersion{}, r.CLAVersions...)
+	}
+	return r
+}
+
+func errNoCLABinding() error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrNoCLABindingDoc,
+		Err:     fmt.Errorf("can't find cla binding"),
+	}
+}
+
+// findBinding returns the binding which is applied to applyTo and is enabled.
+// It returns nil if not found. The applyTo is not checked if it is empty.
+func (c *client) findBinding(claOrgID, applyTo string) *bindingItem {
+	item, ok := c.bindings[claOrgID]
+	if !ok || !item.Enabled || (applyTo != "" && item.ApplyTo != applyTo) {
+		return nil
+	}
+	return item
+}
+
+func (c *client) CreateBindingBetweenCLAAndOrg(claOrg dbmodels.CLAOrg) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, item := range c.bindings {
+		if item.Enabled && item.Platform == claOrg.Platform && item.OrgID == claOrg.OrgID &&
+			item.RepoID == claOrg.RepoID && item.CLALanguage == claOrg.CLALanguage &&
+			item.ApplyTo == claOrg.ApplyTo {
+			return "", fmt.Errorf("the org/repo:%s/%s/%s has already been bound a cla with language:%s",
+				claOrg.Platform, claOrg.OrgID, claOrg.RepoID, claOrg.CLALanguage)
+		}
+	}
+
+	id, seq := c.newID()
+
+	claOrg.ID = id
+	claOrg.OrgSignatureUploaded = false
+	claOrg.CLAVersion = 1
+	claOrg.ResignRequired = false
+	claOrg.ResignDeadline = 0
+	claOrg.CLAVersions = []dbmodels.CLAVersion{
+		{Version: 1, CLAID: claOrg.CLAID, PublishedAt: util.Now()},
+	}
+
+	c.bindings[id] = &bindingItem{CLAOrg: claOrg, seq: seq}
+	return id, nil
+}
+
+func (c *client) DeleteBindingBetweenCLAAndOrg(uid string) error {
+	if err := checkID(uid); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if item, ok := c.bindings[uid]; ok {
+		item.Enabled = false
+	}
+	return nil
+}
+
+func (c *client) GetBindingBetweenCLAAndOrg(uid string) (dbmodels.CLAOrg, error) {
+	if err := checkID(uid); err != nil {
+		return dbmodels.CLAOrg{}, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	item, ok := c.bindings[uid]
+	if !ok {
+		return dbmodels.CLAOrg{}, errNoCLABinding()
+	}
+	return item.toModel(), nil
+}
+
+func (c *client) ListBindingBetweenCLAAndOrg(opt dbmodels.CLAOrgListOption) ([]dbmodels.CLAOrg, int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v := c.listBindings(func(item *bindingItem) bool {
+		return item.Platform == opt.Platform && item.OrgID == opt.OrgID &&
+			(opt.RepoID == "" || item.RepoID == opt.RepoID) &&
+			(opt.ApplyTo == "" || item.ApplyTo == opt.ApplyTo)
+	})
+
+	if opt.Page.Desc {
+		for i, j := 0, len(v)-1; i < j; i, j = i+1, j-1 {
+			v[i], v[j] = v[j], v[i]
+		}
+	}
+
+	start, end := pageRange(len(v), opt.Page)
+
+	r := make([]dbmodels.CLAOrg, 0, end-start)
+	for _, item := range v[start:end] {
+		r = append(r, item.toModel())
+	}
+	return r, len(v), nil
+}
+
+func (c *client) ListBindingForSigningPage(opt dbmodels.CLAOrgListOption) ([]dbmodels.CLAOrg, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v := c.listBindings(func(item *bindingItem) bool {
+		return item.Platform == opt.Platform && item.OrgID == opt.OrgID &&
+			(opt.ApplyTo == "" || item.ApplyTo == opt.ApplyTo) &&
+			(item.RepoID == "" || item.RepoID == opt.RepoID)
+	})
+
+	r := make([]dbmodels.CLAOrg, 0, len(v))
+
+	// if the repo has not been bound any clas, return clas bound to org
+	if opt.RepoID != "" {
+		for _, item := range v {
+			if item.RepoID == opt.RepoID {
+				r = append(r, item.toModel())
+			}
+		}
+		if len(r) != 0 {
+			return r, nil
+		}
+	}
+
+	for _, item := range v {
+		r = append(r, item.toModel())
+	}
+	return r, nil
+}
+
+// listBindings returns the enabled bindings which match, sorted by created time.
+func (c *client) listBindings(match func(*bindingItem) bool) []*bindingItem {
+	r := make([]*bindingItem, 0)
+	for _, item := range c.bindings {
+		if item.Enabled && match(item) {
+			r = append(r, item)
+		}
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].seq < r[j].seq
+	})
+	return r
+}
+
+// listBindingIDs is same as listBindings, but only returns the ids.
+func (c *client) listBindingIDs(match func(*bindingItem) bool) map[string]bool {
+	r := map[string]bool{}
+	for _, item := range c.bindings {
+		if item.Enabled && match(item) {
+			r[item.ID] = true
+		}
+	}
+	return r
+}
+
+func (c *client) PublishCLAVersion(claOrgID string, opt dbmodels.CLAVersionPublishOption) (int, error) {
+	if err := checkID(claOrgID); err != nil {
+		return 0, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	item := c.findBinding(claOrgID, "")
+	if item == nil {
+		return 0, errNoCLABinding()
+	}
+
+	if item.CLAID == opt.CLAID {
+		return 0, dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("the cla is the current version"),
+		}
+	}
+
+	current := normalizeCLAVersion(item.CLAVersion)
+	version := current + 1
+
+	if len(item.CLAVersions) == 0 {
+		// the binding was created before the cla version is introduced
+		item.CLAVersions = append(item.CLAVersions, dbmodels.CLAVersion{Version: current, CLAID: item.CLAID})
+	}
+	item.CLAVersions = append(item.CLAVersions, dbmodels.CLAVersion{
+		Version: version, CLAID: opt.CLAID, PublishedAt: util.Now(),
+	})
+
+	item.CLAID = opt.CLAID
+	item.CLAVersion = version
+	item.ResignRequired = opt.ResignRequired
+	item.ResignDeadline = opt.ResignDeadline
+
+	return version, nil
+}
+
+func (c *client) ListOutdatedSignings(claOrgID string, page dbmodels.PageOption) (dbmodels.OutdatedSignings, error) {
+	var r dbmodels.OutdatedSignings
+
+	if err := checkID(claOrgID); err != nil {
+		return r, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	binding, ok := c.bindings[claOrgID]
+	if !ok {
+		return r, errNoCLABinding()
+	}
+
+	r.CLAVersion = normalizeCLAVersion(binding.CLAVersion)
+
+	individuals := make([]*individualSigningItem, 0)
+	for _, item := range c.individuals {
+		if item.claOrgID == claOrgID && normalizeCLAVersion(item.CLAVersion) < r.CLAVersion {
+			individuals = append(individuals, item)
+		}
+	}
+	sort.SliceStable(individuals, func(i, j int) bool {
+		a, b := individuals[i], individuals[j]
+		if page.SortBy == dbmodels.SortByEmail {
+			return lessBy(page, a.Email, b.Email, a.Email, b.Email)
+		}
+		return lessBy(page, a.Date, b.Date, a.Email, b.Email)
+	})
+
+	start, end := pageRange(len(individuals), page)
+	r.IndividualTotal = len(individuals)
+	r.Individuals = make([]dbmodels.IndividualSigningBasicInfo, 0, end-start)
+	for _, item := range individuals[start:end] {
+		r.Individuals = append(r.Individuals, item.toBasicInfo())
+	}
+
+	corps := make([]*corpSigningItem, 0)
+	for _, item := range c.corps {
+		if item.claOrgID == claOrgID && normalizeCLAVersion(item.CLAVersion) < r.CLAVersion {
+			corps = append(corps, item)
+		}
+	}
+	sort.SliceStable(corps, func(i, j int) bool {
+		a, b := corps[i], corps[j]
+		if page.SortBy == dbmodels.SortByEmail {
+			return lessBy(page, a.AdminEmail, b.AdminEmail, a.AdminEmail, b.AdminEmail)
+		}
+		return lessBy(page, a.Date, b.Date, a.AdminEmail, b.AdminEmail)
+	})
+
+	start, end = pageRange(len(corps), page)
+	r.CorporationTotal = len(corps)
+	r.Corporations = make([]dbmodels.CorporationSigningBasicInfo, 0, end-start)
+	for _, item := range corps[start:end] {
+		r.Corporations = append(r.Corporations, item.toDetail().CorporationSigningBasicInfo)
+	}
+
+	return r, nil
+}
+
+func (c *client) UploadOrgSignature(claOrgID string, pdf []byte) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if item, ok := c.bindings[claOrgID]; ok {
+		item.orgSignature = cloneBytes(pdf)
+		item.OrgSignatureUploaded = true
+	}
+	return nil
+}
+
+func (c *client) DownloadOrgSignature(claOrgID string) ([]byte, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	item, ok := c.bindings[claOrgID]
+	if !ok || !item.OrgSignatureUploaded {
+		return nil, fmt.Errorf("can't find the org signature")
+	}
+	return cloneBytes(item.orgSignature), nil
+}
+
+func (c *client) UploadBlankSignature(language string, pdf []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// the first one is kept like mongodb does
+	if _, ok := c.blankSigs[language]; !ok {
+		c.blankSigs[language] = cloneBytes(pdf)
+	}
+	return nil
+}
+
+func (c *client) DownloadBlankSignature(language string) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.blankSigs[language]
+	if !ok {
+		return nil, fmt.Errorf("can't find the blank signature of %s", language)
+	}
+	return cloneBytes(v), nil
+}
@@ -0,0 +1,118 @@
+package memorydb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+type claItem struct {
+	seq int
+	cla dbmodels.CLA
+}
+
+func cloneCLA(cla dbmodels.CLA) dbmodels.CLA {
+	if cla.Fields != nil {
+		cla.Fields = append([]dbmodels.Field{}, cla.Fields...)
+	}
+	return cla
+}
+
+func (c *client) CreateCLA(cla dbmodels.CLA) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, item := range c.clas {
+		if item.cla.Name == cla.Name && item.cla.Submitter == cla.Submitter {
+			return "", fmt.Errorf("the cla(%s) is already existing", cla.Name)
+		}
+	}
+
+	id, seq := c.newID()
+	cla.ID = id
+	c.clas[id] = &claItem{seq: seq, cla: cloneCLA(cla)}
+
+	return id, nil
+}
+
+func (c *client) DeleteCLA(uid string) error {
+	if err := checkID(uid); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, item := range c.bindings {
+		if item.CLAID == uid {
+			return fmt.Errorf("can't delete the cla which has already been bound to org")
+		}
+	}
+
+	delete(c.clas, uid)
+	return nil
+}
+
+func (c *client) ListCLA(opts dbmodels.CLAListOptions) ([]dbmodels.CLA, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.listCLA(func(cla *dbmodels.CLA) bool {
+		return cla.Submitter == opts.Submitter &&
+			(opts.Name == "" || cla.Name == opts.Name) &&
+			(opts.Language == "" || cla.Language == opts.Language) &&
+			(opts.ApplyTo == "" || cla.ApplyTo == opts.ApplyTo)
+	}), nil
+}
+
+func (c *client) ListCLAByIDs(ids []string) ([]dbmodels.CLA, error) {
+	m := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if err := checkID(id); err != nil {
+			return nil, err
+		}
+		m[id] = true
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.listCLA(func(cla *dbmodels.CLA) bool {
+		return m[cla.ID]
+	}), nil
+}
+
+func (c *client) GetCLA(uid string) (dbmodels.CLA, error) {
+	if err := checkID(uid); err != nil {
+		return dbmodels.CLA{}, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	item, ok := c.clas[uid]
+	if !ok {
+		return dbmodels.CLA{}, fmt.Errorf("can't find the cla")
+	}
+	return cloneCLA(item.cla), nil
+}
+
+func (c *client) listCLA(match func(*dbmodels.CLA) bool) []dbmodels.CLA {
+	items := make([]*claItem, 0, len(c.clas))
+	for _, item := range c.clas {
+		if match(&item.cla) {
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].seq < items[j].seq
+	})
+
+	r := make([]dbmodels.CLA, 0, len(items))
+	for _, item := range items {
+		r = append(r, cloneCLA(item.cla))
+	}
+	return r
+}
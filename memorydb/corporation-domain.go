@@ -0,0 +1,145 @@
+package memorydb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type corpDomainItem struct {
+	dbmodels.CorporationDomain
+
+	claOrgID string
+}
+
+func (c *client) findCorpDomain(claOrgID, domain string) *corpDomainItem {
+	for _, item := range c.corpDomains {
+		if item.claOrgID == claOrgID && item.Domain == domain {
+			return item
+		}
+	}
+	return nil
+}
+
+// corpIDOf returns the id of corporation which the email belongs to.
+func (c *client) corpIDOf(claOrgID, email string) string {
+	domain := util.EmailSuffix(email)
+
+	item := c.findCorpDomain(claOrgID, domain)
+	if item != nil && item.Status == dbmodels.CorpDomainStatusApproved {
+		return item.CorpID
+	}
+	return domain
+}
+
+func errCorpDomainExists(domain string) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrCorpDomainExists,
+		Err:     fmt.Errorf("the domain:%s has been owned by a corporation", domain),
+	}
+}
+
+func (c *client) AddCorporationDomain(claOrgID string, info dbmodels.CorporationDomain) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpBinding(claOrgID, util.ErrNoCLABindingDoc); err != nil {
+		return err
+	}
+
+	if c.findCorpSigningOfCorp(claOrgID, info.CorpID) == nil {
+		return errHasNotSigned("the corp:%s has not signed", info.CorpID)
+	}
+
+	if info.Domain == info.CorpID || c.findCorpSigningOfCorp(claOrgID, info.Domain) != nil {
+		return errCorpDomainExists(info.Domain)
+	}
+
+	// the rejected domain can be requested again.
+	item := c.findCorpDomain(claOrgID, info.Domain)
+	if item != nil {
+		if item.Status != dbmodels.CorpDomainStatusRejected {
+			return errCorpDomainExists(info.Domain)
+		}
+		item.CorporationDomain = info
+		return nil
+	}
+
+	c.corpDomains = append(c.corpDomains, &corpDomainItem{
+		CorporationDomain: info,
+		claOrgID:          claOrgID,
+	})
+	return nil
+}
+
+func (c *client) UpdateCorporationDomainStatus(claOrgID, domain, status string) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpBinding(claOrgID, util.ErrNoCLABindingDoc); err != nil {
+		return err
+	}
+
+	item := c.findCorpDomain(claOrgID, domain)
+	if item == nil || item.Status != dbmodels.CorpDomainStatusPending {
+		return dbmodels.DBError{
+			ErrCode: util.ErrNoPendingCorpDomain,
+			Err:     fmt.Errorf("no pending domain:%s", domain),
+		}
+	}
+
+	if status == dbmodels.CorpDomainStatusApproved && c.findCorpSigningOfCorp(claOrgID, domain) != nil {
+		return errCorpDomainExists(domain)
+	}
+
+	item.Status = status
+	return nil
+}
+
+func (c *client) ListCorporationDomain(claOrgID string, opt dbmodels.CorporationDomainListOption) ([]dbmodels.CorporationDomain, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpBinding(claOrgID, util.ErrNoCLABindingDoc); err != nil {
+		return nil, err
+	}
+
+	r := make([]dbmodels.CorporationDomain, 0)
+	for _, item := range c.corpDomains {
+		if item.claOrgID == claOrgID &&
+			(opt.CorpID == "" || item.CorpID == opt.CorpID) &&
+			(opt.Status == "" || item.Status == opt.Status) {
+			r = append(r, item.CorporationDomain)
+		}
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Domain < r[j].Domain
+	})
+	return r, nil
+}
+
+func (c *client) GetCorporationID(claOrgID, email string) (string, error) {
+	if err := checkID(claOrgID); err != nil {
+		return "", err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.corpIDOf(claOrgID, email), nil
+}
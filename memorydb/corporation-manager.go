@@ -0,0 +1,260 @@
+package memorydb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type corpManagerItem struct {
+	claOrgID         string
+	corpID           string
+	role             string
+	email            string
+	password         string
+	initialPWChanged bool
+}
+
+// checkCorpManagerBinding checks whether the binding exists and is applied to corporation.
+func (c *client) checkCorpManagerBinding(claOrgID string) error {
+	return c.checkCorpBinding(claOrgID, util.ErrNoCLABindingDoc)
+}
+
+// listCorpManagers returns the managers of the corporation which the email belongs to.
+// The role is not checked if it is empty.
+func (c *client) listCorpManagers(claOrgID, email, role string) []*corpManagerItem {
+	corpID := util.EmailSuffix(email)
+
+	r := make([]*corpManagerItem, 0)
+	for _, item := range c.managers {
+		if item.claOrgID == claOrgID && item.corpID == corpID && (role == "" || item.role == role) {
+			r = append(r, item)
+		}
+	}
+	return r
+}
+
+func (c *client) AddCorporationManager(claOrgID string, opt []dbmodels.CorporationManagerCreateOption, managerNumber int) ([]dbmodels.CorporationManagerCreateOption, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpManagerBinding(claOrgID); err != nil {
+		return nil, err
+	}
+
+	ms := c.listCorpManagers(claOrgID, opt[0].Email, opt[0].Role)
+
+	current := map[string]bool{}
+	for _, item := range ms {
+		current[item.email] = true
+	}
+
+	toAdd := make([]dbmodels.CorporationManagerCreateOption, 0, len(opt))
+	for _, item := range opt {
+		if !current[item.Email] {
+			toAdd = append(toAdd, item)
+			current[item.Email] = true
+		}
+	}
+
+	if len(toAdd) == 0 {
+		return toAdd, nil
+	}
+
+	if len(ms)+len(toAdd) > managerNumber {
+		return toAdd, dbmodels.DBError{
+			ErrCode: util.ErrNumOfCorpManagersExceeded,
+			Err:     fmt.Errorf("exceed %d managers allowed", managerNumber),
+		}
+	}
+
+	var signing *corpSigningItem
+	if opt[0].Role == dbmodels.RoleAdmin {
+		if signing = c.findCorpSigning(claOrgID, opt[0].Email); signing == nil {
+			return toAdd, errHasNotSigned("the corp:%s has not signed", util.EmailSuffix(opt[0].Email))
+		}
+	}
+
+	items := make([]*corpManagerItem, 0, len(toAdd))
+	for _, item := range toAdd {
+		pw, err := util.HashPassword(item.Password)
+		if err != nil {
+			return toAdd, fmt.Errorf("failed to hash password: %s", err.Error())
+		}
+
+		items = append(items, &corpManagerItem{
+			claOrgID: claOrgID,
+			corpID:   util.EmailSuffix(item.Email),
+			role:     item.Role,
+			email:    item.Email,
+			password: pw,
+		})
+	}
+
+	c.managers = append(c.managers, items...)
+	if signing != nil {
+		signing.AdminAdded = true
+		signing.Status = dbmodels.CorpSigningStatusAdminCreated
+	}
+	return toAdd, nil
+}
+
+func (c *client) CheckCorporationManagerExist(opt dbmodels.CorporationManagerCheckInfo) (map[string][]dbmodels.CorporationManagerCheckResult, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := map[string][]dbmodels.CorporationManagerCheckResult{}
+	for _, item := range c.managers {
+		if item.email != opt.User {
+			continue
+		}
+
+		binding := c.findBinding(item.claOrgID, dbmodels.ApplyToCorporation)
+		if binding == nil || !util.CheckPassword(item.password, opt.Password) {
+			continue
+		}
+
+		if !util.IsPasswordHashed(item.password) {
+			// It is the plaintext password stored by the early versions.
+			pw, err := util.HashPassword(item.password)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash password: %s", err.Error())
+			}
+			item.password = pw
+		}
+
+		result[binding.ID] = append(result[binding.ID], dbmodels.CorporationManagerCheckResult{
+			Email:            item.email,
+			Role:             item.role,
+			Platform:         binding.Platform,
+			OrgID:            binding.OrgID,
+			RepoID:           binding.RepoID,
+			InitialPWChanged: item.initialPWChanged,
+		})
+	}
+	return result, nil
+}
+
+func (c *client) ResetCorporationManagerPassword(claOrgID, email string, opt dbmodels.CorporationManagerResetPassword) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	pw, err := util.HashPassword(opt.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %s", err.Error())
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpManagerBinding(claOrgID); err != nil {
+		return err
+	}
+
+	invalid := dbmodels.DBError{
+		ErrCode: util.ErrInvalidParameter,
+		Err:     fmt.Errorf("invalid email or old password"),
+	}
+
+	for _, item := range c.listCorpManagers(claOrgID, email, "") {
+		if item.email != email {
+			continue
+		}
+
+		if !util.CheckPassword(item.password, opt.OldPassword) {
+			return invalid
+		}
+
+		item.password = pw
+		item.initialPWChanged = true
+		return nil
+	}
+	return invalid
+}
+
+func (c *client) ListCorporationManager(claOrgID, email, role string, page dbmodels.PageOption) ([]dbmodels.CorporationManagerListResult, int, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, 0, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpManagerBinding(claOrgID); err != nil {
+		return nil, 0, err
+	}
+
+	v := c.listCorpManagers(claOrgID, email, role)
+	sort.SliceStable(v, func(i, j int) bool {
+		return lessBy(page, v[i].email, v[j].email, "", "")
+	})
+
+	start, end := pageRange(len(v), page)
+
+	r := make([]dbmodels.CorporationManagerListResult, 0, end-start)
+	for _, item := range v[start:end] {
+		r = append(r, dbmodels.CorporationManagerListResult{
+			Email: item.email,
+			Role:  item.role,
+		})
+	}
+	return r, len(v), nil
+}
+
+func (c *client) DeleteCorporationManager(claOrgID string, opt []dbmodels.CorporationManagerCreateOption) ([]string, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	deleted := make([]string, 0, len(opt))
+
+	if err := c.checkCorpManagerBinding(claOrgID); err != nil {
+		return deleted, err
+	}
+
+	ms := c.listCorpManagers(claOrgID, opt[0].Email, opt[0].Role)
+
+	all := map[string]bool{}
+	for _, item := range ms {
+		all[item.email] = true
+	}
+
+	toDelete := map[string]bool{}
+	for _, item := range opt {
+		if all[item.Email] {
+			toDelete[item.Email] = true
+			deleted = append(deleted, item.Email)
+		}
+	}
+
+	if len(toDelete) == 0 {
+		return deleted, nil
+	}
+
+	removed := map[*corpManagerItem]bool{}
+	for _, item := range ms {
+		if toDelete[item.email] {
+			removed[item] = true
+		}
+	}
+
+	left := make([]*corpManagerItem, 0, len(c.managers))
+	for _, item := range c.managers {
+		if !removed[item] {
+			left = append(left, item)
+		}
+	}
+	c.managers = left
+
+	return deleted, nil
+}
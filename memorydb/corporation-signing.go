@@ -0,0 +1,329 @@
+package memorydb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type corpSigningItem struct {
+	dbmodels.CorporationSigningFullInfo
+
+	claOrgID string
+	corpID   string
+	pdf      []byte
+}
+
+func (item *corpSigningItem) toDetail() dbmodels.CorporationSigningDetail {
+	r := item.CorporationSigningDetail
+	r.CLAVersion = normalizeCLAVersion(r.CLAVersion)
+	return r
+}
+
+func (c *client) findCorpSigning(claOrgID, email string) *corpSigningItem {
+	return c.findCorpSigningOfCorp(claOrgID, util.EmailSuffix(email))
+}
+
+func (c *client) findCorpSigningOfCorp(claOrgID, corpID string) *corpSigningItem {
+	for _, item := range c.corps {
+		if item.claOrgID == claOrgID && item.corpID == corpID {
+			return item
+		}
+	}
+	return nil
+}
+
+// checkCorpBinding checks whether the binding exists and is applied to corporation.
+func (c *client) checkCorpBinding(claOrgID, errCode string) error {
+	if c.findBinding(claOrgID, dbmodels.ApplyToCorporation) == nil {
+		return dbmodels.DBError{
+			ErrCode: errCode,
+			Err:     fmt.Errorf("can't find the cla"),
+		}
+	}
+	return nil
+}
+
+func (c *client) SignAsCorporation(claOrgID, platform, org, repo string, info dbmodels.CorporationSigningInfo) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	hasSigned := dbmodels.DBError{
+		ErrCode: util.ErrHasSigned,
+		Err:     fmt.Errorf("this corp has already signed"),
+	}
+
+	b, signing, err := c.getCorporationSigningDetail(platform, org, repo, info.AdminEmail)
+	if err == nil {
+		if b.ID != claOrgID || !toSigningVersion(b, signing.CLAVersion).IsOutdated() {
+			return hasSigned
+		}
+
+		// re-sign the new version of cla
+		return c.resignAsCorporation(b, signing, info)
+	}
+	if !isHasNotSigned(err) {
+		return err
+	}
+
+	binding, ok := c.bindings[claOrgID]
+	if !ok {
+		return errNoCLABinding()
+	}
+
+	if c.findCorpSigning(claOrgID, info.AdminEmail) != nil {
+		return hasSigned
+	}
+
+	item := &corpSigningItem{
+		claOrgID: claOrgID,
+		corpID:   util.EmailSuffix(info.AdminEmail),
+	}
+	item.CorporationSigningBasicInfo = info.CorporationSigningBasicInfo
+	item.CLAVersion = normalizeCLAVersion(binding.CLAVersion)
+	item.Info = cloneSigningInfo(info.Info)
+	item.Status = dbmodels.CorpSigningStatusSubmitted
+
+	c.corps = append(c.corps, item)
+	return nil
+}
+
+// resignAsCorporation replaces the outdated signing by the one of new cla version.
+// The pdf has to be uploaded and reviewed again, but the managers are kept.
+func (c *client) resignAsCorporation(binding *bindingItem, item *corpSigningItem, info dbmodels.CorporationSigningInfo) error {
+	if item.AdminEmail != info.AdminEmail {
+		return dbmodels.DBError{
+			ErrCode: util.ErrHasSigned,
+			Err:     fmt.Errorf("this corp can only re-sign by its administrator"),
+		}
+	}
+
+	item.AdminName = info.AdminName
+	item.CorporationName = info.CorporationName
+	item.Date = info.Date
+	item.Info = cloneSigningInfo(info.Info)
+	item.CLAVersion = normalizeCLAVersion(binding.CLAVersion)
+	item.PDFUploaded = false
+	item.Status = dbmodels.CorpSigningStatusSubmitted
+	item.ReviewComment = ""
+	item.pdf = nil
+	return nil
+}
+
+func (c *client) getCorporationSigningDetail(platform, org, repo, email string) (*bindingItem, *corpSigningItem, error) {
+	bindings, err := c.bindingsOfSigning(platform, org, repo, dbmodels.ApplyToCorporation, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, b := range bindings {
+		if item := c.findCorpSigningOfCorp(b.ID, c.corpIDOf(b.ID, email)); item != nil {
+			return b, item, nil
+		}
+	}
+
+	return nil, nil, errHasNotSigned(
+		"the corp/individual has not signed for this org/repo: %s/%s/%s", platform, org, repo,
+	)
+}
+
+func (c *client) GetCorporationSigningDetail(platform, org, repo, email string) (string, dbmodels.CorporationSigningDetail, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	binding, item, err := c.getCorporationSigningDetail(platform, org, repo, email)
+	if err != nil {
+		return "", dbmodels.CorporationSigningDetail{}, err
+	}
+	return binding.ID, item.toDetail(), nil
+}
+
+func (c *client) CheckCorporationSigning(claOrgID, email string) (dbmodels.CorporationSigningDetail, error) {
+	if err := checkID(claOrgID); err != nil {
+		return dbmodels.CorporationSigningDetail{}, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpBinding(claOrgID, util.ErrNoCLABindingDoc); err != nil {
+		return dbmodels.CorporationSigningDetail{}, err
+	}
+
+	item := c.findCorpSigning(claOrgID, email)
+	if item == nil {
+		return dbmodels.CorporationSigningDetail{}, errHasNotSigned(
+			"the corp:%s has not signed", util.EmailSuffix(email),
+		)
+	}
+	return item.toDetail(), nil
+}
+
+func (c *client) UploadCorporationSigningPDF(claOrgID, adminEmail string, pdf []byte) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpBinding(claOrgID, util.ErrInvalidParameter); err != nil {
+		return err
+	}
+
+	item := c.findCorpSigning(claOrgID, adminEmail)
+	if item == nil {
+		return dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't find the corp signing record"),
+		}
+	}
+
+	if !dbmodels.CanChangeCorpSigningStatus(item.Status, dbmodels.CorpSigningStatusPDFUploaded) {
+		return errInvalidSigningStatus(item.Status, dbmodels.CorpSigningStatusPDFUploaded)
+	}
+
+	item.PDFUploaded = true
+	item.Status = dbmodels.CorpSigningStatusPDFUploaded
+	item.pdf = cloneBytes(pdf)
+	return nil
+}
+
+func (c *client) UpdateCorporationSigningStatus(claOrgID, email string, opt dbmodels.CorporationSigningStatusUpdate) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpBinding(claOrgID, util.ErrNoCLABindingDoc); err != nil {
+		return err
+	}
+
+	item := c.findCorpSigning(claOrgID, email)
+	if item == nil {
+		return errHasNotSigned("the corp:%s has not signed", util.EmailSuffix(email))
+	}
+
+	if !dbmodels.CanChangeCorpSigningStatus(item.Status, opt.Status) {
+		return errInvalidSigningStatus(item.Status, opt.Status)
+	}
+
+	item.Status = opt.Status
+	item.ReviewComment = opt.Comment
+	return nil
+}
+
+func errInvalidSigningStatus(from, to string) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrInvalidSigningStatus,
+		Err:     fmt.Errorf("can't change the status of corp signing from %s to %s", from, to),
+	}
+}
+
+func (c *client) DownloadCorporationSigningPDF(claOrgID, email string) ([]byte, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkCorpBinding(claOrgID, util.ErrInvalidParameter); err != nil {
+		return nil, err
+	}
+
+	item := c.findCorpSigning(claOrgID, email)
+	if item == nil {
+		return nil, dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't find the corp signing in this record"),
+		}
+	}
+
+	if !item.PDFUploaded {
+		return nil, dbmodels.DBError{
+			ErrCode: util.ErrPDFHasNotUploaded,
+			Err:     fmt.Errorf("pdf has not yet been uploaded"),
+		}
+	}
+	return cloneBytes(item.pdf), nil
+}
+
+// listCorpSignings returns the signings of bindings which match the option.
+func (c *client) listCorpSignings(opt dbmodels.CorporationSigningListOption) []*corpSigningItem {
+	ids := c.listBindingIDs(func(item *bindingItem) bool {
+		return item.ApplyTo == dbmodels.ApplyToCorporation &&
+			item.Platform == opt.Platform && item.OrgID == opt.OrgID &&
+			item.RepoID == opt.RepoID &&
+			(opt.CLALanguage == "" || item.CLALanguage == opt.CLALanguage)
+	})
+
+	r := make([]*corpSigningItem, 0)
+	for _, item := range c.corps {
+		if ids[item.claOrgID] {
+			r = append(r, item)
+		}
+	}
+	return r
+}
+
+func (c *client) ListCorporationSigning(opt dbmodels.CorporationSigningListOption) ([]dbmodels.CorporationSigningListItem, int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v := make([]*corpSigningItem, 0)
+	for _, item := range c.listCorpSignings(opt) {
+		if opt.Status != "" && item.Status != opt.Status {
+			continue
+		}
+		if matchSigningFilter(opt.Filter, item.AdminAdded, item.Date, item.AdminEmail) {
+			v = append(v, item)
+		}
+	}
+
+	key := func(item *corpSigningItem) string {
+		switch opt.Page.SortBy {
+		case dbmodels.SortByName:
+			return item.CorporationName
+		case dbmodels.SortByEmail:
+			return item.AdminEmail
+		}
+		return item.Date
+	}
+	sort.SliceStable(v, func(i, j int) bool {
+		return lessBy(opt.Page, key(v[i]), key(v[j]), v[i].AdminEmail, v[j].AdminEmail)
+	})
+
+	start, end := pageRange(len(v), opt.Page)
+
+	r := make([]dbmodels.CorporationSigningListItem, 0, end-start)
+	for _, item := range v[start:end] {
+		r = append(r, dbmodels.CorporationSigningListItem{
+			CLAOrgID:                 item.claOrgID,
+			CorporationSigningDetail: item.toDetail(),
+		})
+	}
+	return r, len(v), nil
+}
+
+func (c *client) ListCorporationSigningInfo(opt dbmodels.CorporationSigningListOption) (map[string][]dbmodels.CorporationSigningFullInfo, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r := map[string][]dbmodels.CorporationSigningFullInfo{}
+	for _, item := range c.listCorpSignings(opt) {
+		r[item.claOrgID] = append(r[item.claOrgID], dbmodels.CorporationSigningFullInfo{
+			CorporationSigningDetail: item.toDetail(),
+			Info:                     cloneSigningInfo(item.Info),
+		})
+	}
+	return r, nil
+}
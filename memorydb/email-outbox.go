@@ -0,0 +1,139 @@
+package memorydb
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func errEmailJobLeaseLost() error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrEmailJobLeaseLost,
+		Err:     fmt.Errorf("the lease of email job is lost"),
+	}
+}
+
+func cloneEmailJob(job *dbmodels.EmailJob) *dbmodels.EmailJob {
+	v := *job
+	v.Payload = cloneBytes(job.Payload)
+	return &v
+}
+
+func (c *client) AddEmailJob(job dbmodels.EmailJob) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id, _ := c.newID()
+
+	v := cloneEmailJob(&job)
+	v.ID = id
+	v.Status = dbmodels.EmailJobStatusPending
+	v.Attempts = 0
+	v.LastError = ""
+	v.LeaseOwner = ""
+	v.LeaseUntil = 0
+	c.emailJobs[id] = v
+
+	return id, nil
+}
+
+func (c *client) ClaimEmailJob(now, lease int64) (*dbmodels.EmailJob, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var job *dbmodels.EmailJob
+	for _, item := range c.emailJobs {
+		switch item.Status {
+		case dbmodels.EmailJobStatusPending:
+			if item.NextRunAt > now {
+				continue
+			}
+		case dbmodels.EmailJobStatusRunning:
+			if item.LeaseUntil > now {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if item.Attempts >= item.MaxAttempts {
+			continue
+		}
+
+		if job == nil || item.NextRunAt < job.NextRunAt ||
+			(item.NextRunAt == job.NextRunAt && item.ID < job.ID) {
+			job = item
+		}
+	}
+
+	if job == nil {
+		return nil, nil
+	}
+
+	owner, _ := c.newID()
+
+	job.Status = dbmodels.EmailJobStatusRunning
+	job.LeaseOwner = owner
+	job.LeaseUntil = now + lease
+	job.Attempts++
+
+	return cloneEmailJob(job), nil
+}
+
+func (c *client) FinishEmailJob(job *dbmodels.EmailJob) error {
+	return c.updateEmailJob(job, func(item *dbmodels.EmailJob) {
+		item.Status = dbmodels.EmailJobStatusDone
+		item.LastError = ""
+		// the payload may include sensitive data, such as password
+		item.Payload = nil
+	})
+}
+
+func (c *client) RetryEmailJob(job *dbmodels.EmailJob, nextRunAt int64, reason string) error {
+	return c.updateEmailJob(job, func(item *dbmodels.EmailJob) {
+		item.Status = dbmodels.EmailJobStatusPending
+		item.NextRunAt = nextRunAt
+		item.LastError = reason
+	})
+}
+
+func (c *client) KillEmailJob(job *dbmodels.EmailJob, reason string) error {
+	return c.updateEmailJob(job, func(item *dbmodels.EmailJob) {
+		item.Status = dbmodels.EmailJobStatusDead
+		item.LastError = reason
+		item.Payload = nil
+	})
+}
+
+func (c *client) CountEmailJobs(status string) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	n := 0
+	for _, item := range c.emailJobs {
+		if item.Status == status {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// updateEmailJob updates the job only if it is still leased to the one who claimed it.
+func (c *client) updateEmailJob(job *dbmodels.EmailJob, update func(*dbmodels.EmailJob)) error {
+	if err := checkID(job.ID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	item, ok := c.emailJobs[job.ID]
+	if !ok || item.Status != dbmodels.EmailJobStatusRunning ||
+		item.LeaseOwner != job.LeaseOwner || item.LeaseUntil != job.LeaseUntil {
+		return errEmailJobLeaseLost()
+	}
+
+	update(item)
+	return nil
+}
@@ -0,0 +1,398 @@
+package memorydb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type individualSigningItem struct {
+	dbmodels.IndividualSigningInfo
+
+	claOrgID string
+	corpID   string
+}
+
+func (item *individualSigningItem) toBasicInfo() dbmodels.IndividualSigningBasicInfo {
+	r := item.IndividualSigningBasicInfo
+	r.CLAVersion = normalizeCLAVersion(r.CLAVersion)
+	return r
+}
+
+func errHasNotSigned(format string, a ...interface{}) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrHasNotSigned,
+		Err:     fmt.Errorf(format, a...),
+	}
+}
+
+func (c *client) findIndividualSigning(claOrgID, email string) (int, *individualSigningItem) {
+	for i, item := range c.individuals {
+		if item.claOrgID == claOrgID && item.Email == email {
+			return i, item
+		}
+	}
+	return -1, nil
+}
+
+func (c *client) removeIndividualSigning(i int) {
+	c.individuals = append(c.individuals[:i], c.individuals[i+1:]...)
+}
+
+func (c *client) addIndividualSigningEvent(claOrgID, email, signer, action, reason string) {
+	c.events = append(c.events, dbmodels.IndividualSigningEvent{
+		CLAOrgID: claOrgID,
+		Email:    email,
+		Signer:   signer,
+		Action:   action,
+		Reason:   reason,
+		Time:     util.Now(),
+	})
+}
+
+// checkIndividualBinding checks whether the binding exists and is applied to individual.
+func (c *client) checkIndividualBinding(claOrgID string) error {
+	if c.findBinding(claOrgID, dbmodels.ApplyToIndividual) == nil {
+		return dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't find the cla"),
+		}
+	}
+	return nil
+}
+
+func (c *client) SignAsIndividual(claOrgID, platform, org, repo string, info dbmodels.IndividualSigningInfo) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	binding, signing, err := c.getIndividualSigning(platform, org, repo, info.Email, false)
+	if err != nil {
+		if !isHasNotSigned(err) {
+			return err
+		}
+	} else if !toSigningVersion(binding, signing.CLAVersion).IsOutdated() {
+		return dbmodels.DBError{
+			ErrCode: util.ErrHasSigned,
+			Err:     fmt.Errorf("he/she has signed"),
+		}
+	}
+
+	current, ok := c.bindings[claOrgID]
+	if !ok {
+		return errNoCLABinding()
+	}
+
+	// the outdated signing will be replaced by the new one
+	if signing == nil || binding.ID != claOrgID {
+		if _, v := c.findIndividualSigning(claOrgID, info.Email); v != nil {
+			return dbmodels.DBError{
+				ErrCode: util.ErrHasSigned,
+				Err:     fmt.Errorf("he/she has signed"),
+			}
+		}
+	}
+
+	if signing != nil {
+		// re-sign the new version of cla
+		i, _ := c.findIndividualSigning(binding.ID, info.Email)
+		c.removeIndividualSigning(i)
+	}
+
+	item := &individualSigningItem{
+		IndividualSigningInfo: info,
+		claOrgID:              claOrgID,
+		corpID:                util.EmailSuffix(info.Email),
+	}
+	item.Info = cloneSigningInfo(info.Info)
+	item.CLAVersion = normalizeCLAVersion(current.CLAVersion)
+	c.individuals = append(c.individuals, item)
+
+	action := dbmodels.SigningEventSign
+	if c.hasIndividualSigningEvent(claOrgID, info.Email) {
+		action = dbmodels.SigningEventResign
+	}
+	c.addIndividualSigningEvent(claOrgID, info.Email, info.Signer, action, "")
+
+	return nil
+}
+
+func (c *client) hasIndividualSigningEvent(claOrgID, email string) bool {
+	for i := range c.events {
+		if c.events[i].CLAOrgID == claOrgID && c.events[i].Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *client) DeleteIndividualSigning(claOrgID, email string) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkIndividualBinding(claOrgID); err != nil {
+		return err
+	}
+
+	i, item := c.findIndividualSigning(claOrgID, email)
+	if item == nil {
+		return nil
+	}
+
+	c.removeIndividualSigning(i)
+	c.addIndividualSigningEvent(claOrgID, email, item.Signer, dbmodels.SigningEventDelete, "")
+	return nil
+}
+
+func (c *client) RevokeIndividualSigning(claOrgID string, opt dbmodels.IndividualSigningRevokeOption) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkIndividualBinding(claOrgID); err != nil {
+		return err
+	}
+
+	i, item := c.findIndividualSigning(claOrgID, opt.Email)
+	if item == nil || !(item.Signer == opt.Signer || (opt.EmailVerified && item.Signer == "")) {
+		return errHasNotSigned("can't find the corresponding signing info")
+	}
+
+	c.removeIndividualSigning(i)
+	c.addIndividualSigningEvent(claOrgID, opt.Email, opt.Signer, dbmodels.SigningEventRevoke, opt.Reason)
+	return nil
+}
+
+func (c *client) UpdateIndividualSigning(claOrgID, email string, enabled bool) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.checkIndividualBinding(claOrgID); err != nil {
+		return err
+	}
+
+	_, item := c.findIndividualSigning(claOrgID, email)
+	if item == nil || item.Enabled == enabled {
+		return dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't find the corresponding signing info"),
+		}
+	}
+
+	item.Enabled = enabled
+	return nil
+}
+
+func (c *client) IsIndividualSigned(platform, orgID, repoID, email string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, signing, err := c.getIndividualSigning(platform, orgID, repoID, email, true)
+	if err != nil {
+		return false, err
+	}
+	return signing.Enabled, nil
+}
+
+func (c *client) GetIndividualSigningVersion(platform, orgID, repoID, email string) (dbmodels.SigningVersion, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	binding, signing, err := c.getIndividualSigning(platform, orgID, repoID, email, true)
+	if err != nil {
+		return dbmodels.SigningVersion{}, err
+	}
+	return toSigningVersion(binding, signing.CLAVersion), nil
+}
+
+func toSigningVersion(binding *bindingItem, version int) dbmodels.SigningVersion {
+	return dbmodels.SigningVersion{
+		CLAOrgID:       binding.ID,
+		Version:        normalizeCLAVersion(version),
+		CLAVersion:     normalizeCLAVersion(binding.CLAVersion),
+		ResignRequired: binding.ResignRequired,
+		ResignDeadline: binding.ResignDeadline,
+	}
+}
+
+func (c *client) getIndividualSigning(platform, orgID, repoID, email string, orgCared bool) (*bindingItem, *individualSigningItem, error) {
+	bindings, err := c.bindingsOfSigning(platform, orgID, repoID, dbmodels.ApplyToIndividual, orgCared)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, b := range bindings {
+		if _, item := c.findIndividualSigning(b.ID, email); item != nil {
+			return b, item, nil
+		}
+	}
+
+	return nil, nil, errHasNotSigned(
+		"the corp/individual has not signed for this org/repo: %s/%s/%s", platform, orgID, repoID,
+	)
+}
+
+// bindingsOfSigning returns the bindings in which the signing of org/repo should be found.
+// The bindings of repo are preferred if the repo has been bound cla and the org is cared.
+func (c *client) bindingsOfSigning(platform, org, repo, applyTo string, orgCared bool) ([]*bindingItem, error) {
+	v := c.listBindings(func(item *bindingItem) bool {
+		if item.Platform != platform || item.OrgID != org || item.ApplyTo != applyTo {
+			return false
+		}
+		if repo != "" && orgCared {
+			return item.RepoID == "" || item.RepoID == repo
+		}
+		return item.RepoID == repo
+	})
+
+	if len(v) == 0 {
+		return nil, dbmodels.DBError{
+			ErrCode: util.ErrNoCLABindingDoc,
+			Err:     fmt.Errorf("no record for this org/repo: %s/%s/%s", platform, org, repo),
+		}
+	}
+
+	if repo == "" || !orgCared {
+		return v, nil
+	}
+
+	r := make([]*bindingItem, 0, len(v))
+	for _, item := range v {
+		if item.RepoID == repo {
+			r = append(r, item)
+		}
+	}
+	if len(r) == 0 {
+		return v, nil
+	}
+	return r, nil
+}
+
+func isHasNotSigned(err error) bool {
+	e, ok := dbmodels.IsDBError(err)
+	return ok && e.ErrCode == util.ErrHasNotSigned
+}
+
+func (c *client) ListIndividualSigningOfSigner(signer string) (map[string][]dbmodels.IndividualSigningBasicInfo, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r := map[string][]dbmodels.IndividualSigningBasicInfo{}
+	for _, item := range c.individuals {
+		if item.Signer != signer || c.findBinding(item.claOrgID, dbmodels.ApplyToIndividual) == nil {
+			continue
+		}
+
+		r[item.claOrgID] = append(r[item.claOrgID], item.toBasicInfo())
+	}
+	return r, nil
+}
+
+func (c *client) ListIndividualSigningEvents(signer string) ([]dbmodels.IndividualSigningEvent, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r := make([]dbmodels.IndividualSigningEvent, 0)
+	for _, item := range c.events {
+		if item.Signer == signer {
+			r = append(r, item)
+		}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Time < r[j].Time
+	})
+	return r, nil
+}
+
+// listIndividualSignings returns the signings of bindings which match the option.
+func (c *client) listIndividualSignings(opt dbmodels.IndividualSigningListOption) []*individualSigningItem {
+	ids := c.listBindingIDs(func(item *bindingItem) bool {
+		return item.ApplyTo == dbmodels.ApplyToIndividual &&
+			item.Platform == opt.Platform && item.OrgID == opt.OrgID &&
+			(opt.RepoID == "" || item.RepoID == opt.RepoID) &&
+			(opt.CLALanguage == "" || item.CLALanguage == opt.CLALanguage)
+	})
+
+	var corpIDs map[string]bool
+	if opt.CorporationEmail != "" {
+		corpIDs = map[string]bool{util.EmailSuffix(opt.CorporationEmail): true}
+		for _, d := range opt.CorporationDomains {
+			corpIDs[d] = true
+		}
+	}
+
+	r := make([]*individualSigningItem, 0)
+	for _, item := range c.individuals {
+		if ids[item.claOrgID] && (corpIDs == nil || corpIDs[item.corpID]) {
+			r = append(r, item)
+		}
+	}
+	return r
+}
+
+func (c *client) ListIndividualSigning(opt dbmodels.IndividualSigningListOption) ([]dbmodels.IndividualSigningListItem, int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v := make([]*individualSigningItem, 0)
+	for _, item := range c.listIndividualSignings(opt) {
+		if matchSigningFilter(opt.Filter, item.Enabled, item.Date, item.Email) {
+			v = append(v, item)
+		}
+	}
+
+	key := func(item *individualSigningItem) string {
+		switch opt.Page.SortBy {
+		case dbmodels.SortByName:
+			return item.Name
+		case dbmodels.SortByEmail:
+			return item.Email
+		}
+		return item.Date
+	}
+	sort.SliceStable(v, func(i, j int) bool {
+		return lessBy(opt.Page, key(v[i]), key(v[j]), v[i].Email, v[j].Email)
+	})
+
+	start, end := pageRange(len(v), opt.Page)
+
+	r := make([]dbmodels.IndividualSigningListItem, 0, end-start)
+	for _, item := range v[start:end] {
+		r = append(r, dbmodels.IndividualSigningListItem{
+			CLAOrgID:                   item.claOrgID,
+			IndividualSigningBasicInfo: item.toBasicInfo(),
+		})
+	}
+	return r, len(v), nil
+}
+
+func (c *client) ListIndividualSigningInfo(opt dbmodels.IndividualSigningListOption) (map[string][]dbmodels.IndividualSigningInfo, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r := map[string][]dbmodels.IndividualSigningInfo{}
+	for _, item := range c.listIndividualSignings(opt) {
+		r[item.claOrgID] = append(r[item.claOrgID], dbmodels.IndividualSigningInfo{
+			IndividualSigningBasicInfo: item.toBasicInfo(),
+			Info:                       cloneSigningInfo(item.Info),
+		})
+	}
+	return r, nil
+}
@@ -0,0 +1,101 @@
+// Package memorydb implements dbmodels.IDB in memory. It is used to test the
+// models and controllers without a database, so the data is lost when exiting.
+package memorydb
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var _ dbmodels.IDB = (*client)(nil)
+
+var idPattern = regexp.MustCompile("^[0-9a-f]{24}$")
+
+// client keeps all the data in maps and slices. Every method holds the lock
+// until it returns, so it is atomic just like a transaction of database.
+// A method must check all the conditions before changing anything,
+// because there is no rollback.
+type client struct {
+	lock sync.Mutex
+	seq  int
+
+	clas     map[string]*claItem
+	bindings map[string]*bindingItem
+
+	individuals []*individualSigningItem
+	events      []dbmodels.IndividualSigningEvent
+	corps       []*corpSigningItem
+	managers    []*corpManagerItem
+	corpDomains []*corpDomainItem
+
+	verifCodes     []dbmodels.VerificationCode
+	orgEmails      map[string]dbmodels.OrgEmailCreateInfo
+	blankSigs      map[string][]byte
+	emailJobs      map[string]*dbmodels.EmailJob
+	refreshTokens  map[string]dbmodels.RefreshToken
+	revokedSession map[string]int64
+	accessSessions map[string]dbmodels.AccessSession
+	oauthStates    map[string]dbmodels.OAuthState
+	auditLogs      []dbmodels.AuditLog
+}
+
+func NewDB() *client {
+	return &client{
+		clas:           map[string]*claItem{},
+		bindings:       map[string]*bindingItem{},
+		orgEmails:      map[string]dbmodels.OrgEmailCreateInfo{},
+		blankSigs:      map[string][]byte{},
+		emailJobs:      map[string]*dbmodels.EmailJob{},
+		refreshTokens:  map[string]dbmodels.RefreshToken{},
+		revokedSession: map[string]int64{},
+		accessSessions: map[string]dbmodels.AccessSession{},
+		oauthStates:    map[string]dbmodels.OAuthState{},
+	}
+}
+
+// newID returns an id in the same format as the one of mongodb.
+// The sequence is also used to sort the items by created time.
+func (c *client) newID() (string, int) {
+	c.seq++
+	return fmt.Sprintf("%024x", c.seq), c.seq
+}
+
+func checkID(uid string) error {
+	if !idPattern.MatchString(uid) {
+		return dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't convert to object id"),
+		}
+	}
+	return nil
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+func cloneSigningInfo(info dbmodels.TypeSigningInfo) dbmodels.TypeSigningInfo {
+	if info == nil {
+		return nil
+	}
+
+	r := make(dbmodels.TypeSigningInfo, len(info))
+	for k, v := range info {
+		r[k] = v
+	}
+	return r
+}
+
+func normalizeCLAVersion(v int) int {
+	if v < 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+package memorydb
+
+import (
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/dbmodels/dbtest"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func TestContract(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T) dbmodels.IDB { return NewDB() })
+}
+
+func TestLegacyPasswordMigration(t *testing.T) {
+	db := NewDB()
+
+	claID, err := db.CreateCLA(dbmodels.CLA{
+		Name:      "corp",
+		Text:      "text of corp",
+		Language:  "english",
+		Submitter: "owner",
+		ApplyTo:   dbmodels.ApplyToCorporation,
+		Fields: []dbmodels.Field{
+			{ID: "1", Title: "name", Type: "string", Required: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := db.CreateBindingBetweenCLAAndOrg(dbmodels.CLAOrg{
+		Platform:    "gitee",
+		OrgID:       "org",
+		CLAID:       claID,
+		CLALanguage: "english",
+		ApplyTo:     dbmodels.ApplyToCorporation,
+		OrgEmail:    "cla@org.com",
+		Enabled:     true,
+		Submitter:   "owner",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.SignAsCorporation(b, "gitee", "org", "", dbmodels.CorporationSigningInfo{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			AdminEmail:      "a@corp.com",
+			AdminName:       "admin",
+			CorporationName: "corp",
+			Date:            util.Date(),
+		},
+		Info: dbmodels.TypeSigningInfo{"1": "corp"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.AddCorporationManager(b, []dbmodels.CorporationManagerCreateOption{
+		{Role: dbmodels.RoleAdmin, Email: "a@corp.com", Password: "pw"},
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the early versions stored the plaintext password
+	m := db.managers[0]
+	m.password = "pw"
+
+	login := func(pw string) int {
+		t.Helper()
+
+		r, err := db.CheckCorporationManagerExist(dbmodels.CorporationManagerCheckInfo{
+			User: "a@corp.com", Password: pw,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(r)
+	}
+
+	if login("wrong") != 0 {
+		t.Error("login with the wrong password")
+	}
+	if m.password != "pw" {
+		t.Error("the password is migrated by the failed login")
+	}
+
+	if login("pw") != 1 {
+		t.Fatal("failed to login with the plaintext password")
+	}
+	if !util.IsPasswordHashed(m.password) || !util.CheckPassword(m.password, "pw") {
+		t.Errorf("the password is not hashed after login: %s", m.password)
+	}
+
+	if login("pw") != 1 || login("wrong") != 0 {
+		t.Error("failed to check the migrated password")
+	}
+}
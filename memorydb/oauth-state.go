@@ -0,0 +1,50 @@
+package memorydb
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (c *client) AddOAuthState(opt dbmodels.OAuthState) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// delete the expired states which were never used
+	now := util.Now()
+	for k, v := range c.oauthStates {
+		if v.Expiry < now {
+			delete(c.oauthStates, k)
+		}
+	}
+
+	if _, ok := c.oauthStates[opt.State]; ok {
+		return fmt.Errorf("failed to add oauth state: duplicate state")
+	}
+
+	c.oauthStates[opt.State] = opt
+	return nil
+}
+
+func (c *client) UseOAuthState(state string) (dbmodels.OAuthState, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.oauthStates[state]
+	if !ok {
+		return dbmodels.OAuthState{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidOAuthState,
+			Err:     fmt.Errorf("unknown oauth state"),
+		}
+	}
+	delete(c.oauthStates, state)
+
+	if v.Expiry < util.Now() {
+		return dbmodels.OAuthState{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidOAuthState,
+			Err:     fmt.Errorf("oauth state is expired"),
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,72 @@
+package memorydb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func cloneOrgEmail(info dbmodels.OrgEmailCreateInfo) dbmodels.OrgEmailCreateInfo {
+	info.Token = cloneBytes(info.Token)
+	info.EncryptedKey = cloneBytes(info.EncryptedKey)
+	return info
+}
+
+func (c *client) CreateOrgEmail(opt dbmodels.OrgEmailCreateInfo) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// the existing one is kept like mongodb does
+	if _, ok := c.orgEmails[opt.Email]; !ok {
+		c.orgEmails[opt.Email] = cloneOrgEmail(opt)
+	}
+	return nil
+}
+
+func (c *client) GetOrgEmailInfo(email string) (dbmodels.OrgEmailCreateInfo, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.orgEmails[email]
+	if !ok {
+		return dbmodels.OrgEmailCreateInfo{}, dbmodels.DBError{
+			ErrCode: util.ErrNoOrgEmail,
+			Err:     fmt.Errorf("can't find org email configuration"),
+		}
+	}
+	return cloneOrgEmail(v), nil
+}
+
+func (c *client) ListOrgEmails() ([]dbmodels.OrgEmailCreateInfo, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r := make([]dbmodels.OrgEmailCreateInfo, 0, len(c.orgEmails))
+	for _, v := range c.orgEmails {
+		r = append(r, cloneOrgEmail(v))
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Email < r[j].Email
+	})
+	return r, nil
+}
+
+func (c *client) UpdateOrgEmailToken(email, oldKeyID string, info dbmodels.OrgEmailCreateInfo) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.orgEmails[email]
+	if !ok || v.KeyID != oldKeyID {
+		return fmt.Errorf("the token of org email: %s has been changed", email)
+	}
+
+	v.Token = cloneBytes(info.Token)
+	v.KeyID = info.KeyID
+	v.EncryptedKey = cloneBytes(info.EncryptedKey)
+	c.orgEmails[email] = v
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package memorydb
+
+import (
+	"strings"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// pageRange returns the range of items in the page when there are total items.
+func pageRange(total int, page dbmodels.PageOption) (int, int) {
+	start := page.Skip()
+	if start > total {
+		start = total
+	}
+
+	end := total
+	if page.PerPage > 0 && start+page.PerPage < total {
+		end = start + page.PerPage
+	}
+	return start, end
+}
+
+// lessBy compares two items by the sort field first and then by the tie field
+// in ascending order, which is the same as the sort of mongodb.
+func lessBy(page dbmodels.PageOption, a, b, tieA, tieB string) bool {
+	if a != b {
+		if page.Desc {
+			return a > b
+		}
+		return a < b
+	}
+	return tieA < tieB
+}
+
+// matchSigningFilter checks whether the signing matches the filter.
+func matchSigningFilter(f dbmodels.SigningFilter, enabled bool, date, email string) bool {
+	if f.Enabled != nil && *f.Enabled != enabled {
+		return false
+	}
+
+	if f.DateFrom != "" && date < f.DateFrom {
+		return false
+	}
+
+	if f.DateTo != "" && date > f.DateTo {
+		return false
+	}
+
+	if f.Email != "" && !strings.Contains(strings.ToLower(email), strings.ToLower(f.Email)) {
+		return false
+	}
+	return true
+}
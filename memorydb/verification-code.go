@@ -0,0 +1,51 @@
+package memorydb
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (c *client) CreateVerificationCode(opt dbmodels.VerificationCode) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// delete the old codes, including unused ones.
+	v := make([]dbmodels.VerificationCode, 0, len(c.verifCodes)+1)
+	for _, item := range c.verifCodes {
+		if item.Email != opt.Email || item.Purpose != opt.Purpose {
+			v = append(v, item)
+		}
+	}
+	c.verifCodes = append(v, opt)
+
+	return nil
+}
+
+func (c *client) CheckVerificationCode(opt dbmodels.VerificationCode) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, item := range c.verifCodes {
+		if item.Email != opt.Email || item.Purpose != opt.Purpose || item.Code != opt.Code {
+			continue
+		}
+
+		// the code can be used only once
+		c.verifCodes = append(c.verifCodes[:i], c.verifCodes[i+1:]...)
+
+		if item.Expiry < util.Now() {
+			return dbmodels.DBError{
+				ErrCode: util.ErrVerificationCodeExpired,
+				Err:     fmt.Errorf("verification code is expired"),
+			}
+		}
+		return nil
+	}
+
+	return dbmodels.DBError{
+		ErrCode: util.ErrWrongVerificationCode,
+		Err:     fmt.Errorf("wrong verification code"),
+	}
+}
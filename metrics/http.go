@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	beegoCtx "github.com/astaxie/beego/context"
+)
+
+const routeUnmatched = "unmatched"
+
+type routeKey struct{}
+
+// route is shared by Middleware and SaveRoute, because the middleware can't
+// see the route which is matched by beego.
+type route struct {
+	pattern string
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (this *statusWriter) WriteHeader(code int) {
+	if this.status == 0 {
+		this.status = code
+	}
+	this.ResponseWriter.WriteHeader(code)
+}
+
+func (this *statusWriter) Write(b []byte) (int, error) {
+	if this.status == 0 {
+		this.status = http.StatusOK
+	}
+	return this.ResponseWriter.Write(b)
+}
+
+// Middleware records the latency and status of every request, including
+// the ones which are rejected by the filters.
+func Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		rt := &route{}
+		sw := &statusWriter{ResponseWriter: w}
+		h.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), routeKey{}, rt)))
+
+		pattern := rt.pattern
+		if pattern == "" {
+			pattern = routeUnmatched
+		}
+
+		status := sw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		httpRequestDuration.WithLabelValues(
+			pattern, r.Method, strconv.Itoa(status),
+		).Observe(time.Since(start).Seconds())
+	})
+}
+
+// SaveRoute is the filter of beego which saves the pattern of route matched,
+// so that the requests of same api are counted together.
+func SaveRoute(ctx *beegoCtx.Context) {
+	rt, ok := ctx.Request.Context().Value(routeKey{}).(*route)
+	if !ok {
+		return
+	}
+
+	rt.pattern, _ = ctx.Input.GetData("RouterPattern").(string)
+}
@@ -0,0 +1,127 @@
+// Package metrics exposes the metrics of the server in the format of Prometheus.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cla"
+
+const (
+	SigningIndividual  = "individual"
+	SigningEmployee    = "employee"
+	SigningCorporation = "corporation"
+
+	VerificationCodeIssue  = "issue"
+	VerificationCodeVerify = "verify"
+
+	ResultSuccess = "success"
+	ResultWrong   = "wrong"
+	ResultExpired = "expired"
+	ResultError   = "error"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "The latency of http requests by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"route", "method", "status"},
+	)
+
+	signings = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "signings_total",
+			Help:      "The number of signings by type and org.",
+		},
+		[]string{"type", "platform", "org"},
+	)
+
+	verificationCodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "verification_codes_total",
+			Help:      "The number of verification codes issued and verified by outcome.",
+		},
+		[]string{"purpose", "action", "result"},
+	)
+
+	emailSendings = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "email_sendings_total",
+			Help:      "The number of attempts to send email by platform.",
+		},
+		[]string{"platform"},
+	)
+
+	emailSendingFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "email_sending_failures_total",
+			Help:      "The number of failed attempts to send email by platform.",
+		},
+		[]string{"platform"},
+	)
+
+	pdfGenerationDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "pdf_generation_duration_seconds",
+			Help:      "The time spent on generating the pdf of corporation signing.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+	)
+)
+
+func init() {
+	registry.MustRegister(
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		prometheus.NewGoCollector(),
+		httpRequestDuration,
+		signings,
+		verificationCodes,
+		emailSendings,
+		emailSendingFailures,
+		pdfGenerationDuration,
+		outboxCollector{},
+	)
+}
+
+// Handler serves the metrics to Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func IncSigning(kind, platform, org string) {
+	signings.WithLabelValues(kind, platform, org).Inc()
+}
+
+func IncVerificationCode(purpose, action, result string) {
+	verificationCodes.WithLabelValues(purpose, action, result).Inc()
+}
+
+// IncEmailSending records an attempt to send email, which failed if err is not nil.
+func IncEmailSending(platform string, err error) {
+	emailSendings.WithLabelValues(platform).Inc()
+
+	if err != nil {
+		emailSendingFailures.WithLabelValues(platform).Inc()
+	}
+}
+
+// ObservePDFGeneration records the time since start, so that it can be
+// called as defer ObservePDFGeneration(time.Now()).
+func ObservePDFGeneration(start time.Time) {
+	pdfGenerationDuration.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/astaxie/beego"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+var outboxJobsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "email_outbox_jobs"),
+	"The number of email jobs in the outbox by status.",
+	[]string{"status"}, nil,
+)
+
+// outboxCollector counts the email jobs in the database when it is scraped,
+// so that the backlog is right even if there are several instances of server.
+type outboxCollector struct{}
+
+func (this outboxCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- outboxJobsDesc
+}
+
+func (this outboxCollector) Collect(ch chan<- prometheus.Metric) {
+	db := dbmodels.GetDB()
+	if db == nil {
+		return
+	}
+
+	status := []string{
+		dbmodels.EmailJobStatusPending,
+		dbmodels.EmailJobStatusRunning,
+		dbmodels.EmailJobStatusDead,
+	}
+	for _, s := range status {
+		n, err := db.CountEmailJobs(s)
+		if err != nil {
+			beego.Error(fmt.Sprintf("failed to count email jobs: %s", err.Error()))
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(outboxJobsDesc, prometheus.GaugeValue, float64(n), s)
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"github.com/opensourceways/app-cla-server/conf"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func AddRefreshToken(token dbmodels.RefreshToken) error {
+	return dbmodels.GetDB().AddRefreshToken(token)
+}
+
+func UseRefreshToken(tokenID string) (dbmodels.RefreshToken, error) {
+	return dbmodels.GetDB().UseRefreshToken(tokenID)
+}
+
+// RevokeAccessSession revokes all the access tokens and refresh tokens of the session.
+func RevokeAccessSession(sessionID string, expiry int64) error {
+	db := dbmodels.GetDB()
+
+	if err := db.RevokeAccessSession(sessionID, expiry); err != nil {
+		return err
+	}
+
+	if err := db.DeleteAccessSession(sessionID); err != nil {
+		return err
+	}
+
+	return db.DeleteRefreshTokensOfSession(sessionID)
+}
+
+func IsAccessSessionRevoked(sessionID string) (bool, error) {
+	return dbmodels.GetDB().IsAccessSessionRevoked(sessionID)
+}
+
+// SavePlatformToken keeps the token of code platform encrypted in the session store.
+func SavePlatformToken(sessionID, token string, expiry int64) error {
+	v, err := util.Encrypt([]byte(token), sessionEncryptionKey(), nil)
+	if err != nil {
+		return err
+	}
+
+	return dbmodels.GetDB().SaveAccessSession(dbmodels.AccessSession{
+		ID:            sessionID,
+		PlatformToken: v,
+		Expiry:        expiry,
+	})
+}
+
+func GetPlatformToken(sessionID string) (string, error) {
+	s, err := dbmodels.GetDB().GetAccessSession(sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	v, err := util.Decrypt(s.PlatformToken, sessionEncryptionKey(), nil)
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
+
+func sessionEncryptionKey() []byte {
+	return util.DeriveKey(conf.AppConfig.SessionEncryptionKey)
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	AuditActionAddCorpAdmin          = "add-corporation-administrator"
+	AuditActionAddEmployeeManager    = "add-employee-manager"
+	AuditActionDeleteEmployeeManager = "delete-employee-manager"
+	AuditActionEnableEmployee        = "enable-employee"
+	AuditActionDisableEmployee       = "disable-employee"
+	AuditActionDeleteEmployee        = "delete-employee"
+	AuditActionDeleteBinding         = "delete-binding"
+	AuditActionUploadOrgSignature    = "upload-org-signature"
+	AuditActionReviewCorpSigning     = "review-corporation-signing"
+	AuditActionRequestCorpDomain     = "request-corporation-domain"
+	AuditActionReviewCorpDomain      = "review-corporation-domain"
+)
+
+type AuditLog dbmodels.AuditLog
+
+func (this *AuditLog) Create() error {
+	this.Time = util.Now()
+
+	return dbmodels.GetDB().AddAuditLog(dbmodels.AuditLog(*this))
+}
+
+type AuditLogListOption dbmodels.AuditLogListOption
+
+func (this AuditLogListOption) List() ([]dbmodels.AuditLog, int, error) {
+	return dbmodels.GetDB().ListAuditLog(dbmodels.AuditLogListOption(this))
+}
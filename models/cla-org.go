@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/opensourceways/app-cla-server/dbmodels"
@@ -21,6 +22,11 @@ type CLAOrg struct {
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
 	OrgSignatureUploaded bool      `json:"org_signature_uploaded"`
+
+	CLAVersion     int                   `json:"cla_version"`
+	ResignRequired bool                  `json:"resign_required"`
+	ResignDeadline int64                 `json:"resign_deadline"`
+	CLAVersions    []dbmodels.CLAVersion `json:"cla_versions,omitempty"`
 }
 
 func (this *CLAOrg) Create() error {
@@ -57,6 +63,40 @@ func (this CLAOrgListOption) ListForSigningPage() ([]dbmodels.CLAOrg, error) {
 	return dbmodels.GetDB().ListBindingForSigningPage(dbmodels.CLAOrgListOption(this))
 }
 
-func (this CLAOrgListOption) List() ([]dbmodels.CLAOrg, error) {
+func (this CLAOrgListOption) List() ([]dbmodels.CLAOrg, int, error) {
 	return dbmodels.GetDB().ListBindingBetweenCLAAndOrg(dbmodels.CLAOrgListOption(this))
 }
+
+type CLAVersionPublishOption struct {
+	CLAID          string `json:"cla_id"`
+	ResignRequired bool   `json:"resign_required"`
+	// GracePeriod is the days in which the signers of outdated version can still pass the check.
+	GracePeriod int `json:"grace_period"`
+}
+
+func (this CLAVersionPublishOption) Validate() error {
+	if this.CLAID == "" {
+		return fmt.Errorf("missing cla_id")
+	}
+
+	if this.GracePeriod < 0 {
+		return fmt.Errorf("grace_period can't be negative")
+	}
+	return nil
+}
+
+func (this CLAVersionPublishOption) Publish(claOrgID string) (int, error) {
+	opt := dbmodels.CLAVersionPublishOption{
+		CLAID:          this.CLAID,
+		ResignRequired: this.ResignRequired,
+	}
+	if this.ResignRequired {
+		opt.ResignDeadline = time.Now().AddDate(0, 0, this.GracePeriod).Unix()
+	}
+
+	return dbmodels.GetDB().PublishCLAVersion(claOrgID, opt)
+}
+
+func ListOutdatedSignings(claOrgID string, page dbmodels.PageOption) (dbmodels.OutdatedSignings, error) {
+	return dbmodels.GetDB().ListOutdatedSignings(claOrgID, page)
+}
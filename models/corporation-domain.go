@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var domainPattern = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)+$`)
+
+type CorporationDomainCreateOption struct {
+	Domain string `json:"domain"`
+}
+
+func (this *CorporationDomainCreateOption) Validate() (string, error) {
+	if !domainPattern.MatchString(this.Domain) {
+		return util.ErrInvalidParameter, fmt.Errorf("invalid domain: %s", this.Domain)
+	}
+	return "", nil
+}
+
+// Create requests to add the domain to the corporation which the administrator belongs to.
+func (this *CorporationDomainCreateOption) Create(claOrgID, adminEmail string) error {
+	return dbmodels.GetDB().AddCorporationDomain(claOrgID, dbmodels.CorporationDomain{
+		CorpID:    util.EmailSuffix(adminEmail),
+		Domain:    this.Domain,
+		Status:    dbmodels.CorpDomainStatusPending,
+		Requester: adminEmail,
+		Date:      util.Date(),
+	})
+}
+
+type CorporationDomainReviewOption struct {
+	Approved bool `json:"approved"`
+}
+
+func (this *CorporationDomainReviewOption) Review(claOrgID, domain string) error {
+	status := dbmodels.CorpDomainStatusRejected
+	if this.Approved {
+		status = dbmodels.CorpDomainStatusApproved
+	}
+
+	return dbmodels.GetDB().UpdateCorporationDomainStatus(claOrgID, domain, status)
+}
+
+func ListCorporationDomain(claOrgID string, opt dbmodels.CorporationDomainListOption) ([]dbmodels.CorporationDomain, error) {
+	return dbmodels.GetDB().ListCorporationDomain(claOrgID, opt)
+}
+
+// ApprovedCorporationDomains returns the approved domains of corporation
+// which the email of corp manager belongs to.
+func ApprovedCorporationDomains(claOrgID, corpEmail string) ([]string, error) {
+	v, err := ListCorporationDomain(claOrgID, dbmodels.CorporationDomainListOption{
+		CorpID: util.EmailSuffix(corpEmail),
+		Status: dbmodels.CorpDomainStatusApproved,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]string, 0, len(v))
+	for i := range v {
+		r = append(r, v[i].Domain)
+	}
+	return r, nil
+}
+
+// IsSameCorp checks whether the email belongs to the corporation of corp manager
+// by the approved domains of the corporation.
+func IsSameCorp(claOrgID, corpEmail, email string) (bool, error) {
+	corpID, err := dbmodels.GetDB().GetCorporationID(claOrgID, email)
+	if err != nil {
+		return false, err
+	}
+	return corpID == util.EmailSuffix(corpEmail), nil
+}
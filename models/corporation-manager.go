@@ -43,6 +43,6 @@ func (this CorporationManagerResetPassword) Reset(claOrgID, email string) error
 	)
 }
 
-func ListCorporationManagers(claOrgID, email, role string) ([]dbmodels.CorporationManagerListResult, error) {
-	return dbmodels.GetDB().ListCorporationManager(claOrgID, email, role)
+func ListCorporationManagers(claOrgID, email, role string, page dbmodels.PageOption) ([]dbmodels.CorporationManagerListResult, int, error) {
+	return dbmodels.GetDB().ListCorporationManager(claOrgID, email, role, page)
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	CorpSigningReviewStart   = "start"
+	CorpSigningReviewApprove = "approve"
+	CorpSigningReviewReject  = "reject"
+)
+
+var corpSigningReviewStatus = map[string]string{
+	CorpSigningReviewStart:   dbmodels.CorpSigningStatusUnderReview,
+	CorpSigningReviewApprove: dbmodels.CorpSigningStatusApproved,
+	CorpSigningReviewReject:  dbmodels.CorpSigningStatusRejected,
+}
+
+type CorporationSigningReviewOption struct {
+	// Action is one of start, approve and reject.
+	Action  string `json:"action"`
+	Comment string `json:"comment"`
+}
+
+func (this *CorporationSigningReviewOption) Validate() (string, error) {
+	if _, ok := corpSigningReviewStatus[this.Action]; !ok {
+		return util.ErrInvalidParameter, fmt.Errorf("unknown review action: %s", this.Action)
+	}
+
+	if this.Action == CorpSigningReviewReject && this.Comment == "" {
+		return util.ErrInvalidParameter, fmt.Errorf("the reason of rejection is required")
+	}
+
+	return "", nil
+}
+
+// Status returns the status which the signing will be changed to.
+func (this *CorporationSigningReviewOption) Status() string {
+	return corpSigningReviewStatus[this.Action]
+}
+
+func (this *CorporationSigningReviewOption) Review(claOrgID, email string) error {
+	return dbmodels.GetDB().UpdateCorporationSigningStatus(
+		claOrgID, email,
+		dbmodels.CorporationSigningStatusUpdate{
+			Status:  this.Status(),
+			Comment: this.Comment,
+		},
+	)
+}
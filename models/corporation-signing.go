@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/metrics"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
@@ -22,7 +23,25 @@ func (this *CorporationSigningCreateOption) Validate() error {
 		Purpose: ActionCorporationSigning,
 	}
 
-	return dbmodels.GetDB().CheckVerificationCode(vc)
+	err := dbmodels.GetDB().CheckVerificationCode(vc)
+	metrics.IncVerificationCode(ActionCorporationSigning, metrics.VerificationCodeVerify, verificationCodeResult(err))
+	return err
+}
+
+func verificationCodeResult(err error) string {
+	if err == nil {
+		return metrics.ResultSuccess
+	}
+
+	if e, ok := dbmodels.IsDBError(err); ok {
+		switch e.ErrCode {
+		case util.ErrWrongVerificationCode:
+			return metrics.ResultWrong
+		case util.ErrVerificationCodeExpired:
+			return metrics.ResultExpired
+		}
+	}
+	return metrics.ResultError
 }
 
 func (this *CorporationSigningCreateOption) Create(claOrgID, platform, orgID, repoId string) error {
@@ -50,9 +69,30 @@ func GetCorporationSigningDetail(platform, org, repo, email string) (string, dbm
 	return dbmodels.GetDB().GetCorporationSigningDetail(platform, org, repo, email)
 }
 
+// GetCorporationSigningVersion returns the cla version which the corporation of email signed.
+func GetCorporationSigningVersion(platform, org, repo, email string) (dbmodels.SigningVersion, error) {
+	claOrgID, detail, err := GetCorporationSigningDetail(platform, org, repo, email)
+	if err != nil {
+		return dbmodels.SigningVersion{}, err
+	}
+
+	claOrg := &CLAOrg{ID: claOrgID}
+	if err := claOrg.Get(); err != nil {
+		return dbmodels.SigningVersion{}, err
+	}
+
+	return dbmodels.SigningVersion{
+		CLAOrgID:       claOrgID,
+		Version:        detail.CLAVersion,
+		CLAVersion:     claOrg.CLAVersion,
+		ResignRequired: claOrg.ResignRequired,
+		ResignDeadline: claOrg.ResignDeadline,
+	}, nil
+}
+
 type CorporationSigningListOption dbmodels.CorporationSigningListOption
 
-func (this CorporationSigningListOption) List() (map[string][]dbmodels.CorporationSigningDetail, error) {
+func (this CorporationSigningListOption) List() ([]dbmodels.CorporationSigningListItem, int, error) {
 	return dbmodels.GetDB().ListCorporationSigning(dbmodels.CorporationSigningListOption(this))
 }
 
@@ -67,5 +107,6 @@ func CreateCorporationSigningVerifCode(email string, expiry int64) (string, erro
 	}
 
 	err := dbmodels.GetDB().CreateVerificationCode(vc)
+	metrics.IncVerificationCode(ActionCorporationSigning, metrics.VerificationCodeIssue, verificationCodeResult(err))
 	return code, err
 }
@@ -4,15 +4,28 @@ import "github.com/opensourceways/app-cla-server/dbmodels"
 
 type EmployeeSigningListOption struct {
 	CLALanguage string `json:"cla_language"`
+
+	Filter dbmodels.SigningFilter `json:"-"`
+	Page   dbmodels.PageOption    `json:"-"`
 }
 
-func (this EmployeeSigningListOption) List(corpEmail, platform, org, repo string) (map[string][]dbmodels.IndividualSigningBasicInfo, error) {
+// List lists the employees of corporation, including the ones of its approved domains
+// on the binding of corpClaOrgID.
+func (this EmployeeSigningListOption) List(corpClaOrgID, corpEmail, platform, org, repo string) ([]dbmodels.IndividualSigningListItem, int, error) {
+	domains, err := ApprovedCorporationDomains(corpClaOrgID, corpEmail)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	opt := dbmodels.IndividualSigningListOption{
-		Platform:         platform,
-		OrgID:            org,
-		RepoID:           repo,
-		CLALanguage:      this.CLALanguage,
-		CorporationEmail: corpEmail,
+		Platform:           platform,
+		OrgID:              org,
+		RepoID:             repo,
+		CLALanguage:        this.CLALanguage,
+		CorporationEmail:   corpEmail,
+		CorporationDomains: domains,
+		Filter:             this.Filter,
+		Page:               this.Page,
 	}
 	return dbmodels.GetDB().ListIndividualSigning(opt)
 }
@@ -1,10 +1,15 @@
 package models
 
 import (
+	"fmt"
+
 	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/metrics"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
+const ActionIndividualSigningRevoke = "individual-signing-revoke"
+
 type IndividualSigning dbmodels.IndividualSigningInfo
 
 func (this *IndividualSigning) Create(claOrgID, platform, orgID, repoId string, enabled bool) error {
@@ -19,3 +24,72 @@ func (this *IndividualSigning) Create(claOrgID, platform, orgID, repoId string,
 func IsIndividualSigned(platform, orgID, repoId, email string) (bool, error) {
 	return dbmodels.GetDB().IsIndividualSigned(platform, orgID, repoId, email)
 }
+
+type IndividualSigningRevokeOption struct {
+	dbmodels.IndividualSigningRevokeOption
+
+	// VerifiCode is only required to revoke the signing which has no signer.
+	VerifiCode string `json:"verifi_code"`
+}
+
+func (this *IndividualSigningRevokeOption) Validate() error {
+	if this.Email == "" {
+		return fmt.Errorf("missing email")
+	}
+
+	if this.Reason == "" {
+		return fmt.Errorf("missing reason")
+	}
+	return nil
+}
+
+func (this *IndividualSigningRevokeOption) Revoke(claOrgID string) error {
+	if this.VerifiCode != "" {
+		vc := dbmodels.VerificationCode{
+			Email:   this.Email,
+			Code:    this.VerifiCode,
+			Purpose: ActionIndividualSigningRevoke,
+		}
+
+		err := dbmodels.GetDB().CheckVerificationCode(vc)
+		metrics.IncVerificationCode(
+			ActionIndividualSigningRevoke, metrics.VerificationCodeVerify, verificationCodeResult(err),
+		)
+		if err != nil {
+			return err
+		}
+
+		this.EmailVerified = true
+	}
+
+	return dbmodels.GetDB().RevokeIndividualSigning(claOrgID, this.IndividualSigningRevokeOption)
+}
+
+func CreateIndividualSigningRevokeVerifCode(email string, expiry int64) (string, error) {
+	code := util.RandStr(6, "number")
+
+	vc := dbmodels.VerificationCode{
+		Email:   email,
+		Code:    code,
+		Purpose: ActionIndividualSigningRevoke,
+		Expiry:  util.Now() + expiry,
+	}
+
+	err := dbmodels.GetDB().CreateVerificationCode(vc)
+	metrics.IncVerificationCode(
+		ActionIndividualSigningRevoke, metrics.VerificationCodeIssue, verificationCodeResult(err),
+	)
+	return code, err
+}
+
+func ListIndividualSigningOfSigner(signer string) (map[string][]dbmodels.IndividualSigningBasicInfo, error) {
+	return dbmodels.GetDB().ListIndividualSigningOfSigner(signer)
+}
+
+func ListIndividualSigningEvents(signer string) ([]dbmodels.IndividualSigningEvent, error) {
+	return dbmodels.GetDB().ListIndividualSigningEvents(signer)
+}
+
+func GetIndividualSigningVersion(platform, orgID, repoId, email string) (dbmodels.SigningVersion, error) {
+	return dbmodels.GetDB().GetIndividualSigningVersion(platform, orgID, repoId, email)
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func AddOAuthState(opt dbmodels.OAuthState) error {
+	return dbmodels.GetDB().AddOAuthState(opt)
+}
+
+func UseOAuthState(state string) (dbmodels.OAuthState, error) {
+	return dbmodels.GetDB().UseOAuthState(state)
+}
@@ -3,9 +3,12 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
-	"github.com/opensourceways/app-cla-server/dbmodels"
 	"golang.org/x/oauth2"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/encryption"
 )
 
 type OrgEmail struct {
@@ -21,10 +24,17 @@ func (this *OrgEmail) Create() error {
 		return fmt.Errorf("Failed to marshal oauth2 token: %s", err.Error())
 	}
 
+	e, err := encryption.Encrypt(b, this.Email)
+	if err != nil {
+		return fmt.Errorf("Failed to encrypt oauth2 token: %s", err.Error())
+	}
+
 	opt := dbmodels.OrgEmailCreateInfo{
-		Email:    this.Email,
-		Platform: this.Platform,
-		Token:    b,
+		Email:        this.Email,
+		Platform:     this.Platform,
+		Token:        e.Ciphertext,
+		KeyID:        e.KeyID,
+		EncryptedKey: e.EncryptedKey,
 	}
 	return dbmodels.GetDB().CreateOrgEmail(opt)
 }
@@ -37,9 +47,14 @@ func (this *OrgEmail) Get() error {
 
 	this.Platform = info.Platform
 
+	b, _, err := decryptOrgEmailToken(&info)
+	if err != nil {
+		return err
+	}
+
 	var token oauth2.Token
 
-	err = json.Unmarshal(info.Token, &token)
+	err = json.Unmarshal(b, &token)
 	if err != nil {
 		return fmt.Errorf("Failed to unmarshal oauth2 token: %s", err.Error())
 	}
@@ -47,3 +62,84 @@ func (this *OrgEmail) Get() error {
 	this.Token = &token
 	return nil
 }
+
+// decryptOrgEmailToken returns the token and whether it should be re-encrypted,
+// because it was stored by the early versions.
+func decryptOrgEmailToken(info *dbmodels.OrgEmailCreateInfo) ([]byte, bool, error) {
+	if info.KeyID == "" {
+		// the token was stored as plaintext by the early versions
+		return info.Token, true, nil
+	}
+
+	e := encryption.Envelope{
+		KeyID:        info.KeyID,
+		EncryptedKey: info.EncryptedKey,
+		Ciphertext:   info.Token,
+	}
+
+	b, err := encryption.Decrypt(e, info.Email)
+	if err == nil {
+		return b, false, nil
+	}
+
+	// the token was encrypted without the additional data by the early versions
+	if b, err1 := encryption.DecryptLegacy(e); err1 == nil {
+		return b, true, nil
+	}
+
+	return nil, false, fmt.Errorf("Failed to decrypt oauth2 token: %s", err.Error())
+}
+
+// RotateOrgEmailKeys re-encrypts the tokens of all org emails by the primary master key,
+// including the ones stored by the early versions. It returns the number of org emails
+// which are re-encrypted.
+func RotateOrgEmailKeys() (int, error) {
+	items, err := dbmodels.GetDB().ListOrgEmails()
+	if err != nil {
+		return 0, err
+	}
+
+	primary := encryption.PrimaryKeyID()
+	n := 0
+	var failed []string
+	for i := range items {
+		item := &items[i]
+
+		rotated, err := rotateOrgEmailKey(item, primary)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", item.Email, err.Error()))
+			continue
+		}
+		if rotated {
+			n++
+		}
+	}
+
+	if len(failed) > 0 {
+		return n, fmt.Errorf("failed to rotate keys of org emails:\n%s", strings.Join(failed, "\n"))
+	}
+	return n, nil
+}
+
+func rotateOrgEmailKey(item *dbmodels.OrgEmailCreateInfo, primary string) (bool, error) {
+	b, legacy, err := decryptOrgEmailToken(item)
+	if err != nil {
+		return false, err
+	}
+
+	if item.KeyID == primary && !legacy {
+		return false, nil
+	}
+
+	e, err := encryption.Encrypt(b, item.Email)
+	if err != nil {
+		return false, err
+	}
+
+	err = dbmodels.GetDB().UpdateOrgEmailToken(item.Email, item.KeyID, dbmodels.OrgEmailCreateInfo{
+		Token:        e.Ciphertext,
+		KeyID:        e.KeyID,
+		EncryptedKey: e.EncryptedKey,
+	})
+	return err == nil, err
+}
@@ -0,0 +1,295 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/export"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	SigningKindIndividual  = "individual"
+	SigningKindEmployee    = "employee"
+	SigningKindCorporation = "corporation"
+)
+
+var SigningKinds = []string{SigningKindIndividual, SigningKindEmployee, SigningKindCorporation}
+
+type SigningExportOption struct {
+	Platform string
+	OrgID    string
+	RepoID   string
+}
+
+// Export returns the tables of individual, employee and corporation signings in order.
+func (this SigningExportOption) Export() ([]*export.Table, error) {
+	db := dbmodels.GetDB()
+
+	bindings, _, err := db.ListBindingBetweenCLAAndOrg(dbmodels.CLAOrgListOption{
+		Platform: this.Platform,
+		OrgID:    this.OrgID,
+		RepoID:   this.RepoID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	languages := make(map[string]string, len(bindings))
+	for _, item := range bindings {
+		languages[item.ID] = item.CLALanguage
+	}
+
+	individualTitles, err := fieldTitles(bindings, dbmodels.ApplyToIndividual)
+	if err != nil {
+		return nil, err
+	}
+
+	corpTitles, err := fieldTitles(bindings, dbmodels.ApplyToCorporation)
+	if err != nil {
+		return nil, err
+	}
+
+	corps, err := db.ListCorporationSigningInfo(dbmodels.CorporationSigningListOption{
+		Platform: this.Platform,
+		OrgID:    this.OrgID,
+		RepoID:   this.RepoID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	individuals, err := db.ListIndividualSigningInfo(dbmodels.IndividualSigningListOption{
+		Platform: this.Platform,
+		OrgID:    this.OrgID,
+		RepoID:   this.RepoID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the employee is the individual whose email suffix is same as the corporation's,
+	// or is one of the approved domains of the corporation.
+	corpNames := map[string]map[string]string{}
+	for claOrgID, items := range corps {
+		names := map[string]string{}
+		for i := range items {
+			names[util.EmailSuffix(items[i].AdminEmail)] = items[i].CorporationName
+		}
+
+		domains, err := db.ListCorporationDomain(claOrgID, dbmodels.CorporationDomainListOption{
+			Status: dbmodels.CorpDomainStatusApproved,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range domains {
+			if name, ok := names[domains[i].CorpID]; ok {
+				names[domains[i].Domain] = name
+			}
+		}
+
+		corpNames[claOrgID] = names
+	}
+
+	employees := employeeCorpNames(bindings, corpNames)
+
+	return []*export.Table{
+		individualSigningTable(individuals, languages, individualTitles, employees, false),
+		individualSigningTable(individuals, languages, individualTitles, employees, true),
+		corpSigningTable(corps, languages, corpTitles),
+	}, nil
+}
+
+// employeeCorpNames returns the names of corporations by the email domain for each individual
+// binding. The employee signs the individual binding of the same org/repo as the corporation's.
+func employeeCorpNames(bindings []dbmodels.CLAOrg, corpNames map[string]map[string]string) map[string]map[string]string {
+	r := map[string]map[string]string{}
+	for i := range bindings {
+		b := &bindings[i]
+		if b.ApplyTo != dbmodels.ApplyToIndividual {
+			continue
+		}
+
+		names := map[string]string{}
+		for j := range bindings {
+			cb := &bindings[j]
+			if cb.ApplyTo == dbmodels.ApplyToCorporation &&
+				cb.Platform == b.Platform && cb.OrgID == b.OrgID && cb.RepoID == b.RepoID {
+				for k, v := range corpNames[cb.ID] {
+					names[k] = v
+				}
+			}
+		}
+		r[b.ID] = names
+	}
+	return r
+}
+
+// fieldTitles returns the titles of fields of all the cla versions bound by the bindings
+// which apply to applyTo. The titles are joined if the same field has different titles.
+func fieldTitles(bindings []dbmodels.CLAOrg, applyTo string) (map[string]string, error) {
+	claIDs := map[string]bool{}
+	for i := range bindings {
+		b := &bindings[i]
+		if b.ApplyTo != applyTo {
+			continue
+		}
+
+		claIDs[b.CLAID] = true
+		for _, v := range b.CLAVersions {
+			claIDs[v.CLAID] = true
+		}
+	}
+
+	titles := map[string][]string{}
+	for _, id := range sortedKeys(claIDs) {
+		cla, err := dbmodels.GetDB().GetCLA(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range cla.Fields {
+			if !hasString(titles[f.ID], f.Title) {
+				titles[f.ID] = append(titles[f.ID], f.Title)
+			}
+		}
+	}
+
+	r := make(map[string]string, len(titles))
+	for k, v := range titles {
+		r[k] = strings.Join(v, " / ")
+	}
+	return r, nil
+}
+
+func individualSigningTable(
+	signings map[string][]dbmodels.IndividualSigningInfo,
+	languages, titles map[string]string, corpNames map[string]map[string]string, employee bool,
+) *export.Table {
+	infoKeys := map[string]bool{}
+	for _, items := range signings {
+		for i := range items {
+			for k := range items[i].Info {
+				infoKeys[k] = true
+			}
+		}
+	}
+	keys := sortedKeys(infoKeys)
+
+	ids := make(map[string]bool, len(signings))
+	for k := range signings {
+		ids[k] = true
+	}
+
+	t := &export.Table{
+		Name:   SigningKindIndividual,
+		Header: []string{"binding id", "cla language", "cla version", "name", "email", "date", "enabled"},
+	}
+	if employee {
+		t.Name = SigningKindEmployee
+		t.Header = append(t.Header, "corporation")
+	}
+	t.Header = append(t.Header, infoHeader(keys, titles)...)
+
+	// sort the binding ids to make the order of rows stable between exports.
+	for _, id := range sortedKeys(ids) {
+		for _, item := range signings[id] {
+			corpName, isEmployee := corpNames[id][util.EmailSuffix(item.Email)]
+			if isEmployee != employee {
+				continue
+			}
+
+			row := []string{
+				id, languages[id], strconv.Itoa(item.CLAVersion),
+				item.Name, item.Email, item.Date, strconv.FormatBool(item.Enabled),
+			}
+			if employee {
+				row = append(row, corpName)
+			}
+			t.AddRow(append(row, infoValues(item.Info, keys)...))
+		}
+	}
+
+	return t
+}
+
+func corpSigningTable(signings map[string][]dbmodels.CorporationSigningFullInfo, languages, titles map[string]string) *export.Table {
+	infoKeys := map[string]bool{}
+	for _, items := range signings {
+		for i := range items {
+			for k := range items[i].Info {
+				infoKeys[k] = true
+			}
+		}
+	}
+	keys := sortedKeys(infoKeys)
+
+	ids := make(map[string]bool, len(signings))
+	for k := range signings {
+		ids[k] = true
+	}
+
+	t := &export.Table{
+		Name: SigningKindCorporation,
+		Header: append([]string{
+			"binding id", "cla language", "cla version", "corporation", "admin name",
+			"admin email", "date", "pdf uploaded", "admin added", "status",
+		}, infoHeader(keys, titles)...),
+	}
+
+	// sort the binding ids to make the order of rows stable between exports.
+	for _, id := range sortedKeys(ids) {
+		for _, item := range signings[id] {
+			row := []string{
+				id, languages[id], strconv.Itoa(item.CLAVersion), item.CorporationName,
+				item.AdminName, item.AdminEmail, item.Date,
+				strconv.FormatBool(item.PDFUploaded), strconv.FormatBool(item.AdminAdded),
+				item.Status,
+			}
+			t.AddRow(append(row, infoValues(item.Info, keys)...))
+		}
+	}
+
+	return t
+}
+
+func hasString(items []string, s string) bool {
+	for _, v := range items {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// infoHeader returns the titles of fields. The id is used if the title is unknown.
+func infoHeader(keys []string, titles map[string]string) []string {
+	r := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if v, ok := titles[k]; ok {
+			r = append(r, v)
+		} else {
+			r = append(r, k)
+		}
+	}
+	return r
+}
+
+func infoValues(info dbmodels.TypeSigningInfo, keys []string) []string {
+	r := make([]string, 0, len(keys))
+	for _, k := range keys {
+		r = append(r, info[k])
+	}
+	return r
+}
@@ -0,0 +1,211 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	refreshTokenCollection   = "refresh_tokens"
+	revokedSessionCollection = "revoked_sessions"
+	accessSessionCollection  = "access_sessions"
+)
+
+type refreshTokenDoc struct {
+	ID            string `bson:"_id"`
+	SessionID     string `bson:"session_id"`
+	User          string `bson:"user"`
+	Permission    string `bson:"permission"`
+	SessionExpiry int64  `bson:"session_expiry"`
+	Expiry        int64  `bson:"expiry"`
+}
+
+type accessSessionDoc struct {
+	ID            string `bson:"_id"`
+	PlatformToken []byte `bson:"platform_token"`
+	Expiry        int64  `bson:"expiry"`
+}
+
+type revokedSessionDoc struct {
+	ID     string `bson:"_id"`
+	Expiry int64  `bson:"expiry"`
+}
+
+func (c *client) AddRefreshToken(token dbmodels.RefreshToken) error {
+	doc := refreshTokenDoc{
+		ID:            token.ID,
+		SessionID:     token.SessionID,
+		User:          token.User,
+		Permission:    token.Permission,
+		SessionExpiry: token.SessionExpiry,
+		Expiry:        token.Expiry,
+	}
+
+	f := func(ctx context.Context) error {
+		col := c.collection(refreshTokenCollection)
+
+		// clean up the expired tokens by the way
+		col.DeleteMany(ctx, bson.M{"expiry": bson.M{"$lt": util.Now()}})
+
+		if _, err := col.InsertOne(ctx, doc); err != nil {
+			return fmt.Errorf("failed to add refresh token: %s", err.Error())
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (c *client) UseRefreshToken(tokenID string) (dbmodels.RefreshToken, error) {
+	var v refreshTokenDoc
+
+	f := func(ctx context.Context) error {
+		col := c.collection(refreshTokenCollection)
+
+		return col.FindOneAndDelete(ctx, bson.M{"_id": tokenID}).Decode(&v)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoDocuments(err) {
+			return dbmodels.RefreshToken{}, dbmodels.DBError{
+				ErrCode: util.ErrInvalidRefreshToken,
+				Err:     fmt.Errorf("unknown refresh token"),
+			}
+		}
+		return dbmodels.RefreshToken{}, err
+	}
+
+	if v.Expiry < util.Now() {
+		return dbmodels.RefreshToken{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidRefreshToken,
+			Err:     fmt.Errorf("refresh token is expired"),
+		}
+	}
+
+	return dbmodels.RefreshToken{
+		ID:            v.ID,
+		SessionID:     v.SessionID,
+		User:          v.User,
+		Permission:    v.Permission,
+		SessionExpiry: v.SessionExpiry,
+		Expiry:        v.Expiry,
+	}, nil
+}
+
+func (c *client) DeleteRefreshTokensOfSession(sessionID string) error {
+	f := func(ctx context.Context) error {
+		col := c.collection(refreshTokenCollection)
+
+		_, err := col.DeleteMany(ctx, bson.M{"session_id": sessionID})
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) RevokeAccessSession(sessionID string, expiry int64) error {
+	f := func(ctx context.Context) error {
+		col := c.collection(revokedSessionCollection)
+
+		// the session which has expired needn't be kept in the revocation list
+		col.DeleteMany(ctx, bson.M{"expiry": bson.M{"$lt": util.Now()}})
+
+		upsert := true
+		_, err := col.UpdateOne(
+			ctx, bson.M{"_id": sessionID},
+			bson.M{"$set": revokedSessionDoc{ID: sessionID, Expiry: expiry}},
+			&options.UpdateOptions{Upsert: &upsert},
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) IsAccessSessionRevoked(sessionID string) (bool, error) {
+	n := int64(0)
+
+	f := func(ctx context.Context) error {
+		col := c.collection(revokedSessionCollection)
+
+		v, err := col.CountDocuments(ctx, bson.M{"_id": sessionID})
+		n = v
+		return err
+	}
+
+	err := withContext(f)
+	return n > 0, err
+}
+
+func (c *client) SaveAccessSession(session dbmodels.AccessSession) error {
+	doc := accessSessionDoc{
+		ID:            session.ID,
+		PlatformToken: session.PlatformToken,
+		Expiry:        session.Expiry,
+	}
+
+	f := func(ctx context.Context) error {
+		col := c.collection(accessSessionCollection)
+
+		col.DeleteMany(ctx, bson.M{"expiry": bson.M{"$lt": util.Now()}})
+
+		upsert := true
+		_, err := col.UpdateOne(
+			ctx, bson.M{"_id": session.ID}, bson.M{"$set": doc},
+			&options.UpdateOptions{Upsert: &upsert},
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) GetAccessSession(sessionID string) (dbmodels.AccessSession, error) {
+	var v accessSessionDoc
+
+	f := func(ctx context.Context) error {
+		col := c.collection(accessSessionCollection)
+
+		return col.FindOne(ctx, bson.M{"_id": sessionID}).Decode(&v)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoDocuments(err) {
+			return dbmodels.AccessSession{}, dbmodels.DBError{
+				ErrCode: util.ErrSessionExpired,
+				Err:     fmt.Errorf("unknown session"),
+			}
+		}
+		return dbmodels.AccessSession{}, err
+	}
+
+	if v.Expiry < util.Now() {
+		return dbmodels.AccessSession{}, dbmodels.DBError{
+			ErrCode: util.ErrSessionExpired,
+			Err:     fmt.Errorf("session is expired"),
+		}
+	}
+
+	return dbmodels.AccessSession{
+		ID:            v.ID,
+		PlatformToken: v.PlatformToken,
+		Expiry:        v.Expiry,
+	}, nil
+}
+
+func (c *client) DeleteAccessSession(sessionID string) error {
+	f := func(ctx context.Context) error {
+		col := c.collection(accessSessionCollection)
+
+		_, err := col.DeleteOne(ctx, bson.M{"_id": sessionID})
+		return err
+	}
+
+	return withContext(f)
+}
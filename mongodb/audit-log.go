@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// auditLogCollection is only inserted, so that the logs can't be changed.
+const auditLogCollection = "audit_logs"
+
+const auditLogIndex = "platform_org_time"
+
+type auditLogDoc struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	Actor    string             `bson:"actor"`
+	Action   string             `bson:"action"`
+	Platform string             `bson:"platform"`
+	OrgID    string             `bson:"org_id"`
+	RepoID   string             `bson:"repo_id"`
+	CLAOrgID string             `bson:"cla_org_id"`
+	CorpID   string             `bson:"corp_id"`
+	Target   string             `bson:"target"`
+	Before   map[string]string  `bson:"before,omitempty"`
+	After    map[string]string  `bson:"after,omitempty"`
+	SourceIP string             `bson:"source_ip"`
+	Time     int64              `bson:"time"`
+}
+
+func (c *client) AddAuditLog(log dbmodels.AuditLog) error {
+	doc := auditLogDoc{
+		Actor:    log.Actor,
+		Action:   log.Action,
+		Platform: log.Platform,
+		OrgID:    log.OrgID,
+		RepoID:   log.RepoID,
+		CLAOrgID: log.CLAOrgID,
+		CorpID:   log.CorpID,
+		Target:   log.Target,
+		Before:   log.Before,
+		After:    log.After,
+		SourceIP: log.SourceIP,
+		Time:     log.Time,
+	}
+
+	f := func(ctx context.Context) error {
+		col := c.collection(auditLogCollection)
+
+		if _, err := col.InsertOne(ctx, doc); err != nil {
+			return fmt.Errorf("failed to add audit log: %s", err.Error())
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (c *client) ListAuditLog(opt dbmodels.AuditLogListOption) ([]dbmodels.AuditLog, int, error) {
+	filter := bson.M{
+		"platform": opt.Platform,
+		"org_id":   opt.OrgID,
+	}
+	if opt.CorpID != "" {
+		filter["corp_id"] = opt.CorpID
+	}
+	if opt.Action != "" {
+		filter["action"] = opt.Action
+	}
+
+	var v []auditLogDoc
+	total := int64(0)
+
+	f := func(ctx context.Context) error {
+		col := c.collection(auditLogCollection)
+
+		n, err := col.CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("error count audit logs: %v", err)
+		}
+		total = n
+
+		opts := options.FindOptions{
+			Sort: bson.D{
+				{Key: "time", Value: sortDirection(opt.Page)},
+				{Key: "_id", Value: 1},
+			},
+		}
+		if opt.Page.PerPage > 0 {
+			opts.SetSkip(int64(opt.Page.Skip()))
+			opts.SetLimit(int64(opt.Page.PerPage))
+		}
+
+		cursor, err := col.Find(ctx, filter, &opts)
+		if err != nil {
+			return fmt.Errorf("error find audit logs: %v", err)
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, err
+	}
+
+	r := make([]dbmodels.AuditLog, 0, len(v))
+	for _, item := range v {
+		r = append(r, dbmodels.AuditLog{
+			ID:       objectIDToUID(item.ID),
+			Actor:    item.Actor,
+			Action:   item.Action,
+			Platform: item.Platform,
+			OrgID:    item.OrgID,
+			RepoID:   item.RepoID,
+			CLAOrgID: item.CLAOrgID,
+			CorpID:   item.CorpID,
+			Target:   item.Target,
+			Before:   item.Before,
+			After:    item.After,
+			SourceIP: item.SourceIP,
+			Time:     item.Time,
+		})
+	}
+	return r, int(total), nil
+}
+
+func (c *client) createAuditLogIndex() error {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "platform", Value: 1}, {Key: "org_id", Value: 1}, {Key: "time", Value: 1},
+		},
+		Options: options.Index().SetName(auditLogIndex),
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.collection(auditLogCollection).Indexes().CreateOne(ctx, index)
+		return err
+	}
+
+	return withContext(f)
+}
+
+// dropAuditLogIndex only drops the index and keeps the logs.
+func (c *client) dropAuditLogIndex() error {
+	f := func(ctx context.Context) error {
+		_, err := c.collection(auditLogCollection).Indexes().DropOne(ctx, auditLogIndex)
+		return err
+	}
+
+	return withContext(f)
+}
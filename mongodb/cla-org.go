@@ -26,6 +26,8 @@ const (
 	fieldOrgSignature    = "org_signature"
 	fieldOrgSignatureTag = "org_signature_uploaded"
 	fieldRepo            = "repo_id"
+	fieldCLAVersion      = "cla_version"
+	fieldCLAVersions     = "cla_versions"
 )
 
 func filterForClaOrgDoc(filter bson.M) {
@@ -47,19 +49,16 @@ type CLAOrg struct {
 	Enabled     bool      `bson:"enabled"`
 	Submitter   string    `bson:"submitter"`
 
-	// Individuals is the cla signing information of ordinary contributors
-	// key is the email of contributor
-	Individuals []individualSigningDoc `bson:"individuals,omitempty"`
-
-	// Corporations is the cla signing information of corporation
-	// key is the email suffix of corporation
-	Corporations []corporationSigningDoc `bson:"corporations,omitempty"`
-
-	// CorporationManagers is the managers of corporation who can manage the employee
-	CorporationManagers []corporationManagerDoc `bson:"corp_managers,omitempty"`
+	// The signings and corporation managers are kept in their own collections.
+	// See individualSigningCollection, corpSigningCollection and corpManagerCollection.
 
 	OrgSignatureUploaded bool   `bson:"org_signature_uploaded"`
 	OrgSignature         []byte `bson:"org_signature"`
+
+	CLAVersion     int             `bson:"cla_version"`
+	ResignRequired bool            `bson:"resign_required"`
+	ResignDeadline int64           `bson:"resign_deadline"`
+	CLAVersions    []claVersionDoc `bson:"cla_versions,omitempty"`
 }
 
 func orgIdentifier(platform, org string) string {
@@ -72,6 +71,11 @@ func (c *client) CreateBindingBetweenCLAAndOrg(claOrg dbmodels.CLAOrg) (string,
 		return "", fmt.Errorf("build body failed, err:%v", err)
 	}
 	body[orgIdentifierName] = orgIdentifier(claOrg.Platform, claOrg.OrgID)
+	body["created_at"] = time.Now()
+	body[fieldCLAVersion] = 1
+	body[fieldCLAVersions] = bson.A{
+		claVersionDoc{Version: 1, CLAID: claOrg.CLAID, PublishedAt: util.Now()},
+	}
 
 	var r *mongo.UpdateResult
 
@@ -165,7 +169,7 @@ func (c *client) GetBindingBetweenCLAAndOrg(uid string) (dbmodels.CLAOrg, error)
 	return toModelCLAOrg(v), nil
 }
 
-func (c *client) ListBindingBetweenCLAAndOrg(opt dbmodels.CLAOrgListOption) ([]dbmodels.CLAOrg, error) {
+func (c *client) ListBindingBetweenCLAAndOrg(opt dbmodels.CLAOrgListOption) ([]dbmodels.CLAOrg, int, error) {
 	info := struct {
 		Platform string `json:"platform" required:"true"`
 		OrgID    string `json:"org_id" required:"true"`
@@ -180,18 +184,33 @@ func (c *client) ListBindingBetweenCLAAndOrg(opt dbmodels.CLAOrgListOption) ([]d
 
 	body, err := structToMap(info)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	filter := bson.M(body)
 	filterForClaOrgDoc(filter)
 
 	var v []CLAOrg
+	total := int64(0)
 
 	f := func(ctx context.Context) error {
 		col := c.db.Collection(claOrgCollection)
 
+		n, err := col.CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("error count bindings: %v", err)
+		}
+		total = n
+
 		opts := options.FindOptions{
 			Projection: projectOfClaOrg(),
+			Sort: bson.D{
+				{Key: "created_at", Value: sortDirection(opt.Page)},
+				{Key: "_id", Value: 1},
+			},
+		}
+		if opt.Page.PerPage > 0 {
+			opts.SetSkip(int64(opt.Page.Skip()))
+			opts.SetLimit(int64(opt.Page.PerPage))
 		}
 		cursor, err := col.Find(ctx, filter, &opts)
 		if err != nil {
@@ -207,7 +226,7 @@ func (c *client) ListBindingBetweenCLAAndOrg(opt dbmodels.CLAOrgListOption) ([]d
 
 	err = withContext(f)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	n := len(v)
@@ -216,7 +235,7 @@ func (c *client) ListBindingBetweenCLAAndOrg(opt dbmodels.CLAOrgListOption) ([]d
 		r = append(r, toModelCLAOrg(item))
 	}
 
-	return r, nil
+	return r, int(total), nil
 }
 
 func (c *client) ListBindingForSigningPage(opt dbmodels.CLAOrgListOption) ([]dbmodels.CLAOrg, error) {
@@ -301,9 +320,45 @@ func toModelCLAOrg(item CLAOrg) dbmodels.CLAOrg {
 		Enabled:              item.Enabled,
 		Submitter:            item.Submitter,
 		OrgSignatureUploaded: item.OrgSignatureUploaded,
+		CLAVersion:           normalizeCLAVersion(item.CLAVersion),
+		ResignRequired:       item.ResignRequired,
+		ResignDeadline:       item.ResignDeadline,
+		CLAVersions:          toDBModelCLAVersions(item.CLAVersions),
+	}
+}
+
+func (c *client) listBindingIDs(filter bson.M, ctx context.Context) (bson.A, error) {
+	col := c.collection(claOrgCollection)
+
+	cursor, err := col.Find(ctx, filter, &options.FindOptions{Projection: bson.M{"_id": 1}})
+	if err != nil {
+		return nil, fmt.Errorf("error find bindings: %v", err)
+	}
+
+	var v []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &v); err != nil {
+		return nil, err
+	}
+
+	r := make(bson.A, 0, len(v))
+	for i := range v {
+		r = append(r, v[i].ID)
+	}
+	return r, nil
+}
+
+func (c *client) isBindingExist(filter bson.M, ctx context.Context) (bool, error) {
+	n, err := c.collection(claOrgCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
 	}
+	return n > 0, nil
 }
 
+// projectOfClaOrg excludes the big fields. The arrays of signings and managers
+// only exist in the documents which have not been split.
 func projectOfClaOrg() bson.M {
 	return bson.M{
 		fieldIndividuals:   0,
@@ -0,0 +1,236 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type claVersionDoc struct {
+	Version     int    `bson:"version"`
+	CLAID       string `bson:"cla_id"`
+	PublishedAt int64  `bson:"published_at"`
+}
+
+// normalizeCLAVersion treats the binding and signing created before
+// the cla version is introduced as the first version.
+func normalizeCLAVersion(v int) int {
+	if v < 1 {
+		return 1
+	}
+	return v
+}
+
+func toDBModelCLAVersions(items []claVersionDoc) []dbmodels.CLAVersion {
+	if len(items) == 0 {
+		return nil
+	}
+
+	r := make([]dbmodels.CLAVersion, 0, len(items))
+	for _, item := range items {
+		r = append(r, dbmodels.CLAVersion{
+			Version:     item.Version,
+			CLAID:       item.CLAID,
+			PublishedAt: item.PublishedAt,
+		})
+	}
+	return r
+}
+
+func (c *client) getCLAVersion(claOrgID primitive.ObjectID, ctx context.Context) (int, error) {
+	col := c.collection(claOrgCollection)
+
+	opt := options.FindOneOptions{
+		Projection: bson.M{fieldCLAVersion: 1},
+	}
+
+	var v CLAOrg
+	if err := col.FindOne(ctx, bson.M{"_id": claOrgID}, &opt).Decode(&v); err != nil {
+		if isErrNoDocuments(err) {
+			return 0, dbmodels.DBError{
+				ErrCode: util.ErrNoCLABindingDoc,
+				Err:     fmt.Errorf("can't find cla binding"),
+			}
+		}
+		return 0, err
+	}
+
+	return normalizeCLAVersion(v.CLAVersion), nil
+}
+
+func (c *client) PublishCLAVersion(claOrgID string, opt dbmodels.CLAVersionPublishOption) (int, error) {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+
+	f := func(ctx context.Context) error {
+		col := c.collection(claOrgCollection)
+
+		filter := bson.M{"_id": oid}
+		filterForClaOrgDoc(filter)
+
+		var v CLAOrg
+		err := col.FindOne(ctx, filter, &options.FindOneOptions{
+			Projection: bson.M{"cla_id": 1, fieldCLAVersion: 1, fieldCLAVersions: 1},
+		}).Decode(&v)
+		if err != nil {
+			if isErrNoDocuments(err) {
+				return dbmodels.DBError{
+					ErrCode: util.ErrNoCLABindingDoc,
+					Err:     fmt.Errorf("can't find cla binding"),
+				}
+			}
+			return err
+		}
+
+		if v.CLAID == opt.CLAID {
+			return dbmodels.DBError{
+				ErrCode: util.ErrInvalidParameter,
+				Err:     fmt.Errorf("the cla is the current version"),
+			}
+		}
+
+		current := normalizeCLAVersion(v.CLAVersion)
+		version = current + 1
+
+		history := bson.A{}
+		if len(v.CLAVersions) == 0 {
+			// the binding was created before the cla version is introduced
+			history = append(history, claVersionDoc{Version: current, CLAID: v.CLAID})
+		}
+		history = append(history, claVersionDoc{Version: version, CLAID: opt.CLAID, PublishedAt: util.Now()})
+
+		// make sure the cla is not changed concurrently
+		filter["cla_id"] = v.CLAID
+
+		update := bson.M{
+			"$set": bson.M{
+				"cla_id":          opt.CLAID,
+				fieldCLAVersion:   version,
+				"resign_required": opt.ResignRequired,
+				"resign_deadline": opt.ResignDeadline,
+				"updated_at":      time.Now(),
+			},
+			"$push": bson.M{fieldCLAVersions: bson.M{"$each": history}},
+		}
+
+		r, err := col.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+
+		if r.ModifiedCount == 0 {
+			return dbmodels.DBError{
+				ErrCode: util.ErrInvalidParameter,
+				Err:     fmt.Errorf("the cla has been changed concurrently"),
+			}
+		}
+		return nil
+	}
+
+	err = withContext(f)
+	return version, err
+}
+
+func (c *client) ListOutdatedSignings(claOrgID string, page dbmodels.PageOption) (dbmodels.OutdatedSignings, error) {
+	r := dbmodels.OutdatedSignings{
+		Individuals:  []dbmodels.IndividualSigningBasicInfo{},
+		Corporations: []dbmodels.CorporationSigningBasicInfo{},
+	}
+
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return r, err
+	}
+
+	individualSort, corpSort := "date", "date"
+	if page.SortBy == dbmodels.SortByEmail {
+		individualSort, corpSort = "email", "admin_email"
+	}
+
+	var v CLAOrg
+	var individuals []individualSigningDoc
+	var corps []corporationSigningDoc
+
+	f := func(ctx context.Context) error {
+		col := c.collection(claOrgCollection)
+
+		opt := options.FindOneOptions{
+			Projection: bson.M{fieldCLAVersion: 1},
+		}
+
+		err := col.FindOne(ctx, bson.M{"_id": oid}, &opt).Decode(&v)
+		if err != nil {
+			if isErrNoDocuments(err) {
+				return dbmodels.DBError{
+					ErrCode: util.ErrNoCLABindingDoc,
+					Err:     fmt.Errorf("can't find cla binding"),
+				}
+			}
+			return err
+		}
+
+		version := normalizeCLAVersion(v.CLAVersion)
+		if version <= 1 {
+			return nil
+		}
+
+		// the signing without cla version is treated as the first version.
+		filter := bson.M{
+			"cla_org_id": oid,
+			"$or": bson.A{
+				bson.M{fieldCLAVersion: bson.M{"$lt": version}},
+				bson.M{fieldCLAVersion: bson.M{"$exists": false}},
+			},
+		}
+		project := bson.M{"info": 0}
+
+		r.IndividualTotal, err = c.findPage(
+			individualSigningCollection, filter, page,
+			bson.D{
+				{Key: individualSort, Value: sortDirection(page)},
+				{Key: "email", Value: 1},
+			},
+			project, &individuals, ctx,
+		)
+		if err != nil {
+			return err
+		}
+
+		r.CorporationTotal, err = c.findPage(
+			corpSigningCollection, filter, page,
+			bson.D{
+				{Key: corpSort, Value: sortDirection(page)},
+				{Key: "admin_email", Value: 1},
+			},
+			project, &corps, ctx,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return r, err
+	}
+
+	r.CLAVersion = normalizeCLAVersion(v.CLAVersion)
+
+	for i := range individuals {
+		r.Individuals = append(r.Individuals, toDBModelIndividualSigningBasicInfo(&individuals[i]))
+	}
+
+	for i := range corps {
+		r.Corporations = append(r.Corporations, toDBModelCorporationSigningDetail(&corps[i]).CorporationSigningBasicInfo)
+	}
+
+	return r, nil
+}
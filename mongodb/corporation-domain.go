@@ -0,0 +1,291 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	corpDomainCollection = "corporation_domains"
+	corpDomainIndex      = "cla_org_domain"
+)
+
+type corpDomainDoc struct {
+	CLAOrgID  primitive.ObjectID `bson:"cla_org_id"`
+	CorpID    string             `bson:"corp_id"`
+	Domain    string             `bson:"domain"`
+	Status    string             `bson:"status"`
+	Requester string             `bson:"requester"`
+	Date      string             `bson:"date"`
+}
+
+func errCorpDomainExists(domain string) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrCorpDomainExists,
+		Err:     fmt.Errorf("the domain:%s has been owned by a corporation", domain),
+	}
+}
+
+func (c *client) isCorpSigned(claOrgID primitive.ObjectID, corpID string, ctx context.Context) (bool, error) {
+	n, err := c.collection(corpSigningCollection).CountDocuments(
+		ctx, bson.M{"cla_org_id": claOrgID, fieldCorporationID: corpID},
+	)
+	return n > 0, err
+}
+
+// corpIDOf returns the id of corporation which the email belongs to.
+func (c *client) corpIDOf(claOrgID primitive.ObjectID, email string, ctx context.Context) (string, error) {
+	domain := util.EmailSuffix(email)
+
+	var v corpDomainDoc
+	err := c.collection(corpDomainCollection).FindOne(ctx, bson.M{
+		"cla_org_id": claOrgID,
+		"domain":     domain,
+		"status":     dbmodels.CorpDomainStatusApproved,
+	}).Decode(&v)
+	if err != nil {
+		if isErrNoDocuments(err) {
+			return domain, nil
+		}
+		return "", err
+	}
+	return v.CorpID, nil
+}
+
+// corpIDFilter returns the filter which matches the signings of corporations
+// which the email belongs to on each binding.
+func (c *client) corpIDFilter(email string, ctx context.Context) (bson.M, error) {
+	domain := util.EmailSuffix(email)
+
+	cursor, err := c.collection(corpDomainCollection).Find(ctx, bson.M{
+		"domain": domain,
+		"status": dbmodels.CorpDomainStatusApproved,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var v []corpDomainDoc
+	if err := cursor.All(ctx, &v); err != nil {
+		return nil, err
+	}
+
+	if len(v) == 0 {
+		return bson.M{fieldCorporationID: domain}, nil
+	}
+
+	// the signing whose id is the domain can't exist on the bindings
+	// where the domain has been approved for other corporation.
+	conds := bson.A{bson.M{fieldCorporationID: domain}}
+	for i := range v {
+		conds = append(conds, bson.M{"cla_org_id": v[i].CLAOrgID, fieldCorporationID: v[i].CorpID})
+	}
+	return bson.M{"$or": conds}, nil
+}
+
+func (c *client) AddCorporationDomain(claOrgID string, info dbmodels.CorporationDomain) error {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return err
+	}
+
+	doc := corpDomainDoc{
+		CLAOrgID:  oid,
+		CorpID:    info.CorpID,
+		Domain:    info.Domain,
+		Status:    info.Status,
+		Requester: info.Requester,
+		Date:      info.Date,
+	}
+
+	f := func(ctx mongo.SessionContext) error {
+		if err := c.checkCorpBinding(oid, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		b, err := c.isCorpSigned(oid, info.CorpID, ctx)
+		if err != nil {
+			return err
+		}
+		if !b {
+			return dbmodels.DBError{
+				ErrCode: util.ErrHasNotSigned,
+				Err:     fmt.Errorf("the corp:%s has not signed", info.CorpID),
+			}
+		}
+
+		if info.Domain == info.CorpID {
+			return errCorpDomainExists(info.Domain)
+		}
+
+		b, err = c.isCorpSigned(oid, info.Domain, ctx)
+		if err != nil {
+			return err
+		}
+		if b {
+			return errCorpDomainExists(info.Domain)
+		}
+
+		// the rejected domain can be requested again.
+		filter := bson.M{
+			"cla_org_id": oid,
+			"domain":     info.Domain,
+			"status":     dbmodels.CorpDomainStatusRejected,
+		}
+		upsert := true
+		_, err = c.collection(corpDomainCollection).ReplaceOne(
+			ctx, filter, doc, &options.ReplaceOptions{Upsert: &upsert},
+		)
+		if err != nil && isDuplicateKeyError(err) {
+			return errCorpDomainExists(info.Domain)
+		}
+		return err
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) UpdateCorporationDomainStatus(claOrgID, domain, status string) error {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx mongo.SessionContext) error {
+		if err := c.checkCorpBinding(oid, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		if status == dbmodels.CorpDomainStatusApproved {
+			b, err := c.isCorpSigned(oid, domain, ctx)
+			if err != nil {
+				return err
+			}
+			if b {
+				return errCorpDomainExists(domain)
+			}
+		}
+
+		r, err := c.collection(corpDomainCollection).UpdateOne(
+			ctx,
+			bson.M{
+				"cla_org_id": oid,
+				"domain":     domain,
+				"status":     dbmodels.CorpDomainStatusPending,
+			},
+			bson.M{"$set": bson.M{"status": status}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if r.MatchedCount == 0 {
+			return dbmodels.DBError{
+				ErrCode: util.ErrNoPendingCorpDomain,
+				Err:     fmt.Errorf("no pending domain:%s", domain),
+			}
+		}
+		return nil
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) ListCorporationDomain(claOrgID string, opt dbmodels.CorporationDomainListOption) ([]dbmodels.CorporationDomain, error) {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"cla_org_id": oid}
+	if opt.CorpID != "" {
+		filter[fieldCorporationID] = opt.CorpID
+	}
+	if opt.Status != "" {
+		filter["status"] = opt.Status
+	}
+
+	var v []corpDomainDoc
+
+	f := func(ctx context.Context) error {
+		if err := c.checkCorpBinding(oid, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		cursor, err := c.collection(corpDomainCollection).Find(
+			ctx, filter, options.Find().SetSort(bson.M{"domain": 1}),
+		)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	r := make([]dbmodels.CorporationDomain, 0, len(v))
+	for i := range v {
+		item := &v[i]
+		r = append(r, dbmodels.CorporationDomain{
+			CorpID:    item.CorpID,
+			Domain:    item.Domain,
+			Status:    item.Status,
+			Requester: item.Requester,
+			Date:      item.Date,
+		})
+	}
+	return r, nil
+}
+
+func (c *client) GetCorporationID(claOrgID, email string) (string, error) {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return "", err
+	}
+
+	r := ""
+	f := func(ctx context.Context) error {
+		v, err := c.corpIDOf(oid, email, ctx)
+		r = v
+		return err
+	}
+
+	err = withContext(f)
+	return r, err
+}
+
+func (c *client) createCorpDomainIndex() error {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "cla_org_id", Value: 1}, {Key: "domain", Value: 1},
+		},
+		Options: options.Index().SetName(corpDomainIndex).SetUnique(true),
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.collection(corpDomainCollection).Indexes().CreateOne(ctx, index)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) dropCorpDomainIndex() error {
+	f := func(ctx context.Context) error {
+		_, err := c.collection(corpDomainCollection).Indexes().DropOne(ctx, corpDomainIndex)
+		return err
+	}
+
+	return withContext(f)
+}
@@ -7,27 +7,36 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/opensourceways/app-cla-server/dbmodels"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
-type corporationManagerDoc struct {
-	Role             string `bson:"role" json:"role" required:"true"`
-	Email            string `bson:"email"  json:"email" required:"true"`
-	Password         string `bson:"password" json:"password" required:"true"`
-	InitialPWChanged bool   `bson:"changed" json:"changed"`
-}
+const corpManagerCollection = "corporation_managers"
 
-func corpManagerField(field string) string {
-	return fmt.Sprintf("%s.%s", fieldCorpoManagers, field)
+type corporationManagerDoc struct {
+	CLAOrgID         primitive.ObjectID `bson:"cla_org_id"`
+	CorpID           string             `bson:"corp_id"`
+	Role             string             `bson:"role"`
+	Email            string             `bson:"email"`
+	Password         string             `bson:"password"`
+	InitialPWChanged bool               `bson:"changed"`
 }
 
 func filterForCorpManager(filter bson.M) {
 	filter["apply_to"] = dbmodels.ApplyToCorporation
 	filter["enabled"] = true
-	filter[fieldCorpoManagers] = bson.M{"$type": "array"}
+}
+
+func filterOfCorpManager(claOrgID primitive.ObjectID, email, role string) bson.M {
+	filter := bson.M{
+		"cla_org_id":       claOrgID,
+		fieldCorporationID: util.EmailSuffix(email),
+	}
+	if role != "" {
+		filter["role"] = role
+	}
+	return filter
 }
 
 func (c *client) AddCorporationManager(claOrgID string, opt []dbmodels.CorporationManagerCreateOption, managerNumber int) ([]dbmodels.CorporationManagerCreateOption, error) {
@@ -66,37 +75,27 @@ func (c *client) AddCorporationManager(claOrgID string, opt []dbmodels.Corporati
 			}
 		}
 
-		col := c.collection(claOrgCollection)
-
-		updates := make(bson.A, 0, len(opt))
+		docs := make([]interface{}, 0, len(toAdd))
 		for _, item := range toAdd {
-			info := corporationManagerDoc{
-				Email:    item.Email,
-				Role:     item.Role,
-				Password: item.Password,
-			}
-
-			body, err := structToMap(info)
+			pw, err := util.HashPassword(item.Password)
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to hash password: %s", err.Error())
 			}
-			addCorporationID(item.Email, body)
 
-			updates = append(updates, bson.M(body))
+			docs = append(docs, corporationManagerDoc{
+				CLAOrgID: oid,
+				CorpID:   util.EmailSuffix(item.Email),
+				Email:    item.Email,
+				Role:     item.Role,
+				Password: pw,
+			})
 		}
 
-		v, err := col.UpdateOne(
-			ctx, bson.M{"_id": oid},
-			bson.M{"$push": bson.M{fieldCorpoManagers: bson.M{"$each": updates}}},
-		)
-		if err != nil {
+		col := c.collection(corpManagerCollection)
+		if _, err := col.InsertMany(ctx, docs); err != nil {
 			return fmt.Errorf("write db failed: %s", err.Error())
 		}
 
-		if v.ModifiedCount != 1 {
-			return fmt.Errorf("impossible")
-		}
-
 		if opt[0].Role == dbmodels.RoleAdmin {
 			return c.setAdministratorAdded(oid, opt[0].Email, ctx)
 		}
@@ -108,124 +107,185 @@ func (c *client) AddCorporationManager(claOrgID string, opt []dbmodels.Corporati
 }
 
 func (c *client) CheckCorporationManagerExist(opt dbmodels.CorporationManagerCheckInfo) (map[string][]dbmodels.CorporationManagerCheckResult, error) {
-	filter := bson.M{}
-	filterForCorpManager(filter)
-
-	var v []CLAOrg
+	var ms []corporationManagerDoc
+	var bindings []CLAOrg
 
 	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
-
-		pipeline := bson.A{
-			bson.M{"$match": filter},
-			bson.M{"$project": bson.M{
-				"platform": 1,
-				"org_id":   1,
-				"repo_id":  1,
-				fieldCorpoManagers: bson.M{"$filter": bson.M{
-					"input": fmt.Sprintf("$%s", fieldCorpoManagers),
-					"cond": bson.M{"$and": bson.A{
-						bson.M{"$eq": bson.A{"$$this.corp_id", util.EmailSuffix(opt.User)}},
-						bson.M{"$eq": bson.A{"$$this.email", opt.User}},
-						bson.M{"$eq": bson.A{"$$this.password", opt.Password}},
-					}},
-				}}},
-			},
-			bson.M{"$project": bson.M{
-				"platform":                  1,
-				"org_id":                    1,
-				"repo_id":                   1,
-				corpManagerField("role"):    1,
-				corpManagerField("email"):   1,
-				corpManagerField("changed"): 1,
-			}},
+		col := c.collection(corpManagerCollection)
+
+		cursor, err := col.Find(ctx, bson.M{
+			fieldCorporationID: util.EmailSuffix(opt.User),
+			"email":            opt.User,
+		})
+		if err != nil {
+			return fmt.Errorf("error find corporation managers: %v", err)
+		}
+
+		if err := cursor.All(ctx, &ms); err != nil {
+			return err
 		}
 
-		cursor, err := col.Aggregate(ctx, pipeline)
+		if len(ms) == 0 {
+			return nil
+		}
+
+		ids := make(bson.A, 0, len(ms))
+		for i := range ms {
+			ids = append(ids, ms[i].CLAOrgID)
+		}
+
+		filter := bson.M{"_id": bson.M{"$in": ids}}
+		filterForCorpManager(filter)
+
+		cursor, err = c.collection(claOrgCollection).Find(ctx, filter)
 		if err != nil {
 			return fmt.Errorf("error find bindings: %v", err)
 		}
 
-		return cursor.All(ctx, &v)
+		return cursor.All(ctx, &bindings)
 	}
 
 	if err := withContext(f); err != nil {
 		return nil, err
 	}
 
-	if len(v) == 0 {
-		return nil, dbmodels.DBError{
-			ErrCode: util.ErrNoCLABindingDoc,
-			Err:     fmt.Errorf("no cla binding found"),
-		}
+	docs := make(map[primitive.ObjectID]*CLAOrg, len(bindings))
+	for i := range bindings {
+		docs[bindings[i].ID] = &bindings[i]
 	}
 
 	result := map[string][]dbmodels.CorporationManagerCheckResult{}
-	for _, doc := range v {
-		cm := doc.CorporationManagers
-		if len(cm) == 0 {
+	for i := range ms {
+		item := &ms[i]
+
+		doc, ok := docs[item.CLAOrgID]
+		if !ok {
 			continue
 		}
 
-		// If len(cm) > 1, it happened when administrator add himself as the manager
-		// But, it will not happend
-		ms := make([]dbmodels.CorporationManagerCheckResult, 0, len(cm))
-		for _, item := range cm {
-			ms = append(ms, dbmodels.CorporationManagerCheckResult{
-				Email:            item.Email,
-				Role:             item.Role,
-				Platform:         doc.Platform,
-				OrgID:            doc.OrgID,
-				RepoID:           doc.RepoID,
-				InitialPWChanged: item.InitialPWChanged,
-			})
+		if !util.CheckPassword(item.Password, opt.Password) {
+			continue
+		}
+
+		if !util.IsPasswordHashed(item.Password) {
+			// It is the plaintext password stored by the early versions.
+			if err := c.rehashCorporationManagerPassword(doc.ID, item.Email, item.Password); err != nil {
+				return nil, err
+			}
 		}
-		result[objectIDToUID(doc.ID)] = ms
+
+		k := objectIDToUID(doc.ID)
+		result[k] = append(result[k], dbmodels.CorporationManagerCheckResult{
+			Email:            item.Email,
+			Role:             item.Role,
+			Platform:         doc.Platform,
+			OrgID:            doc.OrgID,
+			RepoID:           doc.RepoID,
+			InitialPWChanged: item.InitialPWChanged,
+		})
 	}
 	return result, nil
 }
 
-func (c *client) ResetCorporationManagerPassword(claOrgID, email string, opt dbmodels.CorporationManagerResetPassword) error {
-	oid, err := toObjectID(claOrgID)
+func (c *client) rehashCorporationManagerPassword(claOrgID primitive.ObjectID, email, pw string) error {
+	hashed, err := util.HashPassword(pw)
 	if err != nil {
+		return fmt.Errorf("failed to hash password: %s", err.Error())
+	}
+
+	return withContext(func(ctx context.Context) error {
+		_, err := c.updateCorporationManagerPassword(claOrgID, email, pw, hashed, false, ctx)
 		return err
+	})
+}
+
+// updateCorporationManagerPassword updates the password only if the stored one is
+// still same as old, in case it was changed concurrently.
+func (c *client) updateCorporationManagerPassword(claOrgID primitive.ObjectID, email, old, newOne string, changed bool, ctx context.Context) (*mongo.UpdateResult, error) {
+	col := c.collection(corpManagerCollection)
+
+	filter := filterOfCorpManager(claOrgID, email, "")
+	filter["email"] = email
+	filter["password"] = old
+
+	v := bson.M{"password": newOne}
+	if changed {
+		v["changed"] = true
 	}
 
-	filter := bson.M{"_id": oid}
+	return col.UpdateOne(ctx, filter, bson.M{"$set": v})
+}
+
+// checkCorpManagerBinding checks whether the binding exists and is applied to corporation.
+func (c *client) checkCorpManagerBinding(claOrgID primitive.ObjectID, ctx context.Context) error {
+	filter := bson.M{"_id": claOrgID}
 	filterForCorpManager(filter)
 
-	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
-
-		update := bson.M{"$set": bson.M{
-			fmt.Sprintf("%s.$[ms].password", fieldCorpoManagers): opt.NewPassword,
-			fmt.Sprintf("%s.$[ms].changed", fieldCorpoManagers):  true,
-		}}
-
-		updateOpt := options.UpdateOptions{
-			ArrayFilters: &options.ArrayFilters{
-				Filters: bson.A{
-					bson.M{
-						"ms.corp_id":  util.EmailSuffix(email),
-						"ms.email":    email,
-						"ms.password": opt.OldPassword,
-					},
-				},
-			},
+	b, err := c.isBindingExist(filter, ctx)
+	if err != nil {
+		return err
+	}
+
+	if !b {
+		return dbmodels.DBError{
+			ErrCode: util.ErrNoCLABindingDoc,
+			Err:     fmt.Errorf("can't find the cla"),
 		}
+	}
+	return nil
+}
 
-		v, err := col.UpdateOne(ctx, filter, update, &updateOpt)
+func (c *client) getCorporationManager(claOrgID primitive.ObjectID, email string, ctx context.Context) (*corporationManagerDoc, error) {
+	if err := c.checkCorpManagerBinding(claOrgID, ctx); err != nil {
+		return nil, err
+	}
+
+	filter := filterOfCorpManager(claOrgID, email, "")
+	filter["email"] = email
+
+	var v corporationManagerDoc
+	if err := c.collection(corpManagerCollection).FindOne(ctx, filter).Decode(&v); err != nil {
+		if isErrNoDocuments(err) {
+			return nil, dbmodels.DBError{
+				ErrCode: util.ErrInvalidParameter,
+				Err:     fmt.Errorf("invalid email or old password"),
+			}
+		}
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func (c *client) ResetCorporationManagerPassword(claOrgID, email string, opt dbmodels.CorporationManagerResetPassword) error {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return err
+	}
+
+	pw, err := util.HashPassword(opt.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %s", err.Error())
+	}
+
+	f := func(ctx context.Context) error {
+		m, err := c.getCorporationManager(oid, email, ctx)
 		if err != nil {
 			return err
 		}
 
-		if v.MatchedCount == 0 {
+		if !util.CheckPassword(m.Password, opt.OldPassword) {
 			return dbmodels.DBError{
-				ErrCode: util.ErrNoCLABindingDoc,
-				Err:     fmt.Errorf("can't find the cla"),
+				ErrCode: util.ErrInvalidParameter,
+				Err:     fmt.Errorf("invalid email or old password"),
 			}
 		}
 
+		v, err := c.updateCorporationManagerPassword(oid, email, m.Password, pw, true, ctx)
+		if err != nil {
+			return err
+		}
+
 		if v.ModifiedCount != 1 {
 			return dbmodels.DBError{
 				ErrCode: util.ErrInvalidParameter,
@@ -240,82 +300,67 @@ func (c *client) ResetCorporationManagerPassword(claOrgID, email string, opt dbm
 }
 
 func (c *client) listCorporationManager(claOrgID primitive.ObjectID, email, role string, ctx context.Context) ([]dbmodels.CorporationManagerListResult, error) {
-	filter := bson.M{"_id": claOrgID}
-	filterForCorpManager(filter)
-
-	cond := bson.A{
-		bson.M{"$eq": bson.A{"$$this.corp_id", util.EmailSuffix(email)}},
-	}
-	if role != "" {
-		cond = append(cond, bson.M{"$eq": bson.A{"$$this.role", role}})
-
+	if err := c.checkCorpManagerBinding(claOrgID, ctx); err != nil {
+		return nil, err
 	}
 
-	var v []CLAOrg
-	f := func() error {
-		col := c.collection(claOrgCollection)
-
-		pipeline := bson.A{
-			bson.M{"$match": filter},
-			bson.M{"$project": bson.M{
-				fieldCorpoManagers: bson.M{"$filter": bson.M{
-					"input": fmt.Sprintf("$%s", fieldCorpoManagers),
-					"cond":  cond,
-				}}},
-			},
-			bson.M{"$project": bson.M{
-				corpManagerField("email"): 1,
-				corpManagerField("role"):  1,
-			}},
-		}
-
-		cursor, err := col.Aggregate(ctx, pipeline)
-		if err != nil {
-			return fmt.Errorf("error find bindings: %v", err)
-		}
-
-		return cursor.All(ctx, &v)
+	cursor, err := c.collection(corpManagerCollection).Find(ctx, filterOfCorpManager(claOrgID, email, role))
+	if err != nil {
+		return nil, fmt.Errorf("error find corporation managers: %v", err)
 	}
 
-	if err := f(); err != nil {
+	var v []corporationManagerDoc
+	if err := cursor.All(ctx, &v); err != nil {
 		return nil, err
 	}
 
-	if len(v) == 0 {
-		return nil, dbmodels.DBError{
-			ErrCode: util.ErrNoCLABindingDoc,
-			Err:     fmt.Errorf("can't find the cla"),
-		}
-	}
-
-	ms := v[0].CorporationManagers
-	r := make([]dbmodels.CorporationManagerListResult, 0, len(ms))
-	for _, item := range ms {
+	r := make([]dbmodels.CorporationManagerListResult, 0, len(v))
+	for i := range v {
 		r = append(r, dbmodels.CorporationManagerListResult{
-			Email: item.Email,
-			Role:  item.Role,
+			Email: v[i].Email,
+			Role:  v[i].Role,
 		})
 	}
 	return r, nil
 }
 
-func (c *client) ListCorporationManager(claOrgID, email, role string) ([]dbmodels.CorporationManagerListResult, error) {
+func (c *client) ListCorporationManager(claOrgID, email, role string, page dbmodels.PageOption) ([]dbmodels.CorporationManagerListResult, int, error) {
 	oid, err := toObjectID(claOrgID)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	var r []dbmodels.CorporationManagerListResult
+	var v []corporationManagerDoc
+	total := 0
 
 	f := func(ctx context.Context) error {
-		v, err := c.listCorporationManager(oid, email, role, ctx)
-		r = v
+		if err := c.checkCorpManagerBinding(oid, ctx); err != nil {
+			return err
+		}
+
+		n, err := c.listPage(
+			corpManagerCollection, filterOfCorpManager(oid, email, role),
+			page,
+			bson.D{{Key: "email", Value: sortDirection(page)}},
+			bson.M{"email": 1, "role": 1},
+			&v, ctx,
+		)
+		total = n
 		return err
 	}
 
-	err = withContext(f)
+	if err := withContext(f); err != nil {
+		return nil, 0, err
+	}
 
-	return r, err
+	r := make([]dbmodels.CorporationManagerListResult, 0, len(v))
+	for i := range v {
+		r = append(r, dbmodels.CorporationManagerListResult{
+			Email: v[i].Email,
+			Role:  v[i].Role,
+		})
+	}
+	return r, total, nil
 }
 
 func (c *client) DeleteCorporationManager(claOrgID string, opt []dbmodels.CorporationManagerCreateOption) ([]string, error) {
@@ -348,24 +393,12 @@ func (c *client) DeleteCorporationManager(claOrgID string, opt []dbmodels.Corpor
 			return nil
 		}
 
-		col := c.collection(claOrgCollection)
+		filter := filterOfCorpManager(oid, opt[0].Email, opt[0].Role)
+		filter["email"] = bson.M{"$in": toDelete}
 
-		update := bson.M{"$pull": bson.M{
-			fieldCorpoManagers: bson.M{
-				"corp_id": util.EmailSuffix(opt[0].Email),
-				"email":   bson.M{"$in": toDelete},
-			},
-		}}
-
-		v, err := col.UpdateOne(ctx, bson.M{"_id": oid}, update)
-		if err != nil {
+		if _, err := c.collection(corpManagerCollection).DeleteMany(ctx, filter); err != nil {
 			return fmt.Errorf("failed to write db: %s", err.Error())
 		}
-
-		if v.ModifiedCount != 1 {
-			return fmt.Errorf("impossible.")
-		}
-
 		return nil
 	}
 
@@ -13,27 +13,45 @@ import (
 	"github.com/opensourceways/app-cla-server/util"
 )
 
-type corporationSigningDoc struct {
-	AdminEmail      string                   `bson:"admin_email" json:"admin_email" required:"true"`
-	AdminName       string                   `bson:"admin_name" json:"admin_name" required:"true"`
-	CorporationName string                   `bson:"corp_name" json:"corp_name" required:"true"`
-	Date            string                   `bson:"date" json:"date" required:"true"`
-	SigningInfo     dbmodels.TypeSigningInfo `bson:"info" json:"info,omitempty"`
+const (
+	corpSigningCollection = "corporation_signings"
+	// corpPDFCollection keeps the pdf of corporation signing apart,
+	// so that listing the signings needn't load the big files.
+	corpPDFCollection = "corporation_signing_pdfs"
+)
 
-	PDFUploaded bool `bson:"pdf_uploaded" json:"pdf_uploaded"`
-	AdminAdded  bool `bson:"admin_added" json:"admin_added"`
+type corporationSigningDoc struct {
+	CLAOrgID        primitive.ObjectID       `bson:"cla_org_id"`
+	CorpID          string                   `bson:"corp_id"`
+	AdminEmail      string                   `bson:"admin_email"`
+	AdminName       string                   `bson:"admin_name"`
+	CorporationName string                   `bson:"corp_name"`
+	Date            string                   `bson:"date"`
+	SigningInfo     dbmodels.TypeSigningInfo `bson:"info,omitempty"`
+	CLAVersion      int                      `bson:"cla_version,omitempty"`
+
+	PDFUploaded   bool   `bson:"pdf_uploaded"`
+	AdminAdded    bool   `bson:"admin_added"`
+	Status        string `bson:"status"`
+	ReviewComment string `bson:"review_comment"`
+}
 
-	PDF []byte `bson:"pdf" json:"pdf,omitempty"`
+type corporationPDFDoc struct {
+	CLAOrgID primitive.ObjectID `bson:"cla_org_id"`
+	CorpID   string             `bson:"corp_id"`
+	PDF      []byte             `bson:"pdf"`
 }
 
 func filterForCorpSigning(filter bson.M) {
 	filter["apply_to"] = dbmodels.ApplyToCorporation
 	filter["enabled"] = true
-	filter[fieldCorporations] = bson.M{"$type": "array"}
 }
 
-func corpSigningField(field string) string {
-	return fmt.Sprintf("%s.%s", fieldCorporations, field)
+func filterOfCorpSigning(claOrgID primitive.ObjectID, email string) bson.M {
+	return bson.M{
+		"cla_org_id":       claOrgID,
+		fieldCorporationID: util.EmailSuffix(email),
+	}
 }
 
 func (c *client) SignAsCorporation(claOrgID, platform, org, repo string, info dbmodels.CorporationSigningInfo) error {
@@ -42,40 +60,51 @@ func (c *client) SignAsCorporation(claOrgID, platform, org, repo string, info db
 		return err
 	}
 
-	signing := corporationSigningDoc{
+	doc := corporationSigningDoc{
+		CLAOrgID:        oid,
+		CorpID:          util.EmailSuffix(info.AdminEmail),
 		AdminEmail:      info.AdminEmail,
 		AdminName:       info.AdminName,
 		CorporationName: info.CorporationName,
 		Date:            info.Date,
 		SigningInfo:     info.Info,
+		Status:          dbmodels.CorpSigningStatusSubmitted,
 	}
-	body, err := structToMap(signing)
-	if err != nil {
-		return err
-	}
-	addCorporationID(info.AdminEmail, body)
 
 	f := func(ctx mongo.SessionContext) error {
-		_, _, err := c.getCorporationSigningDetail(platform, org, repo, info.AdminEmail, ctx)
-		if err != nil {
-			if !isHasNotSigned(err) {
-				return err
-			}
-		} else {
-			return dbmodels.DBError{
-				ErrCode: util.ErrHasSigned,
-				Err:     fmt.Errorf("this corp has already signed"),
-			}
+		cid, detail, err := c.getCorporationSigningDetail(platform, org, repo, info.AdminEmail, ctx)
+		signed := err == nil
+		if !signed && !isHasNotSigned(err) {
+			return err
 		}
 
-		col := c.collection(claOrgCollection)
-		r, err := col.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$push": bson.M{fieldCorporations: bson.M(body)}})
+		v, err := c.getCLAVersion(oid, ctx)
 		if err != nil {
 			return err
 		}
+		doc.CLAVersion = v
 
-		if r.ModifiedCount == 0 {
-			return fmt.Errorf("impossible")
+		if signed {
+			if cid != claOrgID || detail.CLAVersion >= v {
+				return dbmodels.DBError{
+					ErrCode: util.ErrHasSigned,
+					Err:     fmt.Errorf("this corp has already signed"),
+				}
+			}
+
+			// re-sign the new version of cla
+			return c.resignAsCorporation(oid, detail.AdminEmail, &doc, ctx)
+		}
+
+		col := c.collection(corpSigningCollection)
+		if _, err := col.InsertOne(ctx, doc); err != nil {
+			if isDuplicateKeyError(err) {
+				return dbmodels.DBError{
+					ErrCode: util.ErrHasSigned,
+					Err:     fmt.Errorf("this corp has already signed"),
+				}
+			}
+			return err
 		}
 		return nil
 	}
@@ -83,109 +112,209 @@ func (c *client) SignAsCorporation(claOrgID, platform, org, repo string, info db
 	return c.doTransaction(f)
 }
 
-func (c *client) ListCorporationSigning(opt dbmodels.CorporationSigningListOption) (map[string][]dbmodels.CorporationSigningDetail, error) {
-	info := struct {
-		Platform    string `json:"platform" required:"true"`
-		OrgID       string `json:"org_id" required:"true"`
-		RepoID      string `json:"repo_id"`
-		CLALanguage string `json:"cla_language,omitempty"`
-	}{
-		Platform:    opt.Platform,
-		OrgID:       opt.OrgID,
-		RepoID:      opt.RepoID,
-		CLALanguage: opt.CLALanguage,
+// resignAsCorporation replaces the outdated signing by the one of new cla version.
+// The pdf has to be uploaded and reviewed again, but the managers are kept.
+func (c *client) resignAsCorporation(claOrgID primitive.ObjectID, adminEmail string, doc *corporationSigningDoc, ctx context.Context) error {
+	if doc.AdminEmail != adminEmail {
+		return dbmodels.DBError{
+			ErrCode: util.ErrHasSigned,
+			Err:     fmt.Errorf("this corp can only re-sign by its administrator"),
+		}
 	}
 
-	body, err := structToMap(info)
+	filter := filterOfCorpSigning(claOrgID, adminEmail)
+	filter["$or"] = bson.A{
+		bson.M{fieldCLAVersion: bson.M{"$lt": doc.CLAVersion}},
+		bson.M{fieldCLAVersion: bson.M{"$exists": false}},
+	}
+
+	update := bson.M{"$set": bson.M{
+		"admin_name":     doc.AdminName,
+		"corp_name":      doc.CorporationName,
+		"date":           doc.Date,
+		"info":           doc.SigningInfo,
+		fieldCLAVersion:  doc.CLAVersion,
+		"pdf_uploaded":   false,
+		"status":         dbmodels.CorpSigningStatusSubmitted,
+		"review_comment": "",
+	}}
+
+	r, err := c.collection(corpSigningCollection).UpdateOne(ctx, filter, update)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if r.MatchedCount == 0 {
+		return dbmodels.DBError{
+			ErrCode: util.ErrHasSigned,
+			Err:     fmt.Errorf("this corp has already signed"),
+		}
 	}
-	filter := bson.M(body)
-	filterForCorpSigning(filter)
 
-	var v []CLAOrg
+	_, err = c.collection(corpPDFCollection).DeleteOne(ctx, filterOfCorpSigning(claOrgID, adminEmail))
+	return err
+}
 
-	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
+func (c *client) ListCorporationSigning(opt dbmodels.CorporationSigningListOption) ([]dbmodels.CorporationSigningListItem, int, error) {
+	filter, err := corpSigningListFilter(opt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filterOfSigning := bson.M{}
+	signingFilter(filterOfSigning, opt.Filter, "admin_added", "admin_email")
+	if opt.Status != "" {
+		filterOfSigning["status"] = opt.Status
+	}
 
-		pipeline := bson.A{
-			bson.M{"$match": filter},
-			bson.M{"$project": bson.M{
-				corpSigningField("admin_email"):  1,
-				corpSigningField("admin_name"):   1,
-				corpSigningField("corp_name"):    1,
-				corpSigningField("date"):         1,
-				corpSigningField("pdf_uploaded"): 1,
-				corpSigningField("admin_added"):  1,
-			}},
+	sortField := "date"
+	switch opt.Page.SortBy {
+	case dbmodels.SortByName:
+		sortField = "corp_name"
+	case dbmodels.SortByEmail:
+		sortField = "admin_email"
+	}
+
+	var v []corporationSigningDoc
+	total := 0
+
+	f := func(ctx context.Context) error {
+		ids, err := c.listBindingIDs(filter, ctx)
+		if err != nil || len(ids) == 0 {
+			return err
 		}
-		cursor, err := col.Aggregate(ctx, pipeline)
-		if err != nil {
+		filterOfSigning["cla_org_id"] = bson.M{"$in": ids}
+
+		n, err := c.listPage(
+			corpSigningCollection, filterOfSigning,
+			opt.Page,
+			bson.D{
+				{Key: sortField, Value: sortDirection(opt.Page)},
+				{Key: "admin_email", Value: 1},
+			},
+			bson.M{"info": 0},
+			&v, ctx,
+		)
+		total = n
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, err
+	}
+
+	r := make([]dbmodels.CorporationSigningListItem, 0, len(v))
+	for i := range v {
+		r = append(r, dbmodels.CorporationSigningListItem{
+			CLAOrgID:                 objectIDToUID(v[i].CLAOrgID),
+			CorporationSigningDetail: toDBModelCorporationSigningDetail(&v[i]),
+		})
+	}
+	return r, total, nil
+}
+
+func (c *client) ListCorporationSigningInfo(opt dbmodels.CorporationSigningListOption) (map[string][]dbmodels.CorporationSigningFullInfo, error) {
+	filter, err := corpSigningListFilter(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var v []corporationSigningDoc
+
+	f := func(ctx context.Context) error {
+		ids, err := c.listBindingIDs(filter, ctx)
+		if err != nil || len(ids) == 0 {
 			return err
 		}
 
-		err = cursor.All(ctx, &v)
+		col := c.collection(corpSigningCollection)
+
+		cursor, err := col.Find(ctx, bson.M{"cla_org_id": bson.M{"$in": ids}})
 		if err != nil {
 			return err
 		}
-		return nil
+
+		return cursor.All(ctx, &v)
 	}
 
-	if err = withContext(f); err != nil {
+	if err := withContext(f); err != nil {
 		return nil, err
 	}
 
-	r := map[string][]dbmodels.CorporationSigningDetail{}
-	for _, doc := range v {
-		cs := doc.Corporations
-		if len(cs) == 0 {
-			continue
-		}
+	r := map[string][]dbmodels.CorporationSigningFullInfo{}
+	for i := range v {
+		item := &v[i]
 
-		cs1 := make([]dbmodels.CorporationSigningDetail, 0, len(cs))
-		for _, item := range cs {
-			cs1 = append(cs1, toDBModelCorporationSigningDetail(&item))
-		}
-		r[objectIDToUID(doc.ID)] = cs1
+		k := objectIDToUID(item.CLAOrgID)
+		r[k] = append(r[k], dbmodels.CorporationSigningFullInfo{
+			CorporationSigningDetail: toDBModelCorporationSigningDetail(item),
+			Info:                     item.SigningInfo,
+		})
 	}
 
 	return r, nil
 }
 
-func (c *client) setAdministratorAdded(claOrgID primitive.ObjectID, email string, ctx context.Context) error {
-	col := c.collection(claOrgCollection)
+func corpSigningListFilter(opt dbmodels.CorporationSigningListOption) (bson.M, error) {
+	info := struct {
+		Platform    string `json:"platform" required:"true"`
+		OrgID       string `json:"org_id" required:"true"`
+		RepoID      string `json:"repo_id"`
+		CLALanguage string `json:"cla_language,omitempty"`
+	}{
+		Platform:    opt.Platform,
+		OrgID:       opt.OrgID,
+		RepoID:      opt.RepoID,
+		CLALanguage: opt.CLALanguage,
+	}
 
-	filter := bson.M{"_id": claOrgID}
+	body, err := structToMap(info)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M(body)
 	filterForCorpSigning(filter)
 
-	update := bson.M{"$set": bson.M{
-		fmt.Sprintf("%s.$[elem].admin_added", fieldCorporations): true,
-	}}
+	return filter, nil
+}
 
-	updateOpt := options.UpdateOptions{
-		ArrayFilters: &options.ArrayFilters{
-			Filters: bson.A{
-				bson.M{
-					"elem.corp_id": util.EmailSuffix(email),
-				},
-			},
-		},
-	}
+func (c *client) setAdministratorAdded(claOrgID primitive.ObjectID, email string, ctx context.Context) error {
+	col := c.collection(corpSigningCollection)
 
-	r, err := col.UpdateOne(ctx, filter, update, &updateOpt)
+	r, err := col.UpdateOne(
+		ctx, filterOfCorpSigning(claOrgID, email),
+		bson.M{"$set": bson.M{
+			"admin_added": true,
+			"status":      dbmodels.CorpSigningStatusAdminCreated,
+		}},
+	)
 	if err != nil {
 		return err
 	}
 
 	if r.MatchedCount == 0 {
 		return dbmodels.DBError{
-			ErrCode: util.ErrNoCLABindingDoc,
-			Err:     fmt.Errorf("can't find the cla"),
+			ErrCode: util.ErrHasNotSigned,
+			Err:     fmt.Errorf("the corp:%s has not signed", util.EmailSuffix(email)),
 		}
 	}
+	return nil
+}
+
+// checkCorpBinding checks whether the binding exists and is applied to corporation.
+func (c *client) checkCorpBinding(claOrgID primitive.ObjectID, errCode string, ctx context.Context) error {
+	filter := bson.M{"_id": claOrgID}
+	filterForCorpSigning(filter)
+
+	b, err := c.isBindingExist(filter, ctx)
+	if err != nil {
+		return err
+	}
 
-	if r.ModifiedCount == 0 {
-		return fmt.Errorf("impossible")
+	if !b {
+		return dbmodels.DBError{
+			ErrCode: errCode,
+			Err:     fmt.Errorf("can't find the cla"),
+		}
 	}
 	return nil
 }
@@ -196,139 +325,202 @@ func (c *client) UploadCorporationSigningPDF(claOrgID, adminEmail string, pdf []
 		return err
 	}
 
-	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
-
-		filter := bson.M{"_id": oid}
-		filterForCorpSigning(filter)
-
-		update := bson.M{"$set": bson.M{
-			fmt.Sprintf("%s.$[elem].pdf", fieldCorporations):          pdf,
-			fmt.Sprintf("%s.$[elem].pdf_uploaded", fieldCorporations): true,
-		}}
-
-		updateOpt := options.UpdateOptions{
-			ArrayFilters: &options.ArrayFilters{
-				Filters: bson.A{
-					bson.M{
-						"elem.corp_id": util.EmailSuffix(adminEmail),
-					},
-				},
-			},
+	f := func(ctx mongo.SessionContext) error {
+		if err := c.checkCorpBinding(oid, util.ErrInvalidParameter, ctx); err != nil {
+			return err
 		}
 
-		r, err := col.UpdateOne(ctx, filter, update, &updateOpt)
+		filter := filterOfCorpSigning(oid, adminEmail)
+
+		err := c.changeCorpSigningStatus(
+			filter, dbmodels.CorpSigningStatusPDFUploaded,
+			bson.M{"pdf_uploaded": true}, util.ErrInvalidParameter, ctx,
+		)
 		if err != nil {
 			return err
 		}
 
-		if r.MatchedCount == 0 {
-			return dbmodels.DBError{
-				ErrCode: util.ErrInvalidParameter,
-				Err:     fmt.Errorf("can't find the cla"),
-			}
+		doc := corporationPDFDoc{
+			CLAOrgID: oid,
+			CorpID:   util.EmailSuffix(adminEmail),
+			PDF:      pdf,
+		}
+
+		upsert := true
+		_, err = c.collection(corpPDFCollection).ReplaceOne(
+			ctx, filter, doc, &options.ReplaceOptions{Upsert: &upsert},
+		)
+		return err
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) UpdateCorporationSigningStatus(claOrgID, email string, opt dbmodels.CorporationSigningStatusUpdate) error {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		if err := c.checkCorpBinding(oid, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
 		}
 
-		if r.ModifiedCount == 0 {
+		return c.changeCorpSigningStatus(
+			filterOfCorpSigning(oid, email), opt.Status,
+			bson.M{"review_comment": opt.Comment}, util.ErrHasNotSigned, ctx,
+		)
+	}
+
+	return withContext(f)
+}
+
+// changeCorpSigningStatus changes the status of signing to 'to' and sets the fields
+// only if the current status allows the change. It returns the error of errCode if
+// the signing doesn't exist.
+func (c *client) changeCorpSigningStatus(filter bson.M, to string, fields bson.M, errCode string, ctx context.Context) error {
+	col := c.collection(corpSigningCollection)
+
+	update := bson.M{"status": to}
+	for k, v := range fields {
+		update[k] = v
+	}
+
+	cond := bson.M{"status": bson.M{"$in": dbmodels.CorpSigningStatusFrom(to)}}
+	for k, v := range filter {
+		cond[k] = v
+	}
+
+	r, err := col.UpdateOne(ctx, cond, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if r.MatchedCount > 0 {
+		return nil
+	}
+
+	var v corporationSigningDoc
+	if err := col.FindOne(ctx, filter).Decode(&v); err != nil {
+		if isErrNoDocuments(err) {
 			return dbmodels.DBError{
-				ErrCode: util.ErrInvalidParameter,
+				ErrCode: errCode,
 				Err:     fmt.Errorf("can't find the corp signing record"),
 			}
 		}
+		return err
+	}
+
+	return dbmodels.DBError{
+		ErrCode: util.ErrInvalidSigningStatus,
+		Err:     fmt.Errorf("can't change the status of corp signing from %s to %s", v.Status, to),
+	}
+}
+
+// addCorpSigningStatus derives the status of existing signings from the flags of them.
+func (c *client) addCorpSigningStatus() error {
+	steps := []struct {
+		filter bson.M
+		status string
+	}{
+		{bson.M{"status": bson.M{"$exists": false}}, dbmodels.CorpSigningStatusSubmitted},
+		{bson.M{"pdf_uploaded": true, "admin_added": false}, dbmodels.CorpSigningStatusPDFUploaded},
+		{bson.M{"admin_added": true}, dbmodels.CorpSigningStatusAdminCreated},
+	}
+
+	f := func(ctx context.Context) error {
+		col := c.collection(corpSigningCollection)
+
+		for _, item := range steps {
+			_, err := col.UpdateMany(
+				ctx, item.filter,
+				bson.M{"$set": bson.M{"status": item.status, "review_comment": ""}},
+			)
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 
 	return withContext(f)
 }
 
+func (c *client) dropCorpSigningStatus() error {
+	f := func(ctx context.Context) error {
+		_, err := c.collection(corpSigningCollection).UpdateMany(
+			ctx, bson.M{}, bson.M{"$unset": bson.M{"status": "", "review_comment": ""}},
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
 func (c *client) DownloadCorporationSigningPDF(claOrgID, email string) ([]byte, error) {
 	oid, err := toObjectID(claOrgID)
 	if err != nil {
 		return nil, err
 	}
 
-	var v []CLAOrg
+	var v corporationPDFDoc
 
 	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
-
-		filter := bson.M{"_id": oid}
-		filterForCorpSigning(filter)
-
-		pipeline := bson.A{
-			bson.M{"$match": filter},
-			bson.M{"$project": bson.M{
-				fieldCorporations: bson.M{"$filter": bson.M{
-					"input": fmt.Sprintf("$%s", fieldCorporations),
-					"cond":  bson.M{"$eq": bson.A{"$$this.corp_id", util.EmailSuffix(email)}},
-				}},
-			}},
-			bson.M{"$project": bson.M{
-				corpSigningField("pdf"):          1,
-				corpSigningField("pdf_uploaded"): 1,
-			}},
+		if err := c.checkCorpBinding(oid, util.ErrInvalidParameter, ctx); err != nil {
+			return err
 		}
-		cursor, err := col.Aggregate(ctx, pipeline)
+
+		filter := filterOfCorpSigning(oid, email)
+
+		var signing corporationSigningDoc
+		err := c.collection(corpSigningCollection).FindOne(ctx, filter).Decode(&signing)
 		if err != nil {
+			if isErrNoDocuments(err) {
+				return dbmodels.DBError{
+					ErrCode: util.ErrInvalidParameter,
+					Err:     fmt.Errorf("can't find the corp signing in this record"),
+				}
+			}
 			return err
 		}
 
-		return cursor.All(ctx, &v)
-	}
-
-	err = withContext(f)
-	if err != nil {
-		return nil, err
-	}
+		pdfNotUploaded := dbmodels.DBError{
+			ErrCode: util.ErrPDFHasNotUploaded,
+			Err:     fmt.Errorf("pdf has not yet been uploaded"),
+		}
 
-	if len(v) == 0 {
-		return nil, dbmodels.DBError{
-			ErrCode: util.ErrInvalidParameter,
-			Err:     fmt.Errorf("can't find the cla"),
+		if !signing.PDFUploaded {
+			return pdfNotUploaded
 		}
-	}
 
-	cs := v[0].Corporations
-	if len(cs) == 0 {
-		return nil, dbmodels.DBError{
-			ErrCode: util.ErrInvalidParameter,
-			Err:     fmt.Errorf("can't find the corp signing in this record"),
+		err = c.collection(corpPDFCollection).FindOne(ctx, filter).Decode(&v)
+		if err != nil && isErrNoDocuments(err) {
+			return pdfNotUploaded
 		}
+		return err
 	}
 
-	item := cs[0]
-	if !item.PDFUploaded {
-		return nil, dbmodels.DBError{
-			ErrCode: util.ErrPDFHasNotUploaded,
-			Err:     fmt.Errorf("pdf has not yet been uploaded"),
-		}
+	if err := withContext(f); err != nil {
+		return nil, err
 	}
 
-	return item.PDF, nil
+	return v.PDF, nil
 }
+
 func (c *client) getCorporationSigningDetail(platform, org, repo, email string, ctx context.Context) (string, dbmodels.CorporationSigningDetail, error) {
-	filterOfSigning := bson.M{
-		fieldCorporations: bson.M{"$filter": bson.M{
-			"input": fmt.Sprintf("$%s", fieldCorporations),
-			"cond":  bson.M{"$eq": bson.A{"$$this.corp_id", util.EmailSuffix(email)}},
-		}},
+	filterOfSigning, err := c.corpIDFilter(email, ctx)
+	if err != nil {
+		return "", dbmodels.CorporationSigningDetail{}, err
 	}
 
-	project := bson.M{
-		corpSigningField("admin_email"):  1,
-		corpSigningField("admin_name"):   1,
-		corpSigningField("corp_name"):    1,
-		corpSigningField("date"):         1,
-		corpSigningField("pdf_uploaded"): 1,
-		corpSigningField("admin_added"):  1,
-	}
+	var v corporationSigningDoc
 
-	claOrg, err := c.getSigningDetail(platform, org, repo, dbmodels.ApplyToCorporation, false, filterOfSigning, project, ctx)
+	claOrg, err := c.getSigningDetail(platform, org, repo, dbmodels.ApplyToCorporation, false, filterOfSigning, &v, ctx)
 	if err != nil {
 		return "", dbmodels.CorporationSigningDetail{}, err
 	}
 
-	return objectIDToUID(claOrg.ID), toDBModelCorporationSigningDetail(&claOrg.Corporations[0]), nil
+	return objectIDToUID(claOrg.ID), toDBModelCorporationSigningDetail(&v), nil
 }
 
 func (c *client) GetCorporationSigningDetail(platform, org, repo, email string) (string, dbmodels.CorporationSigningDetail, error) {
@@ -355,60 +547,30 @@ func (c *client) CheckCorporationSigning(claOrgID, email string) (dbmodels.Corpo
 		return result, err
 	}
 
-	var v []CLAOrg
+	var v corporationSigningDoc
 
 	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
-
-		filter := bson.M{"_id": oid}
-		filterForCorpSigning(filter)
-
-		pipeline := bson.A{
-			bson.M{"$match": filter},
-			bson.M{"$project": bson.M{
-				fieldCorporations: bson.M{"$filter": bson.M{
-					"input": fmt.Sprintf("$%s", fieldCorporations),
-					"cond":  bson.M{"$eq": bson.A{"$$this.corp_id", util.EmailSuffix(email)}},
-				}},
-			}},
-			bson.M{"$project": bson.M{
-				corpSigningField("admin_email"):  1,
-				corpSigningField("admin_name"):   1,
-				corpSigningField("corp_name"):    1,
-				corpSigningField("date"):         1,
-				corpSigningField("pdf_uploaded"): 1,
-				corpSigningField("admin_added"):  1,
-			}},
-		}
-		cursor, err := col.Aggregate(ctx, pipeline)
-		if err != nil {
+		if err := c.checkCorpBinding(oid, util.ErrNoCLABindingDoc, ctx); err != nil {
 			return err
 		}
 
-		return cursor.All(ctx, &v)
-	}
-
-	err = withContext(f)
-	if err != nil {
-		return result, err
-	}
+		col := c.collection(corpSigningCollection)
 
-	if len(v) == 0 {
-		return result, dbmodels.DBError{
-			ErrCode: util.ErrNoCLABindingDoc,
-			Err:     fmt.Errorf("can't find the cla"),
+		err := col.FindOne(ctx, filterOfCorpSigning(oid, email)).Decode(&v)
+		if err != nil && isErrNoDocuments(err) {
+			return dbmodels.DBError{
+				ErrCode: util.ErrHasNotSigned,
+				Err:     fmt.Errorf("the corp:%s has not signed", util.EmailSuffix(email)),
+			}
 		}
+		return err
 	}
 
-	cs := v[0].Corporations
-	if len(cs) == 0 {
-		return result, dbmodels.DBError{
-			ErrCode: util.ErrHasNotSigned,
-			Err:     fmt.Errorf("the corp:%s has not signed", util.EmailSuffix(email)),
-		}
+	if err := withContext(f); err != nil {
+		return result, err
 	}
 
-	return toDBModelCorporationSigningDetail(&cs[0]), nil
+	return toDBModelCorporationSigningDetail(&v), nil
 }
 
 func toDBModelCorporationSigningDetail(cs *corporationSigningDoc) dbmodels.CorporationSigningDetail {
@@ -418,13 +580,18 @@ func toDBModelCorporationSigningDetail(cs *corporationSigningDoc) dbmodels.Corpo
 			AdminName:       cs.AdminName,
 			CorporationName: cs.CorporationName,
 			Date:            cs.Date,
+			CLAVersion:      normalizeCLAVersion(cs.CLAVersion),
 		},
-		PDFUploaded: cs.PDFUploaded,
-		AdminAdded:  cs.AdminAdded,
+		PDFUploaded:   cs.PDFUploaded,
+		AdminAdded:    cs.AdminAdded,
+		Status:        cs.Status,
+		ReviewComment: cs.ReviewComment,
 	}
 }
 
-func (c *client) getSigningDetail(platform, org, repo, applyTo string, orgCared bool, filterOfSigning, project bson.M, ctx context.Context) (*CLAOrg, error) {
+// getSigningDetail finds the signing of org/repo and decodes it to result.
+// It returns the binding which the signing belongs to.
+func (c *client) getSigningDetail(platform, org, repo, applyTo string, orgCared bool, filterOfSigning bson.M, result interface{}, ctx context.Context) (*CLAOrg, error) {
 	filter := bson.M{
 		"platform": platform,
 		"org_id":   org,
@@ -442,15 +609,15 @@ func (c *client) getSigningDetail(platform, org, repo, applyTo string, orgCared
 	f := func() error {
 		col := c.collection(claOrgCollection)
 
-		filterOfSigning[fieldRepo] = 1
-		project[fieldRepo] = 1
-
-		pipeline := bson.A{
-			bson.M{"$match": filter},
-			bson.M{"$project": filterOfSigning},
-			bson.M{"$project": project},
+		opt := options.FindOptions{
+			Projection: bson.M{
+				fieldRepo:         1,
+				fieldCLAVersion:   1,
+				"resign_required": 1,
+				"resign_deadline": 1,
+			},
 		}
-		cursor, err := col.Aggregate(ctx, pipeline)
+		cursor, err := col.Find(ctx, filter, &opt)
 		if err != nil {
 			return err
 		}
@@ -469,43 +636,51 @@ func (c *client) getSigningDetail(platform, org, repo, applyTo string, orgCared
 		}
 	}
 
-	err := dbmodels.DBError{
-		ErrCode: util.ErrHasNotSigned,
-		Err:     fmt.Errorf("the corp/individual has not signed for this org/repo: %s/%s/%s", platform, org, repo),
+	// the signing of repo is preferred if the repo has been bound cla.
+	bindings := v
+	if repo != "" && orgCared {
+		bindings = make([]CLAOrg, 0, len(v))
+		for i := range v {
+			if v[i].RepoID == repo {
+				bindings = append(bindings, v[i])
+			}
+		}
+		if len(bindings) == 0 {
+			bindings = v
+		}
 	}
 
-	isOk := func(doc *CLAOrg) bool {
-		if applyTo == dbmodels.ApplyToCorporation {
-			return len(doc.Corporations) > 0
-		}
-		return len(doc.Individuals) > 0
+	ids := make(bson.A, 0, len(bindings))
+	for i := range bindings {
+		ids = append(ids, bindings[i].ID)
 	}
+	filterOfSigning["cla_org_id"] = bson.M{"$in": ids}
 
-	if repo != "" && orgCared {
-		bingo := false
+	collection := individualSigningCollection
+	if applyTo == dbmodels.ApplyToCorporation {
+		collection = corpSigningCollection
+	}
 
-		for i := 0; i < len(v); i++ {
-			doc := &v[i]
-			if doc.RepoID == repo {
-				if !bingo {
-					bingo = true
-				}
-				if isOk(doc) {
-					return doc, nil
-				}
+	var raw bson.Raw
+	if err := c.collection(collection).FindOne(ctx, filterOfSigning).Decode(&raw); err != nil {
+		if isErrNoDocuments(err) {
+			return nil, dbmodels.DBError{
+				ErrCode: util.ErrHasNotSigned,
+				Err:     fmt.Errorf("the corp/individual has not signed for this org/repo: %s/%s/%s", platform, org, repo),
 			}
 		}
-		if bingo {
-			return nil, err
-		}
+		return nil, err
 	}
 
-	for i := 0; i < len(v); i++ {
-		doc := &v[i]
-		if isOk(doc) {
-			return doc, nil
-		}
+	if err := bson.Unmarshal(raw, result); err != nil {
+		return nil, err
 	}
 
-	return nil, err
+	oid, _ := raw.Lookup("cla_org_id").ObjectIDOK()
+	for i := range bindings {
+		if bindings[i].ID == oid {
+			return &bindings[i], nil
+		}
+	}
+	return nil, fmt.Errorf("impossible")
 }
@@ -0,0 +1,257 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	emailOutboxCollection = "email_outbox"
+	emailOutboxIndex      = "status_next_run_at"
+)
+
+type emailJobDoc struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Kind        string             `bson:"kind"`
+	OrgEmail    string             `bson:"org_email"`
+	Payload     []byte             `bson:"payload"`
+	Status      string             `bson:"status"`
+	Attempts    int                `bson:"attempts"`
+	MaxAttempts int                `bson:"max_attempts"`
+	NextRunAt   int64              `bson:"next_run_at"`
+	LastError   string             `bson:"last_error"`
+	LeaseOwner  string             `bson:"lease_owner"`
+	LeaseUntil  int64              `bson:"lease_until"`
+}
+
+func errEmailJobLeaseLost() error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrEmailJobLeaseLost,
+		Err:     fmt.Errorf("the lease of email job is lost"),
+	}
+}
+
+func (c *client) AddEmailJob(job dbmodels.EmailJob) (string, error) {
+	doc := emailJobDoc{
+		Kind:        job.Kind,
+		OrgEmail:    job.OrgEmail,
+		Payload:     job.Payload,
+		Status:      dbmodels.EmailJobStatusPending,
+		MaxAttempts: job.MaxAttempts,
+		NextRunAt:   job.NextRunAt,
+	}
+
+	uid := ""
+	f := func(ctx context.Context) error {
+		col := c.collection(emailOutboxCollection)
+
+		r, err := col.InsertOne(ctx, doc)
+		if err != nil {
+			return fmt.Errorf("failed to add email job: %s", err.Error())
+		}
+
+		v, err := toUID(r.InsertedID)
+		if err != nil {
+			return err
+		}
+		uid = v
+		return nil
+	}
+
+	err := withContext(f)
+	return uid, err
+}
+
+func (c *client) ClaimEmailJob(now, lease int64) (*dbmodels.EmailJob, error) {
+	var v emailJobDoc
+
+	f := func(ctx context.Context) error {
+		col := c.collection(emailOutboxCollection)
+
+		filter := bson.M{
+			"$or": bson.A{
+				bson.M{
+					"status":      dbmodels.EmailJobStatusPending,
+					"next_run_at": bson.M{"$lte": now},
+				},
+				bson.M{
+					"status":      dbmodels.EmailJobStatusRunning,
+					"lease_until": bson.M{"$lte": now},
+				},
+			},
+			"$expr": bson.M{"$lt": bson.A{"$attempts", "$max_attempts"}},
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"status":      dbmodels.EmailJobStatusRunning,
+				"lease_owner": primitive.NewObjectID().Hex(),
+				"lease_until": now + lease,
+			},
+			"$inc": bson.M{"attempts": 1},
+		}
+
+		after := options.After
+		opt := options.FindOneAndUpdateOptions{
+			Sort:           bson.M{"next_run_at": 1},
+			ReturnDocument: &after,
+		}
+
+		return col.FindOneAndUpdate(ctx, filter, update, &opt).Decode(&v)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoDocuments(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &dbmodels.EmailJob{
+		ID:          objectIDToUID(v.ID),
+		Kind:        v.Kind,
+		OrgEmail:    v.OrgEmail,
+		Payload:     v.Payload,
+		Status:      v.Status,
+		Attempts:    v.Attempts,
+		MaxAttempts: v.MaxAttempts,
+		NextRunAt:   v.NextRunAt,
+		LastError:   v.LastError,
+		LeaseOwner:  v.LeaseOwner,
+		LeaseUntil:  v.LeaseUntil,
+	}, nil
+}
+
+func (c *client) FinishEmailJob(job *dbmodels.EmailJob) error {
+	return c.updateEmailJob(job, bson.M{
+		"status":     dbmodels.EmailJobStatusDone,
+		"last_error": "",
+		// the payload may include sensitive data, such as password
+		"payload": nil,
+	})
+}
+
+func (c *client) RetryEmailJob(job *dbmodels.EmailJob, nextRunAt int64, reason string) error {
+	return c.updateEmailJob(job, bson.M{
+		"status":      dbmodels.EmailJobStatusPending,
+		"next_run_at": nextRunAt,
+		"last_error":  reason,
+	})
+}
+
+func (c *client) KillEmailJob(job *dbmodels.EmailJob, reason string) error {
+	return c.updateEmailJob(job, bson.M{
+		"status":     dbmodels.EmailJobStatusDead,
+		"last_error": reason,
+		// the dead job is kept for troubleshooting, but not the payload of it.
+		"payload": nil,
+	})
+}
+
+func (c *client) CountEmailJobs(status string) (int, error) {
+	n := int64(0)
+
+	f := func(ctx context.Context) error {
+		col := c.collection(emailOutboxCollection)
+
+		v, err := col.CountDocuments(ctx, bson.M{"status": status})
+		if err != nil {
+			return fmt.Errorf("failed to count email jobs: %s", err.Error())
+		}
+		n = v
+		return nil
+	}
+
+	err := withContext(f)
+	return int(n), err
+}
+
+// updateEmailJob updates the job only if it is still leased to the one who claimed it.
+func (c *client) updateEmailJob(job *dbmodels.EmailJob, v bson.M) error {
+	oid, err := toObjectID(job.ID)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		col := c.collection(emailOutboxCollection)
+
+		filter := bson.M{
+			"_id":         oid,
+			"status":      dbmodels.EmailJobStatusRunning,
+			"lease_owner": job.LeaseOwner,
+			"lease_until": job.LeaseUntil,
+		}
+
+		r, err := col.UpdateOne(ctx, filter, bson.M{"$set": v})
+		if err != nil {
+			return err
+		}
+
+		if r.MatchedCount == 0 {
+			return errEmailJobLeaseLost()
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
+
+// addEmailJobLease adds the lease to the existing jobs. The running jobs can be
+// claimed again at once, because the workers are stopped during the migration.
+func (c *client) addEmailJobLease() error {
+	f := func(ctx context.Context) error {
+		_, err := c.collection(emailOutboxCollection).UpdateMany(
+			ctx, bson.M{"lease_until": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"lease_owner": "", "lease_until": 0}},
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) dropEmailJobLease() error {
+	f := func(ctx context.Context) error {
+		_, err := c.collection(emailOutboxCollection).UpdateMany(
+			ctx, bson.M{}, bson.M{"$unset": bson.M{"lease_owner": "", "lease_until": ""}},
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+// createEmailOutboxIndex creates the index which the worker uses to claim the due jobs.
+func (c *client) createEmailOutboxIndex() error {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1},
+		},
+		Options: options.Index().SetName(emailOutboxIndex),
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.collection(emailOutboxCollection).Indexes().CreateOne(ctx, index)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) dropEmailOutboxIndex() error {
+	f := func(ctx context.Context) error {
+		_, err := c.collection(emailOutboxCollection).Indexes().DropOne(ctx, emailOutboxIndex)
+		return err
+	}
+
+	return withContext(f)
+}
@@ -0,0 +1,87 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// individualSigningEventCollection only be inserted, so it is the audit trail of individual signing.
+const individualSigningEventCollection = "individual_signing_events"
+
+type individualSigningEventDoc struct {
+	CLAOrgID string `bson:"cla_org_id"`
+	Email    string `bson:"email"`
+	Signer   string `bson:"signer"`
+	Action   string `bson:"action"`
+	Reason   string `bson:"reason,omitempty"`
+	Time     int64  `bson:"time"`
+}
+
+func (c *client) addIndividualSigningEvent(claOrgID, email, signer, action, reason string, ctx context.Context) error {
+	doc := individualSigningEventDoc{
+		CLAOrgID: claOrgID,
+		Email:    email,
+		Signer:   signer,
+		Action:   action,
+		Reason:   reason,
+		Time:     util.Now(),
+	}
+
+	col := c.collection(individualSigningEventCollection)
+	if _, err := col.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to add signing event: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *client) hasIndividualSigningEvent(claOrgID, email string, ctx context.Context) (bool, error) {
+	col := c.collection(individualSigningEventCollection)
+
+	n, err := col.CountDocuments(ctx, bson.M{"cla_org_id": claOrgID, "email": email})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (c *client) ListIndividualSigningEvents(signer string) ([]dbmodels.IndividualSigningEvent, error) {
+	var v []individualSigningEventDoc
+
+	f := func(ctx context.Context) error {
+		col := c.collection(individualSigningEventCollection)
+
+		opt := options.FindOptions{
+			Sort: bson.M{"time": 1},
+		}
+
+		cursor, err := col.Find(ctx, bson.M{"signer": signer}, &opt)
+		if err != nil {
+			return err
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	r := make([]dbmodels.IndividualSigningEvent, 0, len(v))
+	for _, item := range v {
+		r = append(r, dbmodels.IndividualSigningEvent{
+			CLAOrgID: item.CLAOrgID,
+			Email:    item.Email,
+			Signer:   item.Signer,
+			Action:   item.Action,
+			Reason:   item.Reason,
+			Time:     item.Time,
+		})
+	}
+	return r, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -12,22 +13,31 @@ import (
 	"github.com/opensourceways/app-cla-server/util"
 )
 
-type individualSigningDoc struct {
-	Name        string                   `bson:"name" json:"name" required:"true"`
-	Email       string                   `bson:"email" json:"email" required:"true"`
-	Enabled     bool                     `bson:"enabled" json:"enabled"`
-	Date        string                   `bson:"date" json:"date" required:"true"`
-	SigningInfo dbmodels.TypeSigningInfo `bson:"info" json:"info,omitempty"`
-}
+const individualSigningCollection = "individual_signings"
 
-func individualSigningField(key string) string {
-	return fmt.Sprintf("%s.%s", fieldIndividuals, key)
+type individualSigningDoc struct {
+	CLAOrgID    primitive.ObjectID       `bson:"cla_org_id"`
+	CorpID      string                   `bson:"corp_id"`
+	Name        string                   `bson:"name"`
+	Email       string                   `bson:"email"`
+	Enabled     bool                     `bson:"enabled"`
+	Date        string                   `bson:"date"`
+	SigningInfo dbmodels.TypeSigningInfo `bson:"info,omitempty"`
+	Signer      string                   `bson:"signer,omitempty"`
+	CLAVersion  int                      `bson:"cla_version,omitempty"`
 }
 
 func filterForIndividualSigning(filter bson.M) {
 	filter["apply_to"] = dbmodels.ApplyToIndividual
 	filter["enabled"] = true
-	filter[fieldIndividuals] = bson.M{"$type": "array"}
+}
+
+func filterOfIndividualSigning(claOrgID primitive.ObjectID, email string) bson.M {
+	return bson.M{
+		"cla_org_id":       claOrgID,
+		fieldCorporationID: util.EmailSuffix(email),
+		"email":            email,
+	}
 }
 
 func (c *client) SignAsIndividual(claOrgID, platform, org, repo string, info dbmodels.IndividualSigningInfo) error {
@@ -36,42 +46,62 @@ func (c *client) SignAsIndividual(claOrgID, platform, org, repo string, info dbm
 		return err
 	}
 
-	signing := individualSigningDoc{
+	doc := individualSigningDoc{
+		CLAOrgID:    oid,
+		CorpID:      util.EmailSuffix(info.Email),
 		Email:       info.Email,
 		Name:        info.Name,
 		Enabled:     info.Enabled,
 		Date:        info.Date,
 		SigningInfo: info.Info,
+		Signer:      info.Signer,
 	}
-	body, err := structToMap(signing)
-	if err != nil {
-		return err
-	}
-	addCorporationID(info.Email, body)
 
 	f := func(ctx mongo.SessionContext) error {
-		_, err := c.isIndividualSigned(platform, org, repo, info.Email, false, ctx)
+		binding, signing, err := c.getIndividualSigning(platform, org, repo, info.Email, false, ctx)
 		if err != nil {
 			if !isHasNotSigned(err) {
 				return err
 			}
 		} else {
-			return dbmodels.DBError{
-				ErrCode: util.ErrHasSigned,
-				Err:     fmt.Errorf("he/she has signed"),
+			if !toSigningVersion(binding, signing.CLAVersion).IsOutdated() {
+				return dbmodels.DBError{
+					ErrCode: util.ErrHasSigned,
+					Err:     fmt.Errorf("he/she has signed"),
+				}
+			}
+
+			// re-sign the new version of cla
+			if err := c.pullIndividualSigning(binding.ID, info.Email, ctx); err != nil {
+				return err
 			}
 		}
 
-		col := c.collection(claOrgCollection)
-		r, err := col.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$push": bson.M{fieldIndividuals: bson.M(body)}})
+		v, err := c.getCLAVersion(oid, ctx)
 		if err != nil {
 			return err
 		}
+		doc.CLAVersion = v
+
+		col := c.collection(individualSigningCollection)
+		if _, err := col.InsertOne(ctx, doc); err != nil {
+			if isDuplicateKeyError(err) {
+				return dbmodels.DBError{
+					ErrCode: util.ErrHasSigned,
+					Err:     fmt.Errorf("he/she has signed"),
+				}
+			}
+			return err
+		}
 
-		if r.ModifiedCount == 0 {
-			return fmt.Errorf("impossible")
+		action := dbmodels.SigningEventSign
+		if b, err := c.hasIndividualSigningEvent(claOrgID, info.Email, ctx); err != nil {
+			return err
+		} else if b {
+			action = dbmodels.SigningEventResign
 		}
-		return nil
+
+		return c.addIndividualSigningEvent(claOrgID, info.Email, info.Signer, action, "", ctx)
 	}
 
 	return c.doTransaction(f)
@@ -83,71 +113,163 @@ func (c *client) DeleteIndividualSigning(claOrgID, email string) error {
 		return err
 	}
 
-	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
+	f := func(ctx mongo.SessionContext) error {
+		if err := c.checkIndividualBinding(oid, ctx); err != nil {
+			return err
+		}
 
-		filter := bson.M{"_id": oid}
-		filterForIndividualSigning(filter)
+		col := c.collection(individualSigningCollection)
 
-		update := bson.M{"$pull": bson.M{fieldIndividuals: bson.M{
-			fieldCorporationID: util.EmailSuffix(email),
-			"email":            email,
-		}}}
+		var v individualSigningDoc
+		err := col.FindOneAndDelete(ctx, filterOfIndividualSigning(oid, email)).Decode(&v)
+		if err != nil {
+			if isErrNoDocuments(err) {
+				return nil
+			}
+			return err
+		}
+
+		return c.addIndividualSigningEvent(claOrgID, email, v.Signer, dbmodels.SigningEventDelete, "", ctx)
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) RevokeIndividualSigning(claOrgID string, opt dbmodels.IndividualSigningRevokeOption) error {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx mongo.SessionContext) error {
+		if err := c.checkIndividualBinding(oid, ctx); err != nil {
+			return err
+		}
 
-		r, err := col.UpdateOne(ctx, filter, update)
+		col := c.collection(individualSigningCollection)
+
+		filter := filterOfIndividualSigning(oid, opt.Email)
+		if opt.EmailVerified {
+			filter["$or"] = bson.A{
+				bson.M{"signer": opt.Signer},
+				bson.M{"signer": bson.M{"$in": bson.A{nil, ""}}},
+			}
+		} else {
+			filter["signer"] = opt.Signer
+		}
+
+		r, err := col.DeleteOne(ctx, filter)
 		if err != nil {
 			return err
 		}
 
-		if r.MatchedCount == 0 {
+		if r.DeletedCount == 0 {
 			return dbmodels.DBError{
-				ErrCode: util.ErrInvalidParameter,
-				Err:     fmt.Errorf("can't find the cla"),
+				ErrCode: util.ErrHasNotSigned,
+				Err:     fmt.Errorf("can't find the corresponding signing info"),
 			}
 		}
 
-		return nil
+		return c.addIndividualSigningEvent(
+			claOrgID, opt.Email, opt.Signer, dbmodels.SigningEventRevoke, opt.Reason, ctx,
+		)
 	}
 
-	return withContext(f)
+	return c.doTransaction(f)
 }
 
-func (c *client) UpdateIndividualSigning(claOrgID, email string, enabled bool) error {
-	oid, err := toObjectID(claOrgID)
+// checkIndividualBinding checks whether the binding exists and is applied to individual.
+func (c *client) checkIndividualBinding(claOrgID primitive.ObjectID, ctx context.Context) error {
+	filter := bson.M{"_id": claOrgID}
+	filterForIndividualSigning(filter)
+
+	b, err := c.isBindingExist(filter, ctx)
 	if err != nil {
 		return err
 	}
 
+	if !b {
+		return dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't find the cla"),
+		}
+	}
+	return nil
+}
+
+func (c *client) ListIndividualSigningOfSigner(signer string) (map[string][]dbmodels.IndividualSigningBasicInfo, error) {
+	var v []individualSigningDoc
+	var bindings bson.A
+
 	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
+		col := c.collection(individualSigningCollection)
+
+		cursor, err := col.Find(ctx, bson.M{"signer": signer})
+		if err != nil {
+			return fmt.Errorf("error find individual signings: %v", err)
+		}
+
+		if err := cursor.All(ctx, &v); err != nil {
+			return err
+		}
+
+		if len(v) == 0 {
+			return nil
+		}
 
-		filter := bson.M{"_id": oid}
+		ids := make(bson.A, 0, len(v))
+		for i := range v {
+			ids = append(ids, v[i].CLAOrgID)
+		}
+
+		filter := bson.M{"_id": bson.M{"$in": ids}}
 		filterForIndividualSigning(filter)
 
-		update := bson.M{"$set": bson.M{fmt.Sprintf("%s.$[ms].enabled", fieldIndividuals): enabled}}
-
-		updateOpt := options.UpdateOptions{
-			ArrayFilters: &options.ArrayFilters{
-				Filters: bson.A{
-					bson.M{
-						"ms.corp_id": util.EmailSuffix(email),
-						"ms.email":   email,
-						"ms.enabled": !enabled,
-					},
-				},
-			},
+		bindings, err = c.listBindingIDs(filter, ctx)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[primitive.ObjectID]bool, len(bindings))
+	for _, id := range bindings {
+		enabled[id.(primitive.ObjectID)] = true
+	}
+
+	r := map[string][]dbmodels.IndividualSigningBasicInfo{}
+	for i := range v {
+		item := &v[i]
+		if !enabled[item.CLAOrgID] {
+			continue
 		}
 
-		r, err := col.UpdateOne(ctx, filter, update, &updateOpt)
-		if err != nil {
+		k := objectIDToUID(item.CLAOrgID)
+		r[k] = append(r[k], toDBModelIndividualSigningBasicInfo(item))
+	}
+	return r, nil
+}
+
+func (c *client) UpdateIndividualSigning(claOrgID, email string, enabled bool) error {
+	oid, err := toObjectID(claOrgID)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		if err := c.checkIndividualBinding(oid, ctx); err != nil {
 			return err
 		}
 
-		if r.MatchedCount == 0 {
-			return dbmodels.DBError{
-				ErrCode: util.ErrInvalidParameter,
-				Err:     fmt.Errorf("can't find the cla"),
-			}
+		col := c.collection(individualSigningCollection)
+
+		filter := filterOfIndividualSigning(oid, email)
+		filter["enabled"] = !enabled
+
+		r, err := col.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"enabled": enabled}})
+		if err != nil {
+			return err
 		}
 
 		if r.ModifiedCount == 0 {
@@ -177,117 +299,220 @@ func (c *client) IsIndividualSigned(platform, orgID, repoID, email string) (bool
 }
 
 func (c *client) isIndividualSigned(platform, orgID, repoID, email string, orgCared bool, ctx context.Context) (bool, error) {
+	_, signing, err := c.getIndividualSigning(platform, orgID, repoID, email, orgCared, ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return signing.Enabled, nil
+}
+
+func (c *client) GetIndividualSigningVersion(platform, orgID, repoID, email string) (dbmodels.SigningVersion, error) {
+	var r dbmodels.SigningVersion
+
+	f := func(ctx context.Context) error {
+		binding, signing, err := c.getIndividualSigning(platform, orgID, repoID, email, true, ctx)
+		if err != nil {
+			return err
+		}
+
+		r = toSigningVersion(binding, signing.CLAVersion)
+		return nil
+	}
+
+	err := withContext(f)
+	return r, err
+}
+
+func toSigningVersion(claOrg *CLAOrg, version int) dbmodels.SigningVersion {
+	return dbmodels.SigningVersion{
+		CLAOrgID:       objectIDToUID(claOrg.ID),
+		Version:        normalizeCLAVersion(version),
+		CLAVersion:     normalizeCLAVersion(claOrg.CLAVersion),
+		ResignRequired: claOrg.ResignRequired,
+		ResignDeadline: claOrg.ResignDeadline,
+	}
+}
+
+func (c *client) pullIndividualSigning(claOrgID primitive.ObjectID, email string, ctx context.Context) error {
+	col := c.collection(individualSigningCollection)
+
+	_, err := col.DeleteOne(ctx, filterOfIndividualSigning(claOrgID, email))
+	return err
+}
+
+func (c *client) getIndividualSigning(platform, orgID, repoID, email string, orgCared bool, ctx context.Context) (*CLAOrg, *individualSigningDoc, error) {
 	filterOfSigning := bson.M{
-		fieldIndividuals: bson.M{"$filter": bson.M{
-			"input": fmt.Sprintf("$%s", fieldIndividuals),
-			"cond": bson.M{"$and": bson.A{
-				bson.M{"$eq": bson.A{"$$this.corp_id", util.EmailSuffix(email)}},
-				bson.M{"$eq": bson.A{"$$this.email", email}},
-			}},
-		}},
+		fieldCorporationID: util.EmailSuffix(email),
+		"email":            email,
 	}
 
-	project := bson.M{
-		individualSigningField("enabled"): 1,
+	var v individualSigningDoc
+
+	binding, err := c.getSigningDetail(platform, orgID, repoID, dbmodels.ApplyToIndividual, orgCared, filterOfSigning, &v, ctx)
+	if err != nil {
+		return nil, nil, err
 	}
+	return binding, &v, nil
+}
 
-	claOrg, err := c.getSigningDetail(platform, orgID, repoID, dbmodels.ApplyToIndividual, orgCared, filterOfSigning, project, ctx)
+func (c *client) ListIndividualSigning(opt dbmodels.IndividualSigningListOption) ([]dbmodels.IndividualSigningListItem, int, error) {
+	filter, err := individualSigningListFilter(opt)
 	if err != nil {
-		return false, err
+		return nil, 0, err
 	}
 
-	return claOrg.Individuals[0].Enabled, nil
-}
+	filterOfSigning := bson.M{}
+	filterOfEmployees(filterOfSigning, opt)
+	signingFilter(filterOfSigning, opt.Filter, "enabled", "email")
 
-func (c *client) ListIndividualSigning(opt dbmodels.IndividualSigningListOption) (map[string][]dbmodels.IndividualSigningBasicInfo, error) {
-	info := struct {
-		Platform    string `json:"platform" required:"true"`
-		OrgID       string `json:"org_id" required:"true"`
-		RepoID      string `json:"repo_id,omitempty"`
-		CLALanguage string `json:"cla_language,omitempty"`
-	}{
-		Platform:    opt.Platform,
-		OrgID:       opt.OrgID,
-		RepoID:      opt.RepoID,
-		CLALanguage: opt.CLALanguage,
+	sortField := "date"
+	switch opt.Page.SortBy {
+	case dbmodels.SortByName:
+		sortField = "name"
+	case dbmodels.SortByEmail:
+		sortField = "email"
 	}
 
-	body, err := structToMap(info)
+	var v []individualSigningDoc
+	total := 0
+
+	f := func(ctx context.Context) error {
+		ids, err := c.listBindingIDs(filter, ctx)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		filterOfSigning["cla_org_id"] = bson.M{"$in": ids}
+
+		n, err := c.listPage(
+			individualSigningCollection, filterOfSigning,
+			opt.Page,
+			bson.D{
+				{Key: sortField, Value: sortDirection(opt.Page)},
+				{Key: "email", Value: 1},
+			},
+			bson.M{
+				"cla_org_id":  1,
+				"email":       1,
+				"name":        1,
+				"enabled":     1,
+				"date":        1,
+				"cla_version": 1,
+			},
+			&v, ctx,
+		)
+		total = n
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, err
+	}
+
+	r := make([]dbmodels.IndividualSigningListItem, 0, len(v))
+	for i := range v {
+		r = append(r, dbmodels.IndividualSigningListItem{
+			CLAOrgID:                   objectIDToUID(v[i].CLAOrgID),
+			IndividualSigningBasicInfo: toDBModelIndividualSigningBasicInfo(&v[i]),
+		})
+	}
+	return r, total, nil
+}
+
+func (c *client) ListIndividualSigningInfo(opt dbmodels.IndividualSigningListOption) (map[string][]dbmodels.IndividualSigningInfo, error) {
+	filter, err := individualSigningListFilter(opt)
 	if err != nil {
 		return nil, err
 	}
-	filter := bson.M(body)
-	filterForIndividualSigning(filter)
 
-	var v []CLAOrg
+	filterOfSigning := bson.M{}
+	filterOfEmployees(filterOfSigning, opt)
+
+	var v []individualSigningDoc
 
 	f := func(ctx context.Context) error {
-		col := c.collection(claOrgCollection)
-
-		pipeline := bson.A{bson.M{"$match": filter}}
-
-		if opt.CorporationEmail != "" {
-			pipeline = append(
-				pipeline,
-				bson.M{"$project": bson.M{
-					fieldIndividuals: bson.M{"$filter": bson.M{
-						"input": fmt.Sprintf("$%s", fieldIndividuals),
-						"cond":  bson.M{"$eq": bson.A{"$$this.corp_id", util.EmailSuffix(opt.CorporationEmail)}},
-					}}},
-				},
-			)
+		ids, err := c.listBindingIDs(filter, ctx)
+		if err != nil || len(ids) == 0 {
+			return err
 		}
+		filterOfSigning["cla_org_id"] = bson.M{"$in": ids}
 
-		pipeline = append(
-			pipeline,
-			bson.M{"$project": bson.M{
-				individualSigningField("email"):   1,
-				individualSigningField("name"):    1,
-				individualSigningField("enabled"): 1,
-				individualSigningField("date"):    1,
-			}},
-		)
+		col := c.collection(individualSigningCollection)
 
-		cursor, err := col.Aggregate(ctx, pipeline)
+		cursor, err := col.Find(ctx, filterOfSigning, &options.FindOptions{
+			Projection: bson.M{"signer": 0},
+		})
 		if err != nil {
-			return fmt.Errorf("error find bindings: %v", err)
+			return fmt.Errorf("error find individual signings: %v", err)
 		}
 
 		err = cursor.All(ctx, &v)
 		if err != nil {
-			return fmt.Errorf("error decoding to bson struct of employee signing: %v", err)
+			return fmt.Errorf("error decoding to bson struct of individual signing: %v", err)
 		}
 		return nil
 	}
 
-	err = withContext(f)
-	if err != nil {
+	if err := withContext(f); err != nil {
 		return nil, err
 	}
 
-	r := map[string][]dbmodels.IndividualSigningBasicInfo{}
-
-	for i := 0; i < len(v); i++ {
-		rs := v[i].Individuals
-		if rs == nil || len(rs) == 0 {
-			continue
-		}
+	r := map[string][]dbmodels.IndividualSigningInfo{}
+	for i := range v {
+		item := &v[i]
 
-		es := make([]dbmodels.IndividualSigningBasicInfo, 0, len(rs))
-		for _, item := range rs {
-			es = append(es, toDBModelIndividualSigningBasicInfo(item))
-		}
-		r[objectIDToUID(v[i].ID)] = es
+		k := objectIDToUID(item.CLAOrgID)
+		r[k] = append(r[k], dbmodels.IndividualSigningInfo{
+			IndividualSigningBasicInfo: toDBModelIndividualSigningBasicInfo(item),
+			Info:                       item.SigningInfo,
+		})
 	}
 
 	return r, nil
 }
 
-func toDBModelIndividualSigningBasicInfo(item individualSigningDoc) dbmodels.IndividualSigningBasicInfo {
+func individualSigningListFilter(opt dbmodels.IndividualSigningListOption) (bson.M, error) {
+	info := struct {
+		Platform    string `json:"platform" required:"true"`
+		OrgID       string `json:"org_id" required:"true"`
+		RepoID      string `json:"repo_id,omitempty"`
+		CLALanguage string `json:"cla_language,omitempty"`
+	}{
+		Platform:    opt.Platform,
+		OrgID:       opt.OrgID,
+		RepoID:      opt.RepoID,
+		CLALanguage: opt.CLALanguage,
+	}
+
+	body, err := structToMap(info)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M(body)
+	filterForIndividualSigning(filter)
+
+	return filter, nil
+}
+
+func toDBModelIndividualSigningBasicInfo(item *individualSigningDoc) dbmodels.IndividualSigningBasicInfo {
 	return dbmodels.IndividualSigningBasicInfo{
-		Email:   item.Email,
-		Name:    item.Name,
-		Enabled: item.Enabled,
-		Date:    item.Date,
+		Email:      item.Email,
+		Name:       item.Name,
+		Enabled:    item.Enabled,
+		Date:       item.Date,
+		CLAVersion: normalizeCLAVersion(item.CLAVersion),
+	}
+}
+
+// filterOfEmployees matches the employees of corporation if it is set in the option.
+func filterOfEmployees(filter bson.M, opt dbmodels.IndividualSigningListOption) {
+	if opt.CorporationEmail == "" {
+		return
+	}
+
+	ids := bson.A{util.EmailSuffix(opt.CorporationEmail)}
+	for _, d := range opt.CorporationDomains {
+		ids = append(ids, d)
 	}
+	filter[fieldCorporationID] = bson.M{"$in": ids}
 }
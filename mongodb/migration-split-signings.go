@@ -0,0 +1,287 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// legacyCLAOrgDoc is the binding which embeds the signings and managers.
+type legacyCLAOrgDoc struct {
+	ID primitive.ObjectID `bson:"_id"`
+
+	Individuals         []individualSigningDoc  `bson:"individuals"`
+	Corporations        []legacyCorpSigningDoc  `bson:"corporations"`
+	CorporationManagers []corporationManagerDoc `bson:"corp_managers"`
+}
+
+type legacyCorpSigningDoc struct {
+	corporationSigningDoc `bson:",inline"`
+
+	PDF []byte `bson:"pdf"`
+}
+
+// createSigningIndexes creates the indexes of the split collections. It also
+// creates the collections which can't be created implicitly in transaction.
+func (c *client) createSigningIndexes() error {
+	unique := options.Index().SetUnique(true)
+
+	indexes := map[string][]mongo.IndexModel{
+		individualSigningCollection: {
+			{Keys: bson.D{{Key: "cla_org_id", Value: 1}, {Key: "email", Value: 1}}, Options: unique},
+			{Keys: bson.D{{Key: "cla_org_id", Value: 1}, {Key: fieldCorporationID, Value: 1}}},
+			{Keys: bson.D{{Key: "signer", Value: 1}}},
+		},
+		corpSigningCollection: {
+			{Keys: bson.D{{Key: "cla_org_id", Value: 1}, {Key: fieldCorporationID, Value: 1}}, Options: unique},
+		},
+		corpPDFCollection: {
+			{Keys: bson.D{{Key: "cla_org_id", Value: 1}, {Key: fieldCorporationID, Value: 1}}, Options: unique},
+		},
+		corpManagerCollection: {
+			{
+				Keys: bson.D{
+					{Key: "cla_org_id", Value: 1}, {Key: "email", Value: 1}, {Key: "role", Value: 1},
+				},
+				Options: unique,
+			},
+			{Keys: bson.D{{Key: fieldCorporationID, Value: 1}, {Key: "email", Value: 1}}},
+		},
+	}
+
+	f := func(ctx context.Context) error {
+		for name, items := range indexes {
+			if _, err := c.collection(name).Indexes().CreateMany(ctx, items); err != nil {
+				return fmt.Errorf("%s: %s", name, err.Error())
+			}
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
+
+// splitCLAOrgDocs moves the signings and managers embedded in the bindings to
+// their own collections. It is safe to run it again if it is interrupted.
+func (c *client) splitCLAOrgDocs() error {
+	if err := c.createSigningIndexes(); err != nil {
+		return fmt.Errorf("failed to create indexes: %s", err.Error())
+	}
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{fieldIndividuals: bson.M{"$exists": true}},
+		bson.M{fieldEmployees: bson.M{"$exists": true}},
+		bson.M{fieldCorporations: bson.M{"$exists": true}},
+		bson.M{fieldCorpoManagers: bson.M{"$exists": true}},
+	}}
+
+	var ids bson.A
+	err := withContext(func(ctx context.Context) error {
+		v, err := c.listBindingIDs(filter, ctx)
+		ids = v
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		oid := id.(primitive.ObjectID)
+
+		if err := withContext(func(ctx context.Context) error {
+			return c.splitCLAOrgDoc(oid, ctx)
+		}); err != nil {
+			return fmt.Errorf("failed to split binding(%s): %s", objectIDToUID(oid), err.Error())
+		}
+	}
+	return nil
+}
+
+func (c *client) splitCLAOrgDoc(claOrgID primitive.ObjectID, ctx context.Context) error {
+	col := c.collection(claOrgCollection)
+
+	var doc legacyCLAOrgDoc
+	if err := col.FindOne(ctx, bson.M{"_id": claOrgID}).Decode(&doc); err != nil {
+		return err
+	}
+
+	upsert := func(filter bson.M, item interface{}) mongo.WriteModel {
+		return mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(item).SetUpsert(true)
+	}
+
+	individuals := make([]mongo.WriteModel, 0, len(doc.Individuals))
+	for i := range doc.Individuals {
+		item := &doc.Individuals[i]
+		item.CLAOrgID = claOrgID
+		item.CorpID = util.EmailSuffix(item.Email)
+
+		individuals = append(individuals, upsert(filterOfIndividualSigning(claOrgID, item.Email), item))
+	}
+
+	corps := make([]mongo.WriteModel, 0, len(doc.Corporations))
+	pdfs := make([]mongo.WriteModel, 0, len(doc.Corporations))
+	for i := range doc.Corporations {
+		item := &doc.Corporations[i]
+		item.CLAOrgID = claOrgID
+		item.CorpID = util.EmailSuffix(item.AdminEmail)
+
+		filter := filterOfCorpSigning(claOrgID, item.AdminEmail)
+		corps = append(corps, upsert(filter, item.corporationSigningDoc))
+
+		if item.PDFUploaded {
+			pdfs = append(pdfs, upsert(filter, corporationPDFDoc{
+				CLAOrgID: claOrgID,
+				CorpID:   item.CorpID,
+				PDF:      item.PDF,
+			}))
+		}
+	}
+
+	managers := make([]mongo.WriteModel, 0, len(doc.CorporationManagers))
+	for i := range doc.CorporationManagers {
+		item := &doc.CorporationManagers[i]
+		item.CLAOrgID = claOrgID
+		item.CorpID = util.EmailSuffix(item.Email)
+
+		filter := filterOfCorpManager(claOrgID, item.Email, item.Role)
+		filter["email"] = item.Email
+		managers = append(managers, upsert(filter, item))
+	}
+
+	writes := map[string][]mongo.WriteModel{
+		individualSigningCollection: individuals,
+		corpSigningCollection:       corps,
+		corpPDFCollection:           pdfs,
+		corpManagerCollection:       managers,
+	}
+	for name, models := range writes {
+		if len(models) == 0 {
+			continue
+		}
+
+		if _, err := c.collection(name).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("failed to write %s: %s", name, err.Error())
+		}
+	}
+
+	_, err := col.UpdateOne(ctx, bson.M{"_id": claOrgID}, bson.M{"$unset": bson.M{
+		fieldIndividuals:   "",
+		fieldEmployees:     "",
+		fieldCorporations:  "",
+		fieldCorpoManagers: "",
+	}})
+	return err
+}
+
+// mergeCLAOrgDocs moves the signings and managers back into the bindings.
+func (c *client) mergeCLAOrgDocs() error {
+	var ids []interface{}
+	err := withContext(func(ctx context.Context) error {
+		v, err := c.collection(claOrgCollection).Distinct(ctx, "_id", bson.M{})
+		ids = v
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		oid, ok := id.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+
+		if err := withContext(func(ctx context.Context) error {
+			return c.mergeCLAOrgDoc(oid, ctx)
+		}); err != nil {
+			return fmt.Errorf("failed to merge binding(%s): %s", objectIDToUID(oid), err.Error())
+		}
+	}
+
+	for _, name := range []string{
+		individualSigningCollection, corpSigningCollection,
+		corpPDFCollection, corpManagerCollection,
+	} {
+		if err := withContext(c.collection(name).Drop); err != nil {
+			return fmt.Errorf("failed to drop %s: %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+func (c *client) mergeCLAOrgDoc(claOrgID primitive.ObjectID, ctx context.Context) error {
+	filter := bson.M{"cla_org_id": claOrgID}
+
+	list := func(name string) ([]bson.M, error) {
+		cursor, err := c.collection(name).Find(
+			ctx, filter, &options.FindOptions{Projection: bson.M{"_id": 0, "cla_org_id": 0}},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var v []bson.M
+		if err := cursor.All(ctx, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+
+	individuals, err := list(individualSigningCollection)
+	if err != nil {
+		return err
+	}
+
+	corps, err := list(corpSigningCollection)
+	if err != nil {
+		return err
+	}
+
+	pdfs, err := list(corpPDFCollection)
+	if err != nil {
+		return err
+	}
+
+	managers, err := list(corpManagerCollection)
+	if err != nil {
+		return err
+	}
+
+	if len(individuals)+len(corps)+len(managers) == 0 {
+		return nil
+	}
+
+	pdfOfCorp := make(map[interface{}]interface{}, len(pdfs))
+	for _, item := range pdfs {
+		pdfOfCorp[item[fieldCorporationID]] = item["pdf"]
+	}
+	for _, item := range corps {
+		if v, ok := pdfOfCorp[item[fieldCorporationID]]; ok {
+			item["pdf"] = v
+		}
+	}
+
+	if _, err := c.collection(claOrgCollection).UpdateOne(ctx, bson.M{"_id": claOrgID}, bson.M{"$set": bson.M{
+		fieldIndividuals:   individuals,
+		fieldCorporations:  corps,
+		fieldCorpoManagers: managers,
+	}}); err != nil {
+		return err
+	}
+
+	for _, name := range []string{
+		individualSigningCollection, corpSigningCollection,
+		corpPDFCollection, corpManagerCollection,
+	} {
+		if _, err := c.collection(name).DeleteMany(ctx, filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const migrationCollection = "schema_migrations"
+
+type migrationDoc struct {
+	Version   int    `bson:"_id"`
+	Name      string `bson:"name"`
+	AppliedAt int64  `bson:"applied_at"`
+}
+
+// migration upgrades the schema of database from version-1 to version,
+// and down reverts it. down is nil if the migration is irreversible.
+type migration struct {
+	version int
+	name    string
+	up      func(*client) error
+	down    func(*client) error
+}
+
+// migrations must be sorted by version which starts from 1 and increases by 1.
+// Never change a migration which has been released, add a new one instead.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "move signings and managers out of bindings",
+		up:      (*client).splitCLAOrgDocs,
+		down:    (*client).mergeCLAOrgDocs,
+	},
+	{
+		version: 2,
+		name:    "create index of audit logs",
+		up:      (*client).createAuditLogIndex,
+		down:    (*client).dropAuditLogIndex,
+	},
+	{
+		version: 3,
+		name:    "add status of corporation signings",
+		up:      (*client).addCorpSigningStatus,
+		down:    (*client).dropCorpSigningStatus,
+	},
+	{
+		version: 4,
+		name:    "create index of corporation domains",
+		up:      (*client).createCorpDomainIndex,
+		down:    (*client).dropCorpDomainIndex,
+	},
+	{
+		version: 5,
+		name:    "create index of email outbox",
+		up:      (*client).createEmailOutboxIndex,
+		down:    (*client).dropEmailOutboxIndex,
+	},
+	{
+		version: 6,
+		name:    "add lease of email jobs",
+		up:      (*client).addEmailJobLease,
+		down:    (*client).dropEmailJobLease,
+	},
+}
+
+func latestSchemaVersion() int {
+	return len(migrations)
+}
+
+// SchemaVersion returns the version of migration which was applied latest.
+func (c *client) SchemaVersion() (int, error) {
+	var v migrationDoc
+
+	f := func(ctx context.Context) error {
+		col := c.collection(migrationCollection)
+
+		return col.FindOne(
+			ctx, bson.M{}, options.FindOne().SetSort(bson.M{"_id": -1}),
+		).Decode(&v)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoDocuments(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return v.Version, nil
+}
+
+// CheckSchemaVersion returns error if the database has not been migrated
+// to the version which this server requires.
+func (c *client) CheckSchemaVersion() error {
+	v, err := c.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %s", err.Error())
+	}
+
+	if n := latestSchemaVersion(); v != n {
+		return fmt.Errorf(
+			"the schema version of database is %d, but %d is required. Run the migrate command first",
+			v, n,
+		)
+	}
+	return nil
+}
+
+// Migrate applies or reverts the migrations until the schema is at the target version.
+// It migrates to the latest version if target is negative, and returns the version at which
+// the schema is after the migration.
+func (c *client) Migrate(target int) (int, error) {
+	latest := latestSchemaVersion()
+	if target < 0 {
+		target = latest
+	}
+	if target > latest {
+		return 0, fmt.Errorf("unknown schema version: %d, the latest one is %d", target, latest)
+	}
+
+	current, err := c.SchemaVersion()
+	if err != nil {
+		return 0, err
+	}
+	if current > latest {
+		return current, fmt.Errorf("the schema version of database is %d which is newer than this server", current)
+	}
+
+	for current < target {
+		m := migrations[current]
+
+		if err := m.up(c); err != nil {
+			return current, fmt.Errorf("failed to apply migration %d(%s): %s", m.version, m.name, err.Error())
+		}
+
+		if err := c.saveMigration(m); err != nil {
+			return current, err
+		}
+		current = m.version
+	}
+
+	for current > target {
+		m := migrations[current-1]
+
+		if m.down == nil {
+			return current, fmt.Errorf("migration %d(%s) is irreversible", m.version, m.name)
+		}
+
+		if err := m.down(c); err != nil {
+			return current, fmt.Errorf("failed to revert migration %d(%s): %s", m.version, m.name, err.Error())
+		}
+
+		if err := c.deleteMigration(m.version); err != nil {
+			return current, err
+		}
+		current = m.version - 1
+	}
+
+	return current, nil
+}
+
+func (c *client) saveMigration(m migration) error {
+	doc := migrationDoc{
+		Version:   m.version,
+		Name:      m.name,
+		AppliedAt: util.Now(),
+	}
+
+	f := func(ctx context.Context) error {
+		col := c.collection(migrationCollection)
+
+		_, err := col.ReplaceOne(
+			ctx, bson.M{"_id": m.version}, doc, options.Replace().SetUpsert(true),
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return fmt.Errorf("failed to record migration %d: %s", m.version, err.Error())
+	}
+	return nil
+}
+
+func (c *client) deleteMigration(version int) error {
+	f := func(ctx context.Context) error {
+		col := c.collection(migrationCollection)
+
+		_, err := col.DeleteOne(ctx, bson.M{"_id": version})
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return fmt.Errorf("failed to delete the record of migration %d: %s", version, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/dbmodels/dbtest"
+)
+
+// TestContract runs only if CLA_TEST_MONGODB_CONN is set. The mongodb must be
+// a replica set to support transaction, and the database of CLA_TEST_MONGODB_DB
+// (cla_test by default) is dropped before each case.
+func TestContract(t *testing.T) {
+	conn := os.Getenv("CLA_TEST_MONGODB_CONN")
+	if conn == "" {
+		t.Skip("CLA_TEST_MONGODB_CONN is not set")
+	}
+
+	name := os.Getenv("CLA_TEST_MONGODB_DB")
+	if name == "" {
+		name = "cla_test"
+	}
+
+	var clients []*client
+	defer func() {
+		for _, c := range clients {
+			c.Close()
+		}
+	}()
+
+	dbtest.Run(t, func(t *testing.T) dbmodels.IDB {
+		c, err := RegisterDatabase(conn, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients = append(clients, c)
+
+		if err := withContext(c.db.Drop); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Migrate(-1); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	})
+}
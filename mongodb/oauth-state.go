@@ -0,0 +1,83 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const oauthStateCollection = "oauth_states"
+
+type oauthStateDoc struct {
+	State     string `bson:"_id"`
+	Purpose   string `bson:"purpose"`
+	Verifier  string `bson:"verifier"`
+	Binding   string `bson:"binding"`
+	ReturnURL string `bson:"return_url"`
+	Expiry    int64  `bson:"expiry"`
+}
+
+func (c *client) AddOAuthState(opt dbmodels.OAuthState) error {
+	doc := oauthStateDoc{
+		State:     opt.State,
+		Purpose:   opt.Purpose,
+		Verifier:  opt.Verifier,
+		Binding:   opt.Binding,
+		ReturnURL: opt.ReturnURL,
+		Expiry:    opt.Expiry,
+	}
+
+	f := func(ctx context.Context) error {
+		col := c.collection(oauthStateCollection)
+
+		// delete the expired states which were never used
+		col.DeleteMany(ctx, bson.M{"expiry": bson.M{"$lt": util.Now()}})
+
+		if _, err := col.InsertOne(ctx, doc); err != nil {
+			return fmt.Errorf("failed to add oauth state: %s", err.Error())
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
+
+func (c *client) UseOAuthState(state string) (dbmodels.OAuthState, error) {
+	var v oauthStateDoc
+
+	f := func(ctx context.Context) error {
+		col := c.collection(oauthStateCollection)
+
+		return col.FindOneAndDelete(ctx, bson.M{"_id": state}).Decode(&v)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoDocuments(err) {
+			return dbmodels.OAuthState{}, dbmodels.DBError{
+				ErrCode: util.ErrInvalidOAuthState,
+				Err:     fmt.Errorf("unknown oauth state"),
+			}
+		}
+		return dbmodels.OAuthState{}, err
+	}
+
+	if v.Expiry < util.Now() {
+		return dbmodels.OAuthState{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidOAuthState,
+			Err:     fmt.Errorf("oauth state is expired"),
+		}
+	}
+
+	return dbmodels.OAuthState{
+		State:     v.State,
+		Purpose:   v.Purpose,
+		Verifier:  v.Verifier,
+		Binding:   v.Binding,
+		ReturnURL: v.ReturnURL,
+		Expiry:    v.Expiry,
+	}, nil
+}
@@ -21,6 +21,9 @@ type OrgEmail struct {
 	Email    string             `bson:"email"`
 	Platform string             `bson:"platform"`
 	Token    []byte             `bson:"token"`
+
+	KeyID        string `bson:"key_id"`
+	EncryptedKey []byte `bson:"encrypted_key"`
 }
 
 func (c *client) CreateOrgEmail(opt dbmodels.OrgEmailCreateInfo) error {
@@ -29,6 +32,8 @@ func (c *client) CreateOrgEmail(opt dbmodels.OrgEmailCreateInfo) error {
 		return fmt.Errorf("Failed to create org email info: build body err:%v", err)
 	}
 	body["token"] = opt.Token
+	body["key_id"] = opt.KeyID
+	body["encrypted_key"] = opt.EncryptedKey
 
 	f := func(ctx context.Context) error {
 		col := c.collection(orgEmailCollection)
@@ -87,9 +92,67 @@ func (c *client) GetOrgEmailInfo(email string) (dbmodels.OrgEmailCreateInfo, err
 	return toDBModelOrgEmail(v), nil
 }
 
+func (c *client) ListOrgEmails() ([]dbmodels.OrgEmailCreateInfo, error) {
+	var v []OrgEmail
+
+	f := func(ctx context.Context) error {
+		col := c.collection(orgEmailCollection)
+
+		cursor, err := col.Find(ctx, bson.M{})
+		if err != nil {
+			return fmt.Errorf("error find org emails: %v", err)
+		}
+
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	r := make([]dbmodels.OrgEmailCreateInfo, 0, len(v))
+	for _, item := range v {
+		r = append(r, toDBModelOrgEmail(item))
+	}
+	return r, nil
+}
+
+func (c *client) UpdateOrgEmailToken(email, oldKeyID string, info dbmodels.OrgEmailCreateInfo) error {
+	f := func(ctx context.Context) error {
+		col := c.collection(orgEmailCollection)
+
+		filter := bson.M{"email": email, "key_id": oldKeyID}
+		if oldKeyID == "" {
+			// the token of early versions has no key_id
+			filter["key_id"] = bson.M{"$in": bson.A{"", nil}}
+		}
+
+		update := bson.M{"$set": bson.M{
+			"token":         info.Token,
+			"key_id":        info.KeyID,
+			"encrypted_key": info.EncryptedKey,
+		}}
+
+		r, err := col.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return fmt.Errorf("failed to update token of org email: %s", err.Error())
+		}
+
+		if r.MatchedCount == 0 {
+			return fmt.Errorf("the token of org email: %s has been changed", email)
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
+
 func toDBModelOrgEmail(item OrgEmail) dbmodels.OrgEmailCreateInfo {
 	return dbmodels.OrgEmailCreateInfo{
-		Platform: item.Platform,
-		Token:    item.Token,
+		Email:        item.Email,
+		Platform:     item.Platform,
+		Token:        item.Token,
+		KeyID:        item.KeyID,
+		EncryptedKey: item.EncryptedKey,
 	}
 }
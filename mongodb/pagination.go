@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// pageResult is the output of the $facet stage built by pageStages.
+type pageResult struct {
+	Total []struct {
+		N int `bson:"n"`
+	} `bson:"total"`
+
+	Items bson.RawValue `bson:"items"`
+}
+
+func sortDirection(page dbmodels.PageOption) int {
+	if page.Desc {
+		return -1
+	}
+	return 1
+}
+
+// pageStages returns the stages which sort the items and fetch one page of them
+// together with the total count. The project is applied to the items of page.
+// The page must be limited, otherwise all the items are put in one document
+// of $facet which can't exceed 16MB.
+func pageStages(page dbmodels.PageOption, sort bson.D, project bson.M) bson.A {
+	items := bson.A{}
+	if n := page.Skip(); n > 0 {
+		items = append(items, bson.M{"$skip": n})
+	}
+	items = append(items,
+		bson.M{"$limit": page.PerPage},
+		bson.M{"$project": project},
+	)
+
+	return bson.A{
+		bson.M{"$sort": sort},
+		bson.M{"$facet": bson.M{
+			"total": bson.A{bson.M{"$count": "n"}},
+			"items": items,
+		}},
+	}
+}
+
+// aggregatePage runs the pipeline which ends with the stages of pageStages
+// and decodes the items of page to result which must be a pointer to slice.
+func (c *client) aggregatePage(collection string, pipeline bson.A, result interface{}, ctx context.Context) (int, error) {
+	col := c.collection(collection)
+
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("error aggregate %s: %v", collection, err)
+	}
+
+	var v []pageResult
+	if err := cursor.All(ctx, &v); err != nil {
+		return 0, err
+	}
+
+	if len(v) == 0 || len(v[0].Total) == 0 {
+		return 0, nil
+	}
+
+	if err := v[0].Items.Unmarshal(result); err != nil {
+		return 0, fmt.Errorf("error decoding the items of page: %v", err)
+	}
+	return v[0].Total[0].N, nil
+}
+
+// listPage lists one page of the items matched by the filter. It fetches the page
+// and the total count by one aggregation, but finds all the items if no page is set.
+func (c *client) listPage(collection string, filter bson.M, page dbmodels.PageOption, sort bson.D, project bson.M, result interface{}, ctx context.Context) (int, error) {
+	if page.PerPage <= 0 {
+		return c.findPage(collection, filter, page, sort, project, result, ctx)
+	}
+
+	pipeline := bson.A{bson.M{"$match": filter}}
+	pipeline = append(pipeline, pageStages(page, sort, project)...)
+
+	return c.aggregatePage(collection, pipeline, result, ctx)
+}
+
+// findPage finds one page of the items matched by the filter and decodes them
+// to result which must be a pointer to slice. It returns the total count of the items.
+func (c *client) findPage(collection string, filter bson.M, page dbmodels.PageOption, sort bson.D, project bson.M, result interface{}, ctx context.Context) (int, error) {
+	col := c.collection(collection)
+
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("error count %s: %v", collection, err)
+	}
+
+	opt := options.Find().SetSort(sort).SetProjection(project)
+	if n := page.Skip(); n > 0 {
+		opt.SetSkip(int64(n))
+	}
+	if page.PerPage > 0 {
+		opt.SetLimit(int64(page.PerPage))
+	}
+
+	cursor, err := col.Find(ctx, filter, opt)
+	if err != nil {
+		return 0, fmt.Errorf("error find %s: %v", collection, err)
+	}
+
+	if err := cursor.All(ctx, result); err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
+
+// signingFilter converts the filter to the conditions on the signings.
+func signingFilter(filter bson.M, f dbmodels.SigningFilter, enabledField, emailField string) {
+	if f.Enabled != nil {
+		filter[enabledField] = *f.Enabled
+	}
+
+	date := bson.M{}
+	if f.DateFrom != "" {
+		date["$gte"] = f.DateFrom
+	}
+	if f.DateTo != "" {
+		date["$lte"] = f.DateTo
+	}
+	if len(date) > 0 {
+		filter["date"] = date
+	}
+
+	if f.Email != "" {
+		filter[emailField] = bson.M{
+			"$regex":   regexp.QuoteMeta(f.Email),
+			"$options": "i",
+		}
+	}
+}
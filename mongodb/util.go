@@ -22,10 +22,6 @@ func structToMap(info interface{}) (map[string]interface{}, error) {
 	return body, nil
 }
 
-func addCorporationID(email string, body map[string]interface{}) {
-	body[fieldCorporationID] = util.EmailSuffix(email)
-}
-
 func isHasNotSigned(err error) bool {
 	e, ok := dbmodels.IsDBError(err)
 	return ok && e.ErrCode == util.ErrHasNotSigned
@@ -45,3 +41,30 @@ func toObjectID(uid string) (primitive.ObjectID, error) {
 func isErrNoDocuments(err error) bool {
 	return err.Error() == mongo.ErrNoDocuments.Error()
 }
+
+// errCodeDuplicateKey is the error code of mongodb when the unique index is violated.
+const errCodeDuplicateKey = 11000
+
+// isDuplicateKeyError checks whether the error is caused by inserting a duplicate key.
+func isDuplicateKeyError(err error) bool {
+	switch e := err.(type) {
+	case mongo.WriteException:
+		for _, we := range e.WriteErrors {
+			if we.Code == errCodeDuplicateKey {
+				return true
+			}
+		}
+
+	case mongo.BulkWriteException:
+		for _, we := range e.WriteErrors {
+			if we.Code == errCodeDuplicateKey {
+				return true
+			}
+		}
+
+	case mongo.CommandError:
+		return e.Code == errCodeDuplicateKey
+	}
+
+	return false
+}
@@ -2,26 +2,30 @@ package oauth2
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 
 	liboauth2 "golang.org/x/oauth2"
+
+	"github.com/opensourceways/app-cla-server/util"
 )
 
 type Oauth2Interface interface {
-	GetToken(code, scope string) (*liboauth2.Token, error)
-	GetOauth2CodeURL(state string) string
+	GetToken(code, scope, verifier string) (*liboauth2.Token, error)
+	GetOauth2CodeURL(state, verifier string) string
 }
 
 type client struct {
 	cfg *liboauth2.Config
 }
 
-func (this *client) GetToken(code, scope string) (*liboauth2.Token, error) {
-	return FetchOauth2Token(this.cfg, code)
+func (this *client) GetToken(code, scope, verifier string) (*liboauth2.Token, error) {
+	return FetchOauth2Token(this.cfg, code, verifier)
 }
 
-func (this *client) GetOauth2CodeURL(state string) string {
-	return GetOauth2CodeURL(state, this.cfg)
+func (this *client) GetOauth2CodeURL(state, verifier string) string {
+	return GetOauth2CodeURL(state, verifier, this.cfg)
 }
 
 type Oauth2Config struct {
@@ -52,14 +56,40 @@ func buildOauth2Config(cfg Oauth2Config) *liboauth2.Config {
 	}
 }
 
-func GetOauth2CodeURL(state string, cfg *liboauth2.Config) string {
-	return cfg.AuthCodeURL(state, liboauth2.AccessTypeOffline)
+// GetOauth2CodeURL returns the url of authorization. The code challenge of PKCE
+// will be carried if the code verifier is not empty.
+func GetOauth2CodeURL(state, verifier string, cfg *liboauth2.Config) string {
+	opts := []liboauth2.AuthCodeOption{liboauth2.AccessTypeOffline}
+	if verifier != "" {
+		opts = append(
+			opts,
+			liboauth2.SetAuthURLParam("code_challenge", codeChallenge(verifier)),
+			liboauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		)
+	}
+
+	return cfg.AuthCodeURL(state, opts...)
 }
 
-func FetchOauth2Token(cfg *liboauth2.Config, code string) (*liboauth2.Token, error) {
-	token, err := cfg.Exchange(context.Background(), code)
+func FetchOauth2Token(cfg *liboauth2.Config, code, verifier string) (*liboauth2.Token, error) {
+	var opts []liboauth2.AuthCodeOption
+	if verifier != "" {
+		opts = append(opts, liboauth2.SetAuthURLParam("code_verifier", verifier))
+	}
+
+	token, err := cfg.Exchange(context.Background(), code, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to retrieve token: %v", err)
 	}
 	return token, nil
 }
+
+// NewCodeVerifier generates the code verifier of PKCE.
+func NewCodeVerifier() string {
+	return util.RandStr(64, "alphanum")
+}
+
+func codeChallenge(verifier string) string {
+	v := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(v[:])
+}
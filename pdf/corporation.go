@@ -3,15 +3,21 @@ package pdf
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"sort"
 	"strconv"
+	"time"
 
+	"github.com/jung-kurt/gofpdf"
+	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+
+	"github.com/opensourceways/app-cla-server/metrics"
 	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
 func (this *pdfGenerator) GenCLAPDFForCorporation(claOrg *models.CLAOrg, signing *models.CorporationSigning, cla *models.CLA) (string, error) {
+	defer metrics.ObservePDFGeneration(time.Now())
+
 	orgSigPdfFile := util.OrgSignaturePDFFILE(this.pdfOrgSigDir, claOrg.ID)
 	if util.IsFileNotExist(orgSigPdfFile) {
 		return "", fmt.Errorf("Failed to generate pdf for corporation signing: the org signature pdf file is not exist")
@@ -40,23 +46,24 @@ func (this *pdfGenerator) genCorporPDFMissingSig(claOrg *models.CLAOrg, signing
 		project = fmt.Sprintf("%s-%s", project, claOrg.RepoID)
 	}
 
-	pdf := c.begin()
+	font := c.font(cla.Language)
+	pdf := c.begin(font)
 
 	// first page
-	c.firstPage(pdf, fmt.Sprintf("The %s Project", project))
-	c.welcome(pdf, project, claOrg.OrgEmail)
+	c.firstPage(pdf, font, fmt.Sprintf("The %s Project", project))
+	c.welcome(pdf, font, project, claOrg.OrgEmail)
 
 	orders, keys, err := buildCorporContact(cla)
 	if err != nil {
 		return "", err
 	}
-	c.contact(pdf, signing.Info, orders, keys)
+	c.contact(pdf, font, signing.Info, orders, keys)
 
-	c.declare(pdf, project)
-	c.cla(pdf, cla.Text)
+	c.declare(pdf, font, project)
+	c.cla(pdf, font, cla.Text)
 
 	// second page
-	c.secondPage(pdf, signing.Date)
+	c.secondPage(pdf, font, signing.Date)
 
 	path := util.CorporCLAPDFFile(this.pdfOutDir, claOrg.ID, signing.AdminEmail, "_missing_sig")
 	if err := c.end(pdf, path); err != nil {
@@ -65,17 +72,42 @@ func (this *pdfGenerator) genCorporPDFMissingSig(claOrg *models.CLAOrg, signing
 	return path, nil
 }
 
-func (this *pdfGenerator) mergeCorporPDFSignaturePage(pdfFile, sigFile, outfile string) error {
-	cmd := exec.Command(
-		this.pythonBin, "./util/merge-signature.py",
-		pdfFile, sigFile, outfile,
-	)
-	_, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("Failed to merge signature page for corporation pdf: %s", err.Error())
+// mergeCorporPDFSignaturePage copies all the pages of pdfFile except the last one
+// which will be overlaid on the org signature page.
+func (this *pdfGenerator) mergeCorporPDFSignaturePage(pdfFile, sigFile, outfile string) (err error) {
+	// gofpdi panics when it fails to parse the pdf file
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Failed to merge signature page for corporation pdf: %v", r)
+		}
+	}()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	w, h := pdf.GetPageSize()
+	imp := gofpdi.NewImporter()
+	box := "/MediaBox"
+
+	tpls := []int{imp.ImportPage(pdf, pdfFile, 1, box)}
+	num := len(imp.GetPageSizes())
+	for i := 2; i <= num; i++ {
+		tpls = append(tpls, imp.ImportPage(pdf, pdfFile, i, box))
+	}
+	sig := imp.ImportPage(pdf, sigFile, 1, box)
+
+	for _, tpl := range tpls[:num-1] {
+		pdf.AddPage()
+		imp.UseImportedTemplate(pdf, tpl, 0, 0, w, h)
+	}
+
+	pdf.AddPage()
+	imp.UseImportedTemplate(pdf, sig, 0, 0, w, h)
+	imp.UseImportedTemplate(pdf, tpls[num-1], 0, 0, w, h)
+
+	if pdf.Err() {
+		return fmt.Errorf("Failed to merge signature page for corporation pdf: %s", pdf.Error().Error())
 	}
 
-	return nil
+	return pdf.OutputFileAndClose(outfile)
 }
 
 func buildCorporContact(cla *models.CLA) ([]string, map[string]string, error) {
@@ -0,0 +1,301 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func newTestGenerator(t *testing.T, dir string) *pdfGenerator {
+	welTemp, err := util.NewTemplate("wel", "../conf/pdf_template_corporation/welcome.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	declTemp, err := util.NewTemplate("decl", "../conf/pdf_template_corporation/declaration.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &pdfGenerator{
+		pdfOutDir:    dir,
+		pdfOrgSigDir: dir,
+		corporation: &corporationCLAPDF{
+			welcomeTemp: welTemp,
+			declaration: declTemp,
+			gh:          5.0,
+			fonts:       map[string]*fontFamily{},
+		},
+	}
+}
+
+// genOrgSignature generates the org signature page like the blank one.
+func genOrgSignature(t *testing.T, path string) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+
+	items := [][]string{
+		{"Signature", "Signature"},
+		{"Title", "Title"},
+		{"Community", "Corporation"},
+	}
+	genSignatureItems(pdf, defaultFont, 5.0, "Community Sign", "Corporation Sign", items)
+
+	if err := pdf.OutputFileAndClose(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenCLAPDFForCorporation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	text, err := ioutil.ReadFile("testdata/cla.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claOrg := &models.CLAOrg{
+		ID:       "5f8f8f8f8f8f8f8f8f8f8f8f",
+		OrgID:    "community",
+		RepoID:   "website",
+		OrgEmail: "cla@community.org",
+	}
+	cla := &models.CLA{
+		Text:     string(text),
+		Language: "english",
+		Fields: []models.Field{
+			{ID: "2", Title: "Title"},
+			{ID: "1", Title: "Corporation Name"},
+			{ID: "10", Title: "E-Mail"},
+		},
+	}
+	signing := &models.CorporationSigning{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			AdminEmail: "admin@corp.com",
+			Date:       "2020-10-01",
+		},
+		Info: dbmodels.TypeSigningInfo{
+			"1":  "Corp Inc.",
+			"2":  "Manager",
+			"10": "admin@corp.com",
+		},
+	}
+
+	genOrgSignature(t, util.OrgSignaturePDFFILE(dir, claOrg.ID))
+
+	file, err := newTestGenerator(t, dir).GenCLAPDFForCorporation(claOrg, signing, cla)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages, err := readPDFPages(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the last page is the signature page of the org overlaid by the one of corporation.
+	last := pages[len(pages)-1]
+	for _, s := range []string{"Community Sign", "Corporation Sign", "Date", "2020-10-01"} {
+		if !strings.Contains(last, s) {
+			t.Errorf("the signature page doesn't contain %q", s)
+		}
+	}
+
+	checkGolden(t, "testdata/corporation.golden", formatPages(pages))
+}
+
+func TestValueFont(t *testing.T) {
+	utf8Font := &fontFamily{sans: "font-chinese", serif: "font-chinese", regularData: []byte("ttf")}
+	c := &corporationCLAPDF{fallback: utf8Font}
+
+	cases := []struct {
+		name  string
+		font  *fontFamily
+		value string
+		want  *fontFamily
+	}{
+		{"latin-1 value", defaultFont, "Corp Inc.", defaultFont},
+		{"chinese value", defaultFont, "某某公司", utf8Font},
+		{"utf8 font of cla", utf8Font, "某某公司", utf8Font},
+	}
+	for _, tc := range cases {
+		if got := c.valueFont(tc.font, tc.value); got != tc.want {
+			t.Errorf("%s: got font %s, want %s", tc.name, got.sans, tc.want.sans)
+		}
+	}
+
+	// the value is written as before if the fallback font is not configured.
+	c.fallback = nil
+	if got := c.valueFont(defaultFont, "某某公司"); got != defaultFont {
+		t.Errorf("got font %s without fallback, want %s", got.sans, defaultFont.sans)
+	}
+}
+
+func formatPages(pages []string) []byte {
+	buf := new(bytes.Buffer)
+	for i, p := range pages {
+		fmt.Fprintf(buf, "=== page %d ===\n%s\n", i+1, p)
+	}
+	return buf.Bytes()
+}
+
+func checkGolden(t *testing.T, golden string, got []byte) {
+	if *update {
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("the pdf is not same as %s, run the test with -update to see the difference:\n%s", golden, got)
+	}
+}
+
+var (
+	reObject    = regexp.MustCompile(`(?s)(\d+) 0 obj(.*?)endobj`)
+	reKids      = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	reRef       = regexp.MustCompile(`(\d+) 0 R`)
+	reContents  = regexp.MustCompile(`/Contents\s+(\d+) 0 R`)
+	reShowText  = regexp.MustCompile(`\(((?:\\.|[^\\)])*)\)\s*Tj`)
+	reXObjectDo = regexp.MustCompile(`/(\w+)\s+Do`)
+)
+
+// pdfObjects is the objects of a pdf file which is generated by gofpdf,
+// it is enough to extract the text written by the core fonts.
+type pdfObjects map[string]string
+
+// readPDFPages returns the text of each page.
+func readPDFPages(file string) ([]string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := pdfObjects{}
+	for _, m := range reObject.FindAllSubmatch(b, -1) {
+		objs[string(m[1])] = string(m[2])
+	}
+
+	var kids []string
+	for _, v := range objs {
+		if strings.Contains(v, "/Type /Pages") {
+			m := reKids.FindStringSubmatch(v)
+			if m == nil {
+				return nil, fmt.Errorf("no kids of pages")
+			}
+			for _, r := range reRef.FindAllStringSubmatch(m[1], -1) {
+				kids = append(kids, r[1])
+			}
+		}
+	}
+
+	pages := make([]string, 0, len(kids))
+	for _, id := range kids {
+		m := reContents.FindStringSubmatch(objs[id])
+		if m == nil {
+			return nil, fmt.Errorf("no contents of page: %s", id)
+		}
+
+		lines, err := objs.text(m[1])
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, strings.Join(lines, "\n"))
+	}
+	return pages, nil
+}
+
+// text returns the text of stream object, including the ones of form xobjects it draws.
+func (this pdfObjects) text(id string) ([]string, error) {
+	content, err := this.stream(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var r []string
+	for _, line := range strings.Split(content, "\n") {
+		if m := reXObjectDo.FindStringSubmatch(line); m != nil {
+			xid, err := this.xobject(m[1])
+			if err != nil {
+				return nil, err
+			}
+
+			v, err := this.text(xid)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, v...)
+			continue
+		}
+
+		for _, m := range reShowText.FindAllStringSubmatch(line, -1) {
+			r = append(r, unescapePDFString(m[1]))
+		}
+	}
+	return r, nil
+}
+
+func (this pdfObjects) xobject(name string) (string, error) {
+	re := regexp.MustCompile(`/` + name + `\s+(\d+) 0 R`)
+	for _, v := range this {
+		if m := re.FindStringSubmatch(v); m != nil {
+			return m[1], nil
+		}
+	}
+	return "", fmt.Errorf("unknown xobject: %s", name)
+}
+
+func (this pdfObjects) stream(id string) (string, error) {
+	v, ok := this[id]
+	if !ok {
+		return "", fmt.Errorf("unknown object: %s", id)
+	}
+
+	i := strings.Index(v, "stream")
+	j := strings.LastIndex(v, "endstream")
+	if i < 0 || j < i {
+		return "", fmt.Errorf("object: %s is not a stream", id)
+	}
+	data := strings.TrimLeft(v[i+len("stream"):j], "\r\n")
+
+	if !strings.Contains(v[:i], "/FlateDecode") {
+		return data, nil
+	}
+
+	zr, err := zlib.NewReader(strings.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+
+	b, err := ioutil.ReadAll(zr)
+	return string(b), err
+}
+
+func unescapePDFString(s string) string {
+	r := strings.NewReplacer(`\(`, "(", `\)`, ")", `\\`, `\`, `\r`, "\r")
+	return r.Replace(s)
+}
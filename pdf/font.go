@@ -0,0 +1,120 @@
+package pdf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type fontConfigs struct {
+	Fonts []fontConfig `json:"fonts"`
+	// Fallback is the language whose font is used to write the values
+	// filled by user which the font of cla doesn't support, such as the
+	// chinese name of corporation on the english cla.
+	Fallback string `json:"fallback"`
+}
+
+type fontConfig struct {
+	// Language is the language of cla, such as chinese
+	Language string `json:"language" required:"true"`
+	// Regular is the path of ttf font file
+	Regular string `json:"regular" required:"true"`
+	// Italic is the path of italic ttf font file. The regular one will
+	// be used if it is not set.
+	Italic string `json:"italic"`
+}
+
+// fontFamily is the fonts used to generate pdf. The core fonts, Arial
+// and Times, are used by default, which only support latin-1.
+type fontFamily struct {
+	sans  string
+	serif string
+	// italic is the style of italic font, it is empty if italic font is not available
+	italic string
+
+	regularData []byte
+	italicData  []byte
+}
+
+var defaultFont = &fontFamily{sans: "Arial", serif: "Times", italic: "I"}
+
+// register adds the utf8 fonts to pdf. It should be called before adding page.
+func (this *fontFamily) register(pdf *gofpdf.Fpdf) {
+	if this.regularData == nil {
+		return
+	}
+
+	pdf.AddUTF8FontFromBytes(this.sans, "", this.regularData)
+	if this.italicData != nil {
+		pdf.AddUTF8FontFromBytes(this.sans, "I", this.italicData)
+	}
+}
+
+// isUTF8 returns true if the font supports the characters beyond latin-1.
+func (this *fontFamily) isUTF8() bool {
+	return this.regularData != nil
+}
+
+func isLatin1(s string) bool {
+	for _, r := range s {
+		if r > 0xff {
+			return false
+		}
+	}
+	return true
+}
+
+// loadFonts returns the fonts for each cla language and the fallback font
+// which is nil if it is not configured.
+func loadFonts(configFile string) (map[string]*fontFamily, *fontFamily, error) {
+	fonts := map[string]*fontFamily{}
+	if configFile == "" {
+		return fonts, nil, nil
+	}
+
+	cfg := fontConfigs{}
+	if err := util.LoadFromYaml(configFile, &cfg); err != nil {
+		return nil, nil, err
+	}
+
+	for _, item := range cfg.Fonts {
+		lang := strings.ToLower(item.Language)
+		if _, ok := fonts[lang]; ok {
+			return nil, nil, fmt.Errorf("font of language: %s is configured repeatedly", item.Language)
+		}
+
+		name := fmt.Sprintf("font-%s", lang)
+		f := &fontFamily{sans: name, serif: name}
+
+		b, err := ioutil.ReadFile(item.Regular)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to load font of language: %s, %s", item.Language, err.Error())
+		}
+		f.regularData = b
+
+		if item.Italic != "" {
+			b, err := ioutil.ReadFile(item.Italic)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed to load font of language: %s, %s", item.Language, err.Error())
+			}
+			f.italicData = b
+			f.italic = "I"
+		}
+
+		fonts[lang] = f
+	}
+
+	if cfg.Fallback == "" {
+		return fonts, nil, nil
+	}
+
+	fallback, ok := fonts[strings.ToLower(cfg.Fallback)]
+	if !ok {
+		return nil, nil, fmt.Errorf("the fallback font of language: %s is not configured", cfg.Fallback)
+	}
+	return fonts, fallback, nil
+}
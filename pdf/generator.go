@@ -3,6 +3,7 @@ package pdf
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/jung-kurt/gofpdf"
@@ -14,9 +15,18 @@ type corporationCLAPDF struct {
 	welcomeTemp *template.Template
 	declaration *template.Template
 	gh          float64
+	// fonts is the fonts for each cla language
+	fonts map[string]*fontFamily
+	// fallback is the utf8 font for the contact values, it may be nil
+	fallback *fontFamily
 }
 
-func newCorporationPDF() (*corporationCLAPDF, error) {
+func newCorporationPDF(fontConfigFile string) (*corporationCLAPDF, error) {
+	fonts, fallback, err := loadFonts(fontConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
 	path := "./conf/pdf_template_corporation/welcome.tmpl"
 	welTemp, err := util.NewTemplate("wel", path)
 	if err != nil {
@@ -33,12 +43,33 @@ func newCorporationPDF() (*corporationCLAPDF, error) {
 		welcomeTemp: welTemp,
 		declaration: declTemp,
 		gh:          5.0,
+		fonts:       fonts,
+		fallback:    fallback,
 	}, nil
 }
 
-func (this *corporationCLAPDF) begin() *gofpdf.Fpdf {
+func (this *corporationCLAPDF) font(language string) *fontFamily {
+	if f, ok := this.fonts[strings.ToLower(language)]; ok {
+		return f
+	}
+	return defaultFont
+}
+
+// valueFont returns the font to write the value filled by user.
+func (this *corporationCLAPDF) valueFont(font *fontFamily, value string) *fontFamily {
+	if this.fallback == nil || font.isUTF8() || isLatin1(value) {
+		return font
+	}
+	return this.fallback
+}
+
+func (this *corporationCLAPDF) begin(font *fontFamily) *gofpdf.Fpdf {
 	pdf := gofpdf.New("P", "mm", "A4", "") // 210mm x 297mm
-	initializePdf(pdf)
+	font.register(pdf)
+	if this.fallback != nil && this.fallback != font {
+		this.fallback.register(pdf)
+	}
+	initializePdf(pdf, font)
 	return pdf
 }
 
@@ -50,10 +81,10 @@ func (this *corporationCLAPDF) end(pdf *gofpdf.Fpdf, path string) error {
 	return pdf.OutputFileAndClose(path)
 }
 
-func (this *corporationCLAPDF) firstPage(pdf *gofpdf.Fpdf, title string) {
+func (this *corporationCLAPDF) firstPage(pdf *gofpdf.Fpdf, font *fontFamily, title string) {
 	pdf.AddPage()
 
-	pdf.SetFont("Arial", "", 12)
+	pdf.SetFont(font.sans, "", 12)
 
 	pdf.CellFormat(0, 10, title, "", 1, "C", false, 0, "")
 
@@ -63,7 +94,7 @@ func (this *corporationCLAPDF) firstPage(pdf *gofpdf.Fpdf, title string) {
 	pdf.Ln(-1)
 }
 
-func (this *corporationCLAPDF) welcome(pdf *gofpdf.Fpdf, project, email string) {
+func (this *corporationCLAPDF) welcome(pdf *gofpdf.Fpdf, font *fontFamily, project, email string) {
 	data := struct {
 		Project string
 		Email   string
@@ -80,10 +111,10 @@ func (this *corporationCLAPDF) welcome(pdf *gofpdf.Fpdf, project, email string)
 		return
 	}
 
-	multlines(pdf, this.gh, buf.String())
+	multlines(pdf, font, this.gh, buf.String())
 }
 
-func (this *corporationCLAPDF) contact(pdf *gofpdf.Fpdf, items map[string]string, orders []string, keys map[string]string) {
+func (this *corporationCLAPDF) contact(pdf *gofpdf.Fpdf, font *fontFamily, items map[string]string, orders []string, keys map[string]string) {
 	gh := this.gh
 
 	f := func(title, value string) {
@@ -91,7 +122,16 @@ func (this *corporationCLAPDF) contact(pdf *gofpdf.Fpdf, items map[string]string
 
 		pdf.Cell(2, gh, " ")
 
+		vf := this.valueFont(font, value)
+		if vf != font {
+			pdf.SetFont(vf.serif, "", 12)
+		}
+
 		pdf.MultiCell(130, gh, value, "B", "L", false)
+
+		if vf != font {
+			pdf.SetFont(font.serif, "", 12)
+		}
 	}
 
 	for _, i := range orders {
@@ -100,7 +140,7 @@ func (this *corporationCLAPDF) contact(pdf *gofpdf.Fpdf, items map[string]string
 	}
 }
 
-func (this *corporationCLAPDF) declare(pdf *gofpdf.Fpdf, project string) {
+func (this *corporationCLAPDF) declare(pdf *gofpdf.Fpdf, font *fontFamily, project string) {
 	data := struct {
 		Project string
 	}{
@@ -115,17 +155,17 @@ func (this *corporationCLAPDF) declare(pdf *gofpdf.Fpdf, project string) {
 		return
 	}
 
-	multlines(pdf, this.gh, buf.String())
+	multlines(pdf, font, this.gh, buf.String())
 }
 
-func (this *corporationCLAPDF) cla(pdf *gofpdf.Fpdf, content string) {
-	multlines(pdf, this.gh, content)
+func (this *corporationCLAPDF) cla(pdf *gofpdf.Fpdf, font *fontFamily, content string) {
+	multlines(pdf, font, this.gh, content)
 }
 
-func (this *corporationCLAPDF) secondPage(pdf *gofpdf.Fpdf, date string) {
+func (this *corporationCLAPDF) secondPage(pdf *gofpdf.Fpdf, font *fontFamily, date string) {
 	item := []string{"", ""}
 	items := [][]string{item, item, item}
-	genSignatureItems(pdf, this.gh, "", "", items)
+	genSignatureItems(pdf, font, this.gh, "", "", items)
 
 	addSignatureItem(pdf, this.gh, "Date", "Date", date, "")
 }
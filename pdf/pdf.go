@@ -13,17 +13,15 @@ var generator *pdfGenerator
 type pdfGenerator struct {
 	pdfOutDir    string
 	pdfOrgSigDir string
-	pythonBin    string
 	corporation  *corporationCLAPDF
 }
 
-func InitPDFGenerator(pythonBin, pdfOutDir, pdfOrgSigDir string) error {
-	c, err := newCorporationPDF()
+func InitPDFGenerator(pdfOutDir, pdfOrgSigDir, fontConfigFile string) error {
+	c, err := newCorporationPDF(fontConfigFile)
 	if err != nil {
 		return err
 	}
 	generator = &pdfGenerator{
-		pythonBin:    pythonBin,
 		pdfOutDir:    pdfOutDir,
 		pdfOrgSigDir: pdfOrgSigDir,
 		corporation:  c,
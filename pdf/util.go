@@ -28,9 +28,9 @@ func addSignatureItem(pdf *gofpdf.Fpdf, gh float64, ltitle, rtitle, lvalue, rval
 	pdf.Ln(-1)
 }
 
-func genSignatureItems(pdf *gofpdf.Fpdf, gh float64, ltips, rtips string, items [][]string) {
+func genSignatureItems(pdf *gofpdf.Fpdf, font *fontFamily, gh float64, ltips, rtips string, items [][]string) {
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 12)
+	pdf.SetFont(font.sans, "", 12)
 
 	w := 92.5
 	pdf.CellFormat(w, gh, ltips, "", 0, "C", false, 0, "")
@@ -43,21 +43,21 @@ func genSignatureItems(pdf *gofpdf.Fpdf, gh float64, ltips, rtips string, items
 	}
 }
 
-func multlines(pdf *gofpdf.Fpdf, gh float64, content string) {
+func multlines(pdf *gofpdf.Fpdf, font *fontFamily, gh float64, content string) {
 	// Times 12
-	pdf.SetFont("Times", "", 12)
+	pdf.SetFont(font.serif, "", 12)
 	// Output justified text
 	pdf.MultiCell(0, gh, content, "", "", false)
 	// Line break
 	pdf.Ln(-1)
 }
 
-func initializePdf(pdf *gofpdf.Fpdf) {
+func initializePdf(pdf *gofpdf.Fpdf, font *fontFamily) {
 	pdf.SetFooterFunc(func() {
 		// Position at 1.5 cm from bottom
 		pdf.SetY(-15)
 		// Arial italic 8
-		pdf.SetFont("Arial", "I", 8)
+		pdf.SetFont(font.sans, font.italic, 8)
 		// Text color in gray
 		pdf.SetTextColor(128, 128, 128)
 		// Page number
@@ -79,7 +79,7 @@ func GenBlankSignaturePage() error {
 		{"Community", "Corporation"},
 	}
 
-	genSignatureItems(pdf, corporation.gh, "Community Sign", "Corporation Sign", items)
+	genSignatureItems(pdf, defaultFont, corporation.gh, "Community Sign", "Corporation Sign", items)
 
 	path := "./conf/blank_signature/english_blank_signature.pdf"
 	if err := corporation.end(pdf, path); err != nil {
@@ -0,0 +1,162 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (c *client) AddRefreshToken(token dbmodels.RefreshToken) error {
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		// clean up the expired tokens by the way
+		_, err := tx.ExecContext(ctx, "DELETE FROM refresh_tokens WHERE expiry < $1", util.Now())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO refresh_tokens (id, session_id, user_name, permission, session_expiry, expiry) "+
+				"VALUES ($1, $2, $3, $4, $5, $6)",
+			token.ID, token.SessionID, token.User, token.Permission, token.SessionExpiry, token.Expiry,
+		)
+		return err
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		return fmt.Errorf("failed to add refresh token: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *client) UseRefreshToken(tokenID string) (dbmodels.RefreshToken, error) {
+	var v dbmodels.RefreshToken
+
+	f := func(ctx context.Context) error {
+		return c.db.QueryRowContext(
+			ctx,
+			"DELETE FROM refresh_tokens WHERE id = $1 "+
+				"RETURNING id, session_id, user_name, permission, session_expiry, expiry",
+			tokenID,
+		).Scan(&v.ID, &v.SessionID, &v.User, &v.Permission, &v.SessionExpiry, &v.Expiry)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return dbmodels.RefreshToken{}, dbmodels.DBError{
+				ErrCode: util.ErrInvalidRefreshToken,
+				Err:     fmt.Errorf("unknown refresh token"),
+			}
+		}
+		return dbmodels.RefreshToken{}, err
+	}
+
+	if v.Expiry < util.Now() {
+		return dbmodels.RefreshToken{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidRefreshToken,
+			Err:     fmt.Errorf("refresh token is expired"),
+		}
+	}
+	return v, nil
+}
+
+func (c *client) DeleteRefreshTokensOfSession(sessionID string) error {
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(ctx, "DELETE FROM refresh_tokens WHERE session_id = $1", sessionID)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) RevokeAccessSession(sessionID string, expiry int64) error {
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		// the session which has expired needn't be kept in the revocation list
+		_, err := tx.ExecContext(ctx, "DELETE FROM revoked_sessions WHERE expiry < $1", util.Now())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO revoked_sessions (id, expiry) VALUES ($1, $2) "+
+				"ON CONFLICT (id) DO UPDATE SET expiry = EXCLUDED.expiry",
+			sessionID, expiry,
+		)
+		return err
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) IsAccessSessionRevoked(sessionID string) (bool, error) {
+	revoked := false
+
+	f := func(ctx context.Context) error {
+		return c.db.QueryRowContext(
+			ctx, "SELECT EXISTS (SELECT 1 FROM revoked_sessions WHERE id = $1)", sessionID,
+		).Scan(&revoked)
+	}
+
+	err := withContext(f)
+	return revoked, err
+}
+
+func (c *client) SaveAccessSession(session dbmodels.AccessSession) error {
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM access_sessions WHERE expiry < $1", util.Now())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO access_sessions (id, platform_token, expiry) VALUES ($1, $2, $3) "+
+				"ON CONFLICT (id) DO UPDATE SET platform_token = EXCLUDED.platform_token, expiry = EXCLUDED.expiry",
+			session.ID, session.PlatformToken, session.Expiry,
+		)
+		return err
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) GetAccessSession(sessionID string) (dbmodels.AccessSession, error) {
+	var v dbmodels.AccessSession
+
+	f := func(ctx context.Context) error {
+		return c.db.QueryRowContext(
+			ctx, "SELECT id, platform_token, expiry FROM access_sessions WHERE id = $1", sessionID,
+		).Scan(&v.ID, &v.PlatformToken, &v.Expiry)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return dbmodels.AccessSession{}, dbmodels.DBError{
+				ErrCode: util.ErrSessionExpired,
+				Err:     fmt.Errorf("unknown session"),
+			}
+		}
+		return dbmodels.AccessSession{}, err
+	}
+
+	if v.Expiry < util.Now() {
+		return dbmodels.AccessSession{}, dbmodels.DBError{
+			ErrCode: util.ErrSessionExpired,
+			Err:     fmt.Errorf("session is expired"),
+		}
+	}
+	return v, nil
+}
+
+func (c *client) DeleteAccessSession(sessionID string) error {
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(ctx, "DELETE FROM access_sessions WHERE id = $1", sessionID)
+		return err
+	}
+
+	return withContext(f)
+}
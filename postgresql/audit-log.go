@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (c *client) AddAuditLog(log dbmodels.AuditLog) error {
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+
+	before, err := toJSON(log.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := toJSON(log.After)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(
+			ctx,
+			"INSERT INTO audit_logs (id, actor, action, platform, org_id, repo_id, cla_org_id, "+
+				"corp_id, target, before_values, after_values, source_ip, time) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+			id, log.Actor, log.Action, log.Platform, log.OrgID, log.RepoID, log.CLAOrgID,
+			log.CorpID, log.Target, before, after, log.SourceIP, log.Time,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return fmt.Errorf("failed to add audit log: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *client) ListAuditLog(opt dbmodels.AuditLogListOption) ([]dbmodels.AuditLog, int, error) {
+	cs := newConditions()
+	cs.add("platform = $%d", opt.Platform)
+	cs.add("org_id = $%d", opt.OrgID)
+	cs.addIf("corp_id = $%d", opt.CorpID)
+	cs.addIf("action = $%d", opt.Action)
+
+	order := "ORDER BY time, seq"
+	if opt.Page.Desc {
+		order = "ORDER BY time DESC, seq"
+	}
+
+	r := make([]dbmodels.AuditLog, 0)
+	total := 0
+
+	f := func(ctx context.Context) error {
+		err := c.db.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM audit_logs WHERE "+cs.String(), cs.args...,
+		).Scan(&total)
+		if err != nil {
+			return err
+		}
+
+		rows, err := c.db.QueryContext(
+			ctx,
+			"SELECT id, actor, action, platform, org_id, repo_id, cla_org_id, corp_id, target, "+
+				"before_values, after_values, source_ip, time FROM audit_logs WHERE "+cs.String()+" "+
+				order+pageClause(opt.Page),
+			cs.args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.AuditLog
+			var before, after []byte
+
+			if err := rows.Scan(
+				&item.ID, &item.Actor, &item.Action, &item.Platform, &item.OrgID, &item.RepoID,
+				&item.CLAOrgID, &item.CorpID, &item.Target, &before, &after, &item.SourceIP, &item.Time,
+			); err != nil {
+				return err
+			}
+
+			if err := fromJSON(before, &item.Before); err != nil {
+				return err
+			}
+			if err := fromJSON(after, &item.After); err != nil {
+				return err
+			}
+			r = append(r, item)
+		}
+		return rows.Err()
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, fmt.Errorf("error find audit logs: %v", err)
+	}
+	return r, total, nil
+}
@@ -0,0 +1,459 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const bindingColumns = "id, platform, org_id, repo_id, cla_id, cla_language, apply_to, org_email, " +
+	"enabled, submitter, org_signature_uploaded, cla_version, resign_required, resign_deadline"
+
+func errNoCLABinding() error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrNoCLABindingDoc,
+		Err:     fmt.Errorf("can't find cla binding"),
+	}
+}
+
+func scanBinding(row interface{ Scan(...interface{}) error }) (dbmodels.CLAOrg, error) {
+	var v dbmodels.CLAOrg
+
+	err := row.Scan(
+		&v.ID, &v.Platform, &v.OrgID, &v.RepoID, &v.CLAID, &v.CLALanguage, &v.ApplyTo, &v.OrgEmail,
+		&v.Enabled, &v.Submitter, &v.OrgSignatureUploaded, &v.CLAVersion, &v.ResignRequired, &v.ResignDeadline,
+	)
+	v.CLAVersion = normalizeCLAVersion(v.CLAVersion)
+	return v, err
+}
+
+// findBinding returns the binding which is applied to applyTo and is enabled.
+// It returns nil if not found. The applyTo is not checked if it is empty.
+// The row is locked until the end of transaction if forUpdate is true.
+func findBinding(q querier, claOrgID, applyTo string, forUpdate bool, ctx context.Context) (*dbmodels.CLAOrg, error) {
+	s := "SELECT " + bindingColumns + " FROM bindings WHERE id = $1 AND enabled"
+	if forUpdate {
+		s += " FOR UPDATE"
+	}
+
+	v, err := scanBinding(q.QueryRowContext(ctx, s, claOrgID))
+	if err != nil {
+		if isErrNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if applyTo != "" && v.ApplyTo != applyTo {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+// listBindings returns the bindings which match the conditions together with their cla versions.
+func listBindings(q querier, cond string, args []interface{}, suffix string, ctx context.Context) ([]dbmodels.CLAOrg, error) {
+	rows, err := q.QueryContext(
+		ctx, "SELECT "+bindingColumns+" FROM bindings WHERE "+cond+" "+suffix, args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r := make([]dbmodels.CLAOrg, 0)
+	for rows.Next() {
+		v, err := scanBinding(rows)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, loadCLAVersions(q, r, ctx)
+}
+
+func loadCLAVersions(q querier, bindings []dbmodels.CLAOrg, ctx context.Context) error {
+	if len(bindings) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(bindings))
+	index := map[string]int{}
+	for i := range bindings {
+		ids = append(ids, bindings[i].ID)
+		index[bindings[i].ID] = i
+	}
+
+	rows, err := q.QueryContext(
+		ctx,
+		"SELECT binding_id, version, cla_id, published_at FROM cla_versions "+
+			"WHERE binding_id = ANY($1) ORDER BY binding_id, version",
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var v dbmodels.CLAVersion
+		if err := rows.Scan(&id, &v.Version, &v.CLAID, &v.PublishedAt); err != nil {
+			return err
+		}
+
+		b := &bindings[index[id]]
+		b.CLAVersions = append(b.CLAVersions, v)
+	}
+	return rows.Err()
+}
+
+func (c *client) CreateBindingBetweenCLAAndOrg(claOrg dbmodels.CLAOrg) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO bindings (id, platform, org_id, repo_id, cla_id, cla_language, "+
+				"apply_to, org_email, enabled, submitter) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+			id, claOrg.Platform, claOrg.OrgID, claOrg.RepoID, claOrg.CLAID, claOrg.CLALanguage,
+			claOrg.ApplyTo, claOrg.OrgEmail, claOrg.Enabled, claOrg.Submitter,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO cla_versions (binding_id, version, cla_id, published_at) VALUES ($1, 1, $2, $3)",
+			id, claOrg.CLAID, util.Now(),
+		)
+		return err
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		if isErrUniqueViolation(err) {
+			return "", fmt.Errorf("the org/repo:%s/%s/%s has already been bound a cla with language:%s",
+				claOrg.Platform, claOrg.OrgID, claOrg.RepoID, claOrg.CLALanguage)
+		}
+		return "", fmt.Errorf("failed to create binding: %s", err.Error())
+	}
+	return id, nil
+}
+
+func (c *client) DeleteBindingBetweenCLAAndOrg(uid string) error {
+	if err := checkID(uid); err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(ctx, "UPDATE bindings SET enabled = FALSE WHERE id = $1", uid)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) GetBindingBetweenCLAAndOrg(uid string) (dbmodels.CLAOrg, error) {
+	if err := checkID(uid); err != nil {
+		return dbmodels.CLAOrg{}, err
+	}
+
+	var v []dbmodels.CLAOrg
+
+	f := func(ctx context.Context) (err error) {
+		v, err = listBindings(c.db, "id = $1", []interface{}{uid}, "", ctx)
+		return
+	}
+
+	if err := withContext(f); err != nil {
+		return dbmodels.CLAOrg{}, err
+	}
+	if len(v) == 0 {
+		return dbmodels.CLAOrg{}, errNoCLABinding()
+	}
+	return v[0], nil
+}
+
+func (c *client) ListBindingBetweenCLAAndOrg(opt dbmodels.CLAOrgListOption) ([]dbmodels.CLAOrg, int, error) {
+	cs := newConditions("enabled")
+	cs.add("platform = $%d", opt.Platform)
+	cs.add("org_id = $%d", opt.OrgID)
+	cs.addIf("repo_id = $%d", opt.RepoID)
+	cs.addIf("apply_to = $%d", opt.ApplyTo)
+
+	order := "ORDER BY seq"
+	if opt.Page.Desc {
+		order = "ORDER BY seq DESC"
+	}
+
+	var r []dbmodels.CLAOrg
+	total := 0
+
+	f := func(ctx context.Context) error {
+		err := c.db.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM bindings WHERE "+cs.String(), cs.args...,
+		).Scan(&total)
+		if err != nil {
+			return err
+		}
+
+		r, err = listBindings(c.db, cs.String(), cs.args, order+pageClause(opt.Page), ctx)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, fmt.Errorf("error find bindings: %v", err)
+	}
+	return r, total, nil
+}
+
+func (c *client) ListBindingForSigningPage(opt dbmodels.CLAOrgListOption) ([]dbmodels.CLAOrg, error) {
+	cs := newConditions("enabled")
+	cs.add("platform = $%d", opt.Platform)
+	cs.add("org_id = $%d", opt.OrgID)
+	cs.add("(repo_id = '' OR repo_id = $%d)", opt.RepoID)
+	cs.addIf("apply_to = $%d", opt.ApplyTo)
+
+	var v []dbmodels.CLAOrg
+
+	f := func(ctx context.Context) (err error) {
+		v, err = listBindings(c.db, cs.String(), cs.args, "ORDER BY seq", ctx)
+		return
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, fmt.Errorf("error find bindings: %v", err)
+	}
+
+	// if the repo has not been bound any clas, return clas bound to org
+	if opt.RepoID != "" {
+		r := make([]dbmodels.CLAOrg, 0, len(v))
+		for _, item := range v {
+			if item.RepoID == opt.RepoID {
+				r = append(r, item)
+			}
+		}
+		if len(r) != 0 {
+			return r, nil
+		}
+	}
+	return v, nil
+}
+
+func (c *client) PublishCLAVersion(claOrgID string, opt dbmodels.CLAVersionPublishOption) (int, error) {
+	if err := checkID(claOrgID); err != nil {
+		return 0, err
+	}
+
+	version := 0
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		binding, err := findBinding(tx, claOrgID, "", true, ctx)
+		if err != nil {
+			return err
+		}
+		if binding == nil {
+			return errNoCLABinding()
+		}
+
+		if binding.CLAID == opt.CLAID {
+			return dbmodels.DBError{
+				ErrCode: util.ErrInvalidParameter,
+				Err:     fmt.Errorf("the cla is the current version"),
+			}
+		}
+
+		version = binding.CLAVersion + 1
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO cla_versions (binding_id, version, cla_id, published_at) VALUES ($1, $2, $3, $4)",
+			claOrgID, version, opt.CLAID, util.Now(),
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE bindings SET cla_id = $2, cla_version = $3, resign_required = $4, resign_deadline = $5 "+
+				"WHERE id = $1",
+			claOrgID, opt.CLAID, version, opt.ResignRequired, opt.ResignDeadline,
+		)
+		return err
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+func (c *client) ListOutdatedSignings(claOrgID string, page dbmodels.PageOption) (dbmodels.OutdatedSignings, error) {
+	var r dbmodels.OutdatedSignings
+
+	if err := checkID(claOrgID); err != nil {
+		return r, err
+	}
+
+	individualSort, corpSort := "s.date", "s.date"
+	if page.SortBy == dbmodels.SortByEmail {
+		individualSort, corpSort = "s.email", "s.admin_email"
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		err := tx.QueryRowContext(
+			ctx, "SELECT cla_version FROM bindings WHERE id = $1", claOrgID,
+		).Scan(&r.CLAVersion)
+		if err != nil {
+			if isErrNoRows(err) {
+				return errNoCLABinding()
+			}
+			return err
+		}
+		r.CLAVersion = normalizeCLAVersion(r.CLAVersion)
+
+		cond := "s.binding_id = $1 AND s.cla_version < $2"
+		args := []interface{}{claOrgID, r.CLAVersion}
+
+		err = tx.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM individual_signings s WHERE "+cond, args...,
+		).Scan(&r.IndividualTotal)
+		if err != nil {
+			return err
+		}
+
+		individuals, err := listIndividualSignings(
+			tx, cond, args, orderClause(page, individualSort, "s.email")+pageClause(page), ctx,
+		)
+		if err != nil {
+			return err
+		}
+
+		r.Individuals = make([]dbmodels.IndividualSigningBasicInfo, 0, len(individuals))
+		for i := range individuals {
+			r.Individuals = append(r.Individuals, individuals[i].IndividualSigningBasicInfo)
+		}
+
+		err = tx.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM corporation_signings s WHERE "+cond, args...,
+		).Scan(&r.CorporationTotal)
+		if err != nil {
+			return err
+		}
+
+		corps, err := listCorpSignings(
+			tx, cond, args, orderClause(page, corpSort, "s.admin_email")+pageClause(page), ctx,
+		)
+		if err != nil {
+			return err
+		}
+
+		r.Corporations = make([]dbmodels.CorporationSigningBasicInfo, 0, len(corps))
+		for i := range corps {
+			r.Corporations = append(r.Corporations, corps[i].CorporationSigningBasicInfo)
+		}
+		return nil
+	}
+
+	err := c.doTransaction(f)
+	return r, err
+}
+
+func (c *client) UploadOrgSignature(claOrgID string, pdf []byte) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		v, err := tx.ExecContext(
+			ctx, "UPDATE bindings SET org_signature_uploaded = TRUE WHERE id = $1", claOrgID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := v.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO org_signatures (binding_id, pdf) VALUES ($1, $2) "+
+				"ON CONFLICT (binding_id) DO UPDATE SET pdf = EXCLUDED.pdf",
+			claOrgID, pdf,
+		)
+		return err
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		return fmt.Errorf("Failed to update binding: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *client) DownloadOrgSignature(claOrgID string) ([]byte, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	var pdf []byte
+
+	f := func(ctx context.Context) error {
+		return c.db.QueryRowContext(
+			ctx, "SELECT pdf FROM org_signatures WHERE binding_id = $1", claOrgID,
+		).Scan(&pdf)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return nil, fmt.Errorf("can't find the org signature")
+		}
+		return nil, err
+	}
+	return pdf, nil
+}
+
+func (c *client) UploadBlankSignature(language string, pdf []byte) error {
+	f := func(ctx context.Context) error {
+		// the first one is kept like mongodb does
+		_, err := c.db.ExecContext(
+			ctx,
+			"INSERT INTO blank_signatures (language, pdf) VALUES ($1, $2) ON CONFLICT (language) DO NOTHING",
+			language, pdf,
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) DownloadBlankSignature(language string) ([]byte, error) {
+	var pdf []byte
+
+	f := func(ctx context.Context) error {
+		return c.db.QueryRowContext(
+			ctx, "SELECT pdf FROM blank_signatures WHERE language = $1", language,
+		).Scan(&pdf)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return nil, fmt.Errorf("can't find the blank signature of %s", language)
+		}
+		return nil, err
+	}
+	return pdf, nil
+}
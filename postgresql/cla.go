@@ -0,0 +1,132 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+const claColumns = "id, name, text, language, submitter, apply_to, fields"
+
+func (c *client) CreateCLA(cla dbmodels.CLA) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	fields, err := toJSON(cla.Fields)
+	if err != nil {
+		return "", err
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(
+			ctx,
+			"INSERT INTO clas ("+claColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			id, cla.Name, cla.Text, cla.Language, cla.Submitter, cla.ApplyTo, fields,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrUniqueViolation(err) {
+			return "", fmt.Errorf("the cla(%s) is already existing", cla.Name)
+		}
+		return "", fmt.Errorf("write db failed, err:%v", err)
+	}
+	return id, nil
+}
+
+func (c *client) DeleteCLA(uid string) error {
+	if err := checkID(uid); err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(ctx, "DELETE FROM clas WHERE id = $1", uid)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrCode(err, errCodeForeignKeyViolation) {
+			return fmt.Errorf("can't delete the cla which has already been bound to org")
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *client) ListCLA(opts dbmodels.CLAListOptions) ([]dbmodels.CLA, error) {
+	cs := newConditions()
+	cs.add("submitter = $%d", opts.Submitter)
+	cs.addIf("name = $%d", opts.Name)
+	cs.addIf("language = $%d", opts.Language)
+	cs.addIf("apply_to = $%d", opts.ApplyTo)
+
+	return c.listCLA(cs.String(), cs.args...)
+}
+
+func (c *client) ListCLAByIDs(ids []string) ([]dbmodels.CLA, error) {
+	for _, id := range ids {
+		if err := checkID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.listCLA("id = ANY($1)", pq.Array(ids))
+}
+
+func (c *client) GetCLA(uid string) (dbmodels.CLA, error) {
+	if err := checkID(uid); err != nil {
+		return dbmodels.CLA{}, err
+	}
+
+	v, err := c.listCLA("id = $1", uid)
+	if err != nil {
+		return dbmodels.CLA{}, err
+	}
+	if len(v) == 0 {
+		return dbmodels.CLA{}, fmt.Errorf("can't find the cla")
+	}
+	return v[0], nil
+}
+
+func (c *client) listCLA(cond string, args ...interface{}) ([]dbmodels.CLA, error) {
+	var r []dbmodels.CLA
+
+	f := func(ctx context.Context) error {
+		rows, err := c.db.QueryContext(
+			ctx, "SELECT "+claColumns+" FROM clas WHERE "+cond+" ORDER BY seq", args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.CLA
+			var fields []byte
+
+			if err := rows.Scan(
+				&item.ID, &item.Name, &item.Text, &item.Language,
+				&item.Submitter, &item.ApplyTo, &fields,
+			); err != nil {
+				return err
+			}
+
+			if err := fromJSON(fields, &item.Fields); err != nil {
+				return err
+			}
+			r = append(r, item)
+		}
+		return rows.Err()
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, fmt.Errorf("error find clas, err:%v", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func errCorpDomainExists(domain string) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrCorpDomainExists,
+		Err:     fmt.Errorf("the domain:%s has been owned by a corporation", domain),
+	}
+}
+
+// corpIDOf returns the id of corporation which the email belongs to.
+func corpIDOf(q querier, claOrgID, email string, ctx context.Context) (string, error) {
+	domain := util.EmailSuffix(email)
+
+	corpID := ""
+	err := q.QueryRowContext(
+		ctx,
+		"SELECT corp_id FROM corporation_domains WHERE binding_id = $1 AND domain = $2 AND status = $3",
+		claOrgID, domain, dbmodels.CorpDomainStatusApproved,
+	).Scan(&corpID)
+	if err != nil {
+		if isErrNoRows(err) {
+			return domain, nil
+		}
+		return "", err
+	}
+	return corpID, nil
+}
+
+func (c *client) AddCorporationDomain(claOrgID string, info dbmodels.CorporationDomain) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkCorpBinding(tx, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		item, err := findCorpSigning(tx, claOrgID, info.CorpID, ctx)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return errHasNotSigned("the corp:%s has not signed", info.CorpID)
+		}
+
+		if info.Domain == info.CorpID {
+			return errCorpDomainExists(info.Domain)
+		}
+
+		item, err = findCorpSigning(tx, claOrgID, info.Domain, ctx)
+		if err != nil {
+			return err
+		}
+		if item != nil {
+			return errCorpDomainExists(info.Domain)
+		}
+
+		// the rejected domain can be requested again.
+		v, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO corporation_domains (binding_id, corp_id, domain, status, requester, date) "+
+				"VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (binding_id, domain) DO UPDATE SET "+
+				"corp_id = EXCLUDED.corp_id, status = EXCLUDED.status, requester = EXCLUDED.requester, "+
+				"date = EXCLUDED.date WHERE corporation_domains.status = $7",
+			claOrgID, info.CorpID, info.Domain, info.Status, info.Requester, info.Date,
+			dbmodels.CorpDomainStatusRejected,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := v.RowsAffected(); err != nil || n == 0 {
+			return errCorpDomainExists(info.Domain)
+		}
+		return nil
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) UpdateCorporationDomainStatus(claOrgID, domain, status string) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkCorpBinding(tx, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		if status == dbmodels.CorpDomainStatusApproved {
+			item, err := findCorpSigning(tx, claOrgID, domain, ctx)
+			if err != nil {
+				return err
+			}
+			if item != nil {
+				return errCorpDomainExists(domain)
+			}
+		}
+
+		v, err := tx.ExecContext(
+			ctx,
+			"UPDATE corporation_domains SET status = $3 WHERE binding_id = $1 AND domain = $2 AND status = $4",
+			claOrgID, domain, status, dbmodels.CorpDomainStatusPending,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := v.RowsAffected(); err != nil || n == 0 {
+			return dbmodels.DBError{
+				ErrCode: util.ErrNoPendingCorpDomain,
+				Err:     fmt.Errorf("no pending domain:%s", domain),
+			}
+		}
+		return nil
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) ListCorporationDomain(claOrgID string, opt dbmodels.CorporationDomainListOption) ([]dbmodels.CorporationDomain, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	cs := newConditions()
+	cs.add("binding_id = $%d", claOrgID)
+	cs.addIf("corp_id = $%d", opt.CorpID)
+	cs.addIf("status = $%d", opt.Status)
+
+	r := make([]dbmodels.CorporationDomain, 0)
+
+	f := func(ctx context.Context) error {
+		if err := checkCorpBinding(c.db, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		rows, err := c.db.QueryContext(
+			ctx,
+			"SELECT corp_id, domain, status, requester, date FROM corporation_domains WHERE "+
+				cs.String()+" ORDER BY domain",
+			cs.args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var v dbmodels.CorporationDomain
+			if err := rows.Scan(&v.CorpID, &v.Domain, &v.Status, &v.Requester, &v.Date); err != nil {
+				return err
+			}
+			r = append(r, v)
+		}
+		return rows.Err()
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (c *client) GetCorporationID(claOrgID, email string) (string, error) {
+	if err := checkID(claOrgID); err != nil {
+		return "", err
+	}
+
+	r := ""
+	f := func(ctx context.Context) (err error) {
+		r, err = corpIDOf(c.db, claOrgID, email, ctx)
+		return
+	}
+
+	err := withContext(f)
+	return r, err
+}
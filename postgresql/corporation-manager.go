@@ -0,0 +1,366 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// corpManagerConditions returns the conditions which match the managers of the
+// corporation which the email belongs to. The role is not checked if it is empty.
+func corpManagerConditions(claOrgID, email, role string) *conditions {
+	cs := newConditions()
+	cs.add("binding_id = $%d", claOrgID)
+	cs.add("corp_id = $%d", util.EmailSuffix(email))
+	cs.addIf("role = $%d", role)
+	return cs
+}
+
+func listCorpManagerEmails(q querier, cs *conditions, ctx context.Context) ([]string, error) {
+	rows, err := q.QueryContext(ctx, "SELECT email FROM corporation_managers WHERE "+cs.String(), cs.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r := make([]string, 0)
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		r = append(r, email)
+	}
+	return r, rows.Err()
+}
+
+func (c *client) AddCorporationManager(claOrgID string, opt []dbmodels.CorporationManagerCreateOption, managerNumber int) ([]dbmodels.CorporationManagerCreateOption, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	var toAdd []dbmodels.CorporationManagerCreateOption
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		// lock the binding, so that the managers of it are added one by one
+		// and the number of them will not exceed.
+		b, err := findBinding(tx, claOrgID, dbmodels.ApplyToCorporation, true, ctx)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return dbmodels.DBError{
+				ErrCode: util.ErrNoCLABindingDoc,
+				Err:     fmt.Errorf("can't find the cla"),
+			}
+		}
+
+		ms, err := listCorpManagerEmails(tx, corpManagerConditions(claOrgID, opt[0].Email, opt[0].Role), ctx)
+		if err != nil {
+			return err
+		}
+
+		current := map[string]bool{}
+		for _, email := range ms {
+			current[email] = true
+		}
+
+		toAdd = make([]dbmodels.CorporationManagerCreateOption, 0, len(opt))
+		for _, item := range opt {
+			if !current[item.Email] {
+				toAdd = append(toAdd, item)
+				current[item.Email] = true
+			}
+		}
+
+		if len(toAdd) == 0 {
+			return nil
+		}
+
+		if len(ms)+len(toAdd) > managerNumber {
+			return dbmodels.DBError{
+				ErrCode: util.ErrNumOfCorpManagersExceeded,
+				Err:     fmt.Errorf("exceed %d managers allowed", managerNumber),
+			}
+		}
+
+		if opt[0].Role == dbmodels.RoleAdmin {
+			v, err := tx.ExecContext(
+				ctx,
+				"UPDATE corporation_signings SET admin_added = TRUE, status = $3 WHERE binding_id = $1 AND corp_id = $2",
+				claOrgID, util.EmailSuffix(opt[0].Email), dbmodels.CorpSigningStatusAdminCreated,
+			)
+			if err != nil {
+				return err
+			}
+			if n, err := v.RowsAffected(); err != nil || n == 0 {
+				return errHasNotSigned("the corp:%s has not signed", util.EmailSuffix(opt[0].Email))
+			}
+		}
+
+		for _, item := range toAdd {
+			pw, err := util.HashPassword(item.Password)
+			if err != nil {
+				return fmt.Errorf("failed to hash password: %s", err.Error())
+			}
+
+			_, err = tx.ExecContext(
+				ctx,
+				"INSERT INTO corporation_managers (binding_id, corp_id, email, role, password) "+
+					"VALUES ($1, $2, $3, $4, $5)",
+				claOrgID, util.EmailSuffix(item.Email), item.Email, item.Role, pw,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := c.doTransaction(f)
+	return toAdd, err
+}
+
+func (c *client) CheckCorporationManagerExist(opt dbmodels.CorporationManagerCheckInfo) (map[string][]dbmodels.CorporationManagerCheckResult, error) {
+	type managerRow struct {
+		binding  dbmodels.CLAOrg
+		role     string
+		password string
+		changed  bool
+	}
+
+	var v []managerRow
+
+	f := func(ctx context.Context) error {
+		rows, err := c.db.QueryContext(
+			ctx,
+			"SELECT b.id, b.platform, b.org_id, b.repo_id, m.role, m.password, m.initial_pw_changed "+
+				"FROM corporation_managers m JOIN bindings b ON b.id = m.binding_id "+
+				"WHERE b.enabled AND b.apply_to = $1 AND m.email = $2",
+			dbmodels.ApplyToCorporation, opt.User,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item managerRow
+			if err := rows.Scan(
+				&item.binding.ID, &item.binding.Platform, &item.binding.OrgID, &item.binding.RepoID,
+				&item.role, &item.password, &item.changed,
+			); err != nil {
+				return err
+			}
+			v = append(v, item)
+		}
+		return rows.Err()
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, fmt.Errorf("error find corporation managers: %v", err)
+	}
+
+	result := map[string][]dbmodels.CorporationManagerCheckResult{}
+	for _, item := range v {
+		if !util.CheckPassword(item.password, opt.Password) {
+			continue
+		}
+
+		if !util.IsPasswordHashed(item.password) {
+			// It is the plaintext password stored by the early versions.
+			if err := c.rehashCorpManagerPassword(item.binding.ID, opt.User, item.role, item.password); err != nil {
+				return nil, err
+			}
+		}
+
+		b := item.binding
+		result[b.ID] = append(result[b.ID], dbmodels.CorporationManagerCheckResult{
+			Email:            opt.User,
+			Role:             item.role,
+			Platform:         b.Platform,
+			OrgID:            b.OrgID,
+			RepoID:           b.RepoID,
+			InitialPWChanged: item.changed,
+		})
+	}
+	return result, nil
+}
+
+func (c *client) rehashCorpManagerPassword(claOrgID, email, role, password string) error {
+	pw, err := util.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %s", err.Error())
+	}
+
+	f := func(ctx context.Context) error {
+		// the password may have been changed meanwhile
+		_, err := c.db.ExecContext(
+			ctx,
+			"UPDATE corporation_managers SET password = $5 "+
+				"WHERE binding_id = $1 AND email = $2 AND role = $3 AND password = $4",
+			claOrgID, email, role, password, pw,
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (c *client) ResetCorporationManagerPassword(claOrgID, email string, opt dbmodels.CorporationManagerResetPassword) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	pw, err := util.HashPassword(opt.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %s", err.Error())
+	}
+
+	invalid := dbmodels.DBError{
+		ErrCode: util.ErrInvalidParameter,
+		Err:     fmt.Errorf("invalid email or old password"),
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkCorpBinding(tx, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		var role, old string
+		err := tx.QueryRowContext(
+			ctx,
+			"SELECT role, password FROM corporation_managers WHERE binding_id = $1 AND email = $2 "+
+				"LIMIT 1 FOR UPDATE",
+			claOrgID, email,
+		).Scan(&role, &old)
+		if err != nil {
+			if isErrNoRows(err) {
+				return invalid
+			}
+			return err
+		}
+
+		if !util.CheckPassword(old, opt.OldPassword) {
+			return invalid
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE corporation_managers SET password = $4, initial_pw_changed = TRUE "+
+				"WHERE binding_id = $1 AND email = $2 AND role = $3",
+			claOrgID, email, role, pw,
+		)
+		return err
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) ListCorporationManager(claOrgID, email, role string, page dbmodels.PageOption) ([]dbmodels.CorporationManagerListResult, int, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, 0, err
+	}
+
+	cs := corpManagerConditions(claOrgID, email, role)
+
+	r := make([]dbmodels.CorporationManagerListResult, 0)
+	total := 0
+
+	f := func(ctx context.Context) error {
+		if err := checkCorpBinding(c.db, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		err := c.db.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM corporation_managers WHERE "+cs.String(), cs.args...,
+		).Scan(&total)
+		if err != nil {
+			return err
+		}
+
+		rows, err := c.db.QueryContext(
+			ctx,
+			"SELECT email, role FROM corporation_managers WHERE "+cs.String()+" "+
+				orderClause(page, "email", "role")+pageClause(page),
+			cs.args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.CorporationManagerListResult
+			if err := rows.Scan(&item.Email, &item.Role); err != nil {
+				return err
+			}
+			r = append(r, item)
+		}
+		return rows.Err()
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, err
+	}
+	return r, total, nil
+}
+
+func (c *client) DeleteCorporationManager(claOrgID string, opt []dbmodels.CorporationManagerCreateOption) ([]string, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	emails := make([]string, 0, len(opt))
+	for _, item := range opt {
+		emails = append(emails, item.Email)
+	}
+
+	cs := corpManagerConditions(claOrgID, opt[0].Email, opt[0].Role)
+	cs.add("email = ANY($%d)", pq.Array(emails))
+
+	deleted := make([]string, 0, len(opt))
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkCorpBinding(tx, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(
+			ctx, "DELETE FROM corporation_managers WHERE "+cs.String()+" RETURNING email", cs.args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		removed := map[string]bool{}
+		for rows.Next() {
+			var email string
+			if err := rows.Scan(&email); err != nil {
+				return err
+			}
+			removed[email] = true
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, email := range emails {
+			if removed[email] {
+				deleted = append(deleted, email)
+			}
+		}
+		return nil
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		return make([]string, 0), err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,474 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const corpSigningColumns = "s.binding_id, s.admin_email, s.admin_name, s.corporation_name, s.date, " +
+	"s.cla_version, s.pdf_uploaded, s.admin_added, s.status, s.review_comment, s.info"
+
+// corpSigningRow is the row of table corporation_signings.
+type corpSigningRow struct {
+	dbmodels.CorporationSigningFullInfo
+
+	claOrgID string
+}
+
+func scanCorpSigning(row interface{ Scan(...interface{}) error }) (corpSigningRow, error) {
+	var v corpSigningRow
+	var info []byte
+
+	err := row.Scan(
+		&v.claOrgID, &v.AdminEmail, &v.AdminName, &v.CorporationName, &v.Date,
+		&v.CLAVersion, &v.PDFUploaded, &v.AdminAdded, &v.Status, &v.ReviewComment, &info,
+	)
+	if err != nil {
+		return v, err
+	}
+
+	v.CLAVersion = normalizeCLAVersion(v.CLAVersion)
+	return v, fromJSON(info, &v.Info)
+}
+
+// listCorpSignings returns the signings which match the conditions on
+// the table of corporation_signings as s and the table of bindings as b.
+func listCorpSignings(q querier, cond string, args []interface{}, suffix string, ctx context.Context) ([]corpSigningRow, error) {
+	rows, err := q.QueryContext(
+		ctx,
+		"SELECT "+corpSigningColumns+" FROM corporation_signings s "+
+			"JOIN bindings b ON b.id = s.binding_id WHERE "+cond+" "+suffix,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r := make([]corpSigningRow, 0)
+	for rows.Next() {
+		v, err := scanCorpSigning(rows)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, v)
+	}
+	return r, rows.Err()
+}
+
+func findCorpSigning(q querier, claOrgID, corpID string, ctx context.Context) (*corpSigningRow, error) {
+	v, err := listCorpSignings(
+		q, "s.binding_id = $1 AND s.corp_id = $2",
+		[]interface{}{claOrgID, corpID}, "", ctx,
+	)
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+	return &v[0], nil
+}
+
+// checkCorpBinding checks whether the binding exists and is applied to corporation.
+func checkCorpBinding(q querier, claOrgID, errCode string, ctx context.Context) error {
+	b, err := findBinding(q, claOrgID, dbmodels.ApplyToCorporation, false, ctx)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return dbmodels.DBError{
+			ErrCode: errCode,
+			Err:     fmt.Errorf("can't find the cla"),
+		}
+	}
+	return nil
+}
+
+func (c *client) SignAsCorporation(claOrgID, platform, org, repo string, info dbmodels.CorporationSigningInfo) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	infoJSON, err := toJSON(info.Info)
+	if err != nil {
+		return err
+	}
+
+	hasSigned := errHasSigned("this corp has already signed")
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		b, signing, err := getCorporationSigningDetail(tx, platform, org, repo, info.AdminEmail, ctx)
+		if err == nil {
+			if b.ID != claOrgID || !toSigningVersion(b, signing.CLAVersion).IsOutdated() {
+				return hasSigned
+			}
+
+			// re-sign the new version of cla
+			return resignAsCorporation(tx, b, signing, info, infoJSON, ctx)
+		}
+		if !isHasNotSigned(err) {
+			return err
+		}
+
+		version := 0
+		err = tx.QueryRowContext(
+			ctx, "SELECT cla_version FROM bindings WHERE id = $1 FOR SHARE", claOrgID,
+		).Scan(&version)
+		if err != nil {
+			if isErrNoRows(err) {
+				return errNoCLABinding()
+			}
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO corporation_signings (binding_id, corp_id, admin_email, admin_name, "+
+				"corporation_name, date, cla_version, info, status) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+			claOrgID, util.EmailSuffix(info.AdminEmail), info.AdminEmail, info.AdminName,
+			info.CorporationName, info.Date, normalizeCLAVersion(version), infoJSON,
+			dbmodels.CorpSigningStatusSubmitted,
+		)
+		return err
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		if isErrUniqueViolation(err) {
+			return hasSigned
+		}
+		return err
+	}
+	return nil
+}
+
+// resignAsCorporation replaces the outdated signing by the one of new cla version.
+// The pdf has to be uploaded and reviewed again, but the managers are kept.
+func resignAsCorporation(tx *sql.Tx, binding *dbmodels.CLAOrg, item *corpSigningRow, info dbmodels.CorporationSigningInfo, infoJSON []byte, ctx context.Context) error {
+	if item.AdminEmail != info.AdminEmail {
+		return errHasSigned("this corp can only re-sign by its administrator")
+	}
+
+	corpID := util.EmailSuffix(item.AdminEmail)
+	version := normalizeCLAVersion(binding.CLAVersion)
+
+	v, err := tx.ExecContext(
+		ctx,
+		"UPDATE corporation_signings SET admin_name = $3, corporation_name = $4, date = $5, "+
+			"cla_version = $6, info = $7, pdf_uploaded = FALSE, status = $8, review_comment = '' "+
+			"WHERE binding_id = $1 AND corp_id = $2 AND cla_version < $6",
+		binding.ID, corpID, info.AdminName, info.CorporationName, info.Date,
+		version, infoJSON, dbmodels.CorpSigningStatusSubmitted,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := v.RowsAffected(); err != nil || n == 0 {
+		return errHasSigned("this corp has already signed")
+	}
+
+	_, err = tx.ExecContext(
+		ctx, "DELETE FROM corporation_signing_pdfs WHERE binding_id = $1 AND corp_id = $2",
+		binding.ID, corpID,
+	)
+	return err
+}
+
+func getCorporationSigningDetail(q querier, platform, org, repo, email string, ctx context.Context) (*dbmodels.CLAOrg, *corpSigningRow, error) {
+	bindings, err := bindingsOfSigning(q, platform, org, repo, dbmodels.ApplyToCorporation, false, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range bindings {
+		corpID, err := corpIDOf(q, bindings[i].ID, email, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		item, err := findCorpSigning(q, bindings[i].ID, corpID, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if item != nil {
+			return &bindings[i], item, nil
+		}
+	}
+
+	return nil, nil, errHasNotSigned(
+		"the corp/individual has not signed for this org/repo: %s/%s/%s", platform, org, repo,
+	)
+}
+
+func (c *client) GetCorporationSigningDetail(platform, org, repo, email string) (string, dbmodels.CorporationSigningDetail, error) {
+	var claOrgID string
+	var r dbmodels.CorporationSigningDetail
+
+	f := func(ctx context.Context) error {
+		binding, item, err := getCorporationSigningDetail(c.db, platform, org, repo, email, ctx)
+		if err == nil {
+			claOrgID = binding.ID
+			r = item.CorporationSigningDetail
+		}
+		return err
+	}
+
+	err := withContext(f)
+	return claOrgID, r, err
+}
+
+func (c *client) CheckCorporationSigning(claOrgID, email string) (dbmodels.CorporationSigningDetail, error) {
+	var r dbmodels.CorporationSigningDetail
+
+	if err := checkID(claOrgID); err != nil {
+		return r, err
+	}
+
+	f := func(ctx context.Context) error {
+		if err := checkCorpBinding(c.db, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		item, err := findCorpSigning(c.db, claOrgID, util.EmailSuffix(email), ctx)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return errHasNotSigned("the corp:%s has not signed", util.EmailSuffix(email))
+		}
+
+		r = item.CorporationSigningDetail
+		return nil
+	}
+
+	err := withContext(f)
+	return r, err
+}
+
+func (c *client) UploadCorporationSigningPDF(claOrgID, adminEmail string, pdf []byte) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	corpID := util.EmailSuffix(adminEmail)
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkCorpBinding(tx, claOrgID, util.ErrInvalidParameter, ctx); err != nil {
+			return err
+		}
+
+		status, err := lockCorpSigningStatus(tx, claOrgID, corpID, ctx)
+		if err != nil {
+			if isErrNoRows(err) {
+				return dbmodels.DBError{
+					ErrCode: util.ErrInvalidParameter,
+					Err:     fmt.Errorf("can't find the corp signing record"),
+				}
+			}
+			return err
+		}
+
+		to := dbmodels.CorpSigningStatusPDFUploaded
+		if !dbmodels.CanChangeCorpSigningStatus(status, to) {
+			return errInvalidSigningStatus(status, to)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE corporation_signings SET pdf_uploaded = TRUE, status = $3 WHERE binding_id = $1 AND corp_id = $2",
+			claOrgID, corpID, to,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO corporation_signing_pdfs (binding_id, corp_id, pdf) VALUES ($1, $2, $3) "+
+				"ON CONFLICT (binding_id, corp_id) DO UPDATE SET pdf = EXCLUDED.pdf",
+			claOrgID, corpID, pdf,
+		)
+		return err
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) UpdateCorporationSigningStatus(claOrgID, email string, opt dbmodels.CorporationSigningStatusUpdate) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	corpID := util.EmailSuffix(email)
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkCorpBinding(tx, claOrgID, util.ErrNoCLABindingDoc, ctx); err != nil {
+			return err
+		}
+
+		status, err := lockCorpSigningStatus(tx, claOrgID, corpID, ctx)
+		if err != nil {
+			if isErrNoRows(err) {
+				return errHasNotSigned("the corp:%s has not signed", corpID)
+			}
+			return err
+		}
+
+		if !dbmodels.CanChangeCorpSigningStatus(status, opt.Status) {
+			return errInvalidSigningStatus(status, opt.Status)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE corporation_signings SET status = $3, review_comment = $4 WHERE binding_id = $1 AND corp_id = $2",
+			claOrgID, corpID, opt.Status, opt.Comment,
+		)
+		return err
+	}
+
+	return c.doTransaction(f)
+}
+
+// lockCorpSigningStatus returns the status of corp signing and locks it until
+// the transaction ends.
+func lockCorpSigningStatus(tx *sql.Tx, claOrgID, corpID string, ctx context.Context) (string, error) {
+	status := ""
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT status FROM corporation_signings WHERE binding_id = $1 AND corp_id = $2 FOR UPDATE",
+		claOrgID, corpID,
+	).Scan(&status)
+	return status, err
+}
+
+func errInvalidSigningStatus(from, to string) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrInvalidSigningStatus,
+		Err:     fmt.Errorf("can't change the status of corp signing from %s to %s", from, to),
+	}
+}
+
+func (c *client) DownloadCorporationSigningPDF(claOrgID, email string) ([]byte, error) {
+	if err := checkID(claOrgID); err != nil {
+		return nil, err
+	}
+
+	var pdf []byte
+
+	f := func(ctx context.Context) error {
+		if err := checkCorpBinding(c.db, claOrgID, util.ErrInvalidParameter, ctx); err != nil {
+			return err
+		}
+
+		uploaded := false
+		err := c.db.QueryRowContext(
+			ctx,
+			"SELECT s.pdf_uploaded, p.pdf FROM corporation_signings s "+
+				"LEFT JOIN corporation_signing_pdfs p ON p.binding_id = s.binding_id AND p.corp_id = s.corp_id "+
+				"WHERE s.binding_id = $1 AND s.corp_id = $2",
+			claOrgID, util.EmailSuffix(email),
+		).Scan(&uploaded, &pdf)
+		if err != nil {
+			if isErrNoRows(err) {
+				return dbmodels.DBError{
+					ErrCode: util.ErrInvalidParameter,
+					Err:     fmt.Errorf("can't find the corp signing in this record"),
+				}
+			}
+			return err
+		}
+
+		if !uploaded {
+			return dbmodels.DBError{
+				ErrCode: util.ErrPDFHasNotUploaded,
+				Err:     fmt.Errorf("pdf has not yet been uploaded"),
+			}
+		}
+		return nil
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+	return pdf, nil
+}
+
+// corpSigningConditions returns the conditions which match the signings of
+// bindings by the option.
+func corpSigningConditions(opt dbmodels.CorporationSigningListOption) *conditions {
+	cs := newConditions("b.enabled")
+	cs.add("b.apply_to = $%d", dbmodels.ApplyToCorporation)
+	cs.add("b.platform = $%d", opt.Platform)
+	cs.add("b.org_id = $%d", opt.OrgID)
+	cs.add("b.repo_id = $%d", opt.RepoID)
+	cs.addIf("b.cla_language = $%d", opt.CLALanguage)
+	return cs
+}
+
+func (c *client) ListCorporationSigning(opt dbmodels.CorporationSigningListOption) ([]dbmodels.CorporationSigningListItem, int, error) {
+	cs := corpSigningConditions(opt)
+	signingFilter(cs, opt.Filter, "s.admin_added", "s.admin_email")
+	cs.addIf("s.status = $%d", opt.Status)
+
+	column := "s.date"
+	switch opt.Page.SortBy {
+	case dbmodels.SortByName:
+		column = "s.corporation_name"
+	case dbmodels.SortByEmail:
+		column = "s.admin_email"
+	}
+
+	var v []corpSigningRow
+	total := 0
+
+	f := func(ctx context.Context) error {
+		err := c.db.QueryRowContext(
+			ctx,
+			"SELECT COUNT(*) FROM corporation_signings s JOIN bindings b ON b.id = s.binding_id WHERE "+cs.String(),
+			cs.args...,
+		).Scan(&total)
+		if err != nil {
+			return err
+		}
+
+		v, err = listCorpSignings(
+			c.db, cs.String(), cs.args, orderClause(opt.Page, column, "s.admin_email")+pageClause(opt.Page), ctx,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, fmt.Errorf("error find corporation signings: %v", err)
+	}
+
+	r := make([]dbmodels.CorporationSigningListItem, 0, len(v))
+	for i := range v {
+		r = append(r, dbmodels.CorporationSigningListItem{
+			CLAOrgID:                 v[i].claOrgID,
+			CorporationSigningDetail: v[i].CorporationSigningDetail,
+		})
+	}
+	return r, total, nil
+}
+
+func (c *client) ListCorporationSigningInfo(opt dbmodels.CorporationSigningListOption) (map[string][]dbmodels.CorporationSigningFullInfo, error) {
+	cs := corpSigningConditions(opt)
+
+	var v []corpSigningRow
+
+	f := func(ctx context.Context) (err error) {
+		v, err = listCorpSignings(c.db, cs.String(), cs.args, "", ctx)
+		return
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, fmt.Errorf("error find corporation signings: %v", err)
+	}
+
+	r := map[string][]dbmodels.CorporationSigningFullInfo{}
+	for i := range v {
+		r[v[i].claOrgID] = append(r[v[i].claOrgID], v[i].CorporationSigningFullInfo)
+	}
+	return r, nil
+}
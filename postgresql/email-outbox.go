@@ -0,0 +1,140 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func errEmailJobLeaseLost() error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrEmailJobLeaseLost,
+		Err:     fmt.Errorf("the lease of email job is lost"),
+	}
+}
+
+func (c *client) AddEmailJob(job dbmodels.EmailJob) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(
+			ctx,
+			"INSERT INTO email_jobs (id, kind, org_email, payload, status, max_attempts, next_run_at) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			id, job.Kind, job.OrgEmail, job.Payload, dbmodels.EmailJobStatusPending,
+			job.MaxAttempts, job.NextRunAt,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return "", fmt.Errorf("failed to add email job: %s", err.Error())
+	}
+	return id, nil
+}
+
+func (c *client) ClaimEmailJob(now, lease int64) (*dbmodels.EmailJob, error) {
+	owner, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	var v dbmodels.EmailJob
+
+	f := func(ctx context.Context) error {
+		// SKIP LOCKED makes the workers claim different jobs at the same time.
+		return c.db.QueryRowContext(
+			ctx,
+			"UPDATE email_jobs SET status = $1, lease_owner = $2, lease_until = $3, attempts = attempts + 1 "+
+				"WHERE id = (SELECT id FROM email_jobs WHERE "+
+				"((status = $4 AND next_run_at <= $5) OR (status = $1 AND lease_until <= $5)) "+
+				"AND attempts < max_attempts "+
+				"ORDER BY next_run_at, id LIMIT 1 FOR UPDATE SKIP LOCKED) "+
+				"RETURNING id, kind, org_email, payload, status, attempts, max_attempts, next_run_at, last_error, "+
+				"lease_owner, lease_until",
+			dbmodels.EmailJobStatusRunning, owner, now+lease, dbmodels.EmailJobStatusPending, now,
+		).Scan(
+			&v.ID, &v.Kind, &v.OrgEmail, &v.Payload, &v.Status,
+			&v.Attempts, &v.MaxAttempts, &v.NextRunAt, &v.LastError,
+			&v.LeaseOwner, &v.LeaseUntil,
+		)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (c *client) FinishEmailJob(job *dbmodels.EmailJob) error {
+	// the payload may include sensitive data, such as password
+	return c.updateEmailJob(
+		job, "status = $5, last_error = '', payload = NULL", dbmodels.EmailJobStatusDone,
+	)
+}
+
+func (c *client) RetryEmailJob(job *dbmodels.EmailJob, nextRunAt int64, reason string) error {
+	return c.updateEmailJob(
+		job, "status = $5, next_run_at = $6, last_error = $7",
+		dbmodels.EmailJobStatusPending, nextRunAt, reason,
+	)
+}
+
+func (c *client) KillEmailJob(job *dbmodels.EmailJob, reason string) error {
+	// the dead job is kept for troubleshooting, but not the payload of it.
+	return c.updateEmailJob(
+		job, "status = $5, last_error = $6, payload = NULL", dbmodels.EmailJobStatusDead, reason,
+	)
+}
+
+func (c *client) CountEmailJobs(status string) (int, error) {
+	n := 0
+
+	f := func(ctx context.Context) error {
+		return c.db.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM email_jobs WHERE status = $1", status,
+		).Scan(&n)
+	}
+
+	if err := withContext(f); err != nil {
+		return 0, fmt.Errorf("failed to count email jobs: %s", err.Error())
+	}
+	return n, nil
+}
+
+// updateEmailJob sets the columns of job whose parameters start from $5.
+// The job is updated only if it is still leased to the one who claimed it.
+func (c *client) updateEmailJob(job *dbmodels.EmailJob, set string, args ...interface{}) error {
+	if err := checkID(job.ID); err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		r, err := c.db.ExecContext(
+			ctx,
+			"UPDATE email_jobs SET "+set+
+				" WHERE id = $1 AND status = $2 AND lease_owner = $3 AND lease_until = $4",
+			append([]interface{}{
+				job.ID, dbmodels.EmailJobStatusRunning, job.LeaseOwner, job.LeaseUntil,
+			}, args...)...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update email job: %s", err.Error())
+		}
+
+		if n, err := r.RowsAffected(); err != nil || n == 0 {
+			return errEmailJobLeaseLost()
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
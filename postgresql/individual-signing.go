@@ -0,0 +1,503 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const individualColumns = "s.binding_id, s.email, s.name, s.date, s.enabled, s.cla_version, s.signer, s.info"
+
+// individualSigningRow is the row of table individual_signings.
+type individualSigningRow struct {
+	dbmodels.IndividualSigningInfo
+
+	claOrgID string
+}
+
+func scanIndividualSigning(row interface{ Scan(...interface{}) error }) (individualSigningRow, error) {
+	var v individualSigningRow
+	var info []byte
+
+	err := row.Scan(
+		&v.claOrgID, &v.Email, &v.Name, &v.Date, &v.Enabled, &v.CLAVersion, &v.Signer, &info,
+	)
+	if err != nil {
+		return v, err
+	}
+
+	v.CLAVersion = normalizeCLAVersion(v.CLAVersion)
+	return v, fromJSON(info, &v.Info)
+}
+
+// listIndividualSignings returns the signings which match the conditions on
+// the table of individual_signings as s and the table of bindings as b.
+func listIndividualSignings(q querier, cond string, args []interface{}, suffix string, ctx context.Context) ([]individualSigningRow, error) {
+	rows, err := q.QueryContext(
+		ctx,
+		"SELECT "+individualColumns+" FROM individual_signings s "+
+			"JOIN bindings b ON b.id = s.binding_id WHERE "+cond+" "+suffix,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r := make([]individualSigningRow, 0)
+	for rows.Next() {
+		v, err := scanIndividualSigning(rows)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, v)
+	}
+	return r, rows.Err()
+}
+
+func addIndividualSigningEvent(tx *sql.Tx, claOrgID, email, signer, action, reason string, ctx context.Context) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO individual_signing_events (binding_id, email, signer, action, reason, time) "+
+			"VALUES ($1, $2, $3, $4, $5, $6)",
+		claOrgID, email, signer, action, reason, util.Now(),
+	)
+	return err
+}
+
+// checkIndividualBinding checks whether the binding exists and is applied to individual.
+func checkIndividualBinding(q querier, claOrgID string, ctx context.Context) error {
+	b, err := findBinding(q, claOrgID, dbmodels.ApplyToIndividual, false, ctx)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't find the cla"),
+		}
+	}
+	return nil
+}
+
+func (c *client) SignAsIndividual(claOrgID, platform, org, repo string, info dbmodels.IndividualSigningInfo) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	infoJSON, err := toJSON(info.Info)
+	if err != nil {
+		return err
+	}
+
+	hasSigned := errHasSigned("he/she has signed")
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		binding, signing, err := getIndividualSigning(tx, platform, org, repo, info.Email, false, ctx)
+		if err != nil {
+			if !isHasNotSigned(err) {
+				return err
+			}
+		} else if !toSigningVersion(binding, signing.CLAVersion).IsOutdated() {
+			return hasSigned
+		}
+
+		version := 0
+		err = tx.QueryRowContext(
+			ctx, "SELECT cla_version FROM bindings WHERE id = $1 FOR SHARE", claOrgID,
+		).Scan(&version)
+		if err != nil {
+			if isErrNoRows(err) {
+				return errNoCLABinding()
+			}
+			return err
+		}
+
+		if signing != nil {
+			// re-sign the new version of cla, the outdated signing is replaced by the new one.
+			_, err := tx.ExecContext(
+				ctx, "DELETE FROM individual_signings WHERE binding_id = $1 AND email = $2",
+				binding.ID, info.Email,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO individual_signings (binding_id, email, corp_id, name, date, enabled, "+
+				"cla_version, signer, info) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+			claOrgID, info.Email, util.EmailSuffix(info.Email), info.Name, info.Date, info.Enabled,
+			normalizeCLAVersion(version), info.Signer, infoJSON,
+		)
+		if err != nil {
+			return err
+		}
+
+		signed := false
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT EXISTS (SELECT 1 FROM individual_signing_events WHERE binding_id = $1 AND email = $2)",
+			claOrgID, info.Email,
+		).Scan(&signed)
+		if err != nil {
+			return err
+		}
+
+		action := dbmodels.SigningEventSign
+		if signed {
+			action = dbmodels.SigningEventResign
+		}
+		return addIndividualSigningEvent(tx, claOrgID, info.Email, info.Signer, action, "", ctx)
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		if isErrUniqueViolation(err) {
+			return hasSigned
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *client) DeleteIndividualSigning(claOrgID, email string) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkIndividualBinding(tx, claOrgID, ctx); err != nil {
+			return err
+		}
+
+		signer := ""
+		err := tx.QueryRowContext(
+			ctx,
+			"DELETE FROM individual_signings WHERE binding_id = $1 AND email = $2 RETURNING signer",
+			claOrgID, email,
+		).Scan(&signer)
+		if err != nil {
+			if isErrNoRows(err) {
+				return nil
+			}
+			return err
+		}
+
+		return addIndividualSigningEvent(tx, claOrgID, email, signer, dbmodels.SigningEventDelete, "", ctx)
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) RevokeIndividualSigning(claOrgID string, opt dbmodels.IndividualSigningRevokeOption) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkIndividualBinding(tx, claOrgID, ctx); err != nil {
+			return err
+		}
+
+		v, err := tx.ExecContext(
+			ctx,
+			"DELETE FROM individual_signings WHERE binding_id = $1 AND email = $2 AND "+
+				"(signer = $3 OR ($4 AND signer = ''))",
+			claOrgID, opt.Email, opt.Signer, opt.EmailVerified,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := v.RowsAffected(); err != nil || n == 0 {
+			return errHasNotSigned("can't find the corresponding signing info")
+		}
+
+		return addIndividualSigningEvent(
+			tx, claOrgID, opt.Email, opt.Signer, dbmodels.SigningEventRevoke, opt.Reason, ctx,
+		)
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) UpdateIndividualSigning(claOrgID, email string, enabled bool) error {
+	if err := checkID(claOrgID); err != nil {
+		return err
+	}
+
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		if err := checkIndividualBinding(tx, claOrgID, ctx); err != nil {
+			return err
+		}
+
+		v, err := tx.ExecContext(
+			ctx,
+			"UPDATE individual_signings SET enabled = $3 "+
+				"WHERE binding_id = $1 AND email = $2 AND enabled <> $3",
+			claOrgID, email, enabled,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := v.RowsAffected(); err != nil || n == 0 {
+			return dbmodels.DBError{
+				ErrCode: util.ErrInvalidParameter,
+				Err:     fmt.Errorf("can't find the corresponding signing info"),
+			}
+		}
+		return nil
+	}
+
+	return c.doTransaction(f)
+}
+
+func (c *client) IsIndividualSigned(platform, orgID, repoID, email string) (bool, error) {
+	enabled := false
+
+	f := func(ctx context.Context) error {
+		_, signing, err := getIndividualSigning(c.db, platform, orgID, repoID, email, true, ctx)
+		if err == nil {
+			enabled = signing.Enabled
+		}
+		return err
+	}
+
+	err := withContext(f)
+	return enabled, err
+}
+
+func (c *client) GetIndividualSigningVersion(platform, orgID, repoID, email string) (dbmodels.SigningVersion, error) {
+	var r dbmodels.SigningVersion
+
+	f := func(ctx context.Context) error {
+		binding, signing, err := getIndividualSigning(c.db, platform, orgID, repoID, email, true, ctx)
+		if err == nil {
+			r = toSigningVersion(binding, signing.CLAVersion)
+		}
+		return err
+	}
+
+	err := withContext(f)
+	return r, err
+}
+
+func toSigningVersion(binding *dbmodels.CLAOrg, version int) dbmodels.SigningVersion {
+	return dbmodels.SigningVersion{
+		CLAOrgID:       binding.ID,
+		Version:        normalizeCLAVersion(version),
+		CLAVersion:     normalizeCLAVersion(binding.CLAVersion),
+		ResignRequired: binding.ResignRequired,
+		ResignDeadline: binding.ResignDeadline,
+	}
+}
+
+func getIndividualSigning(q querier, platform, orgID, repoID, email string, orgCared bool, ctx context.Context) (*dbmodels.CLAOrg, *individualSigningRow, error) {
+	bindings, err := bindingsOfSigning(q, platform, orgID, repoID, dbmodels.ApplyToIndividual, orgCared, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range bindings {
+		b := &bindings[i]
+
+		v, err := listIndividualSignings(
+			q, "s.binding_id = $1 AND s.email = $2", []interface{}{b.ID, email}, "", ctx,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(v) > 0 {
+			return b, &v[0], nil
+		}
+	}
+
+	return nil, nil, errHasNotSigned(
+		"the corp/individual has not signed for this org/repo: %s/%s/%s", platform, orgID, repoID,
+	)
+}
+
+// bindingsOfSigning returns the bindings in which the signing of org/repo should be found.
+// The bindings of repo are preferred if the repo has been bound cla and the org is cared.
+func bindingsOfSigning(q querier, platform, org, repo, applyTo string, orgCared bool, ctx context.Context) ([]dbmodels.CLAOrg, error) {
+	cs := newConditions("enabled")
+	cs.add("platform = $%d", platform)
+	cs.add("org_id = $%d", org)
+	cs.add("apply_to = $%d", applyTo)
+	if repo != "" && orgCared {
+		cs.add("(repo_id = '' OR repo_id = $%d)", repo)
+	} else {
+		cs.add("repo_id = $%d", repo)
+	}
+
+	v, err := listBindings(q, cs.String(), cs.args, "ORDER BY seq", ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v) == 0 {
+		return nil, dbmodels.DBError{
+			ErrCode: util.ErrNoCLABindingDoc,
+			Err:     fmt.Errorf("no record for this org/repo: %s/%s/%s", platform, org, repo),
+		}
+	}
+
+	if repo == "" || !orgCared {
+		return v, nil
+	}
+
+	r := make([]dbmodels.CLAOrg, 0, len(v))
+	for _, item := range v {
+		if item.RepoID == repo {
+			r = append(r, item)
+		}
+	}
+	if len(r) == 0 {
+		return v, nil
+	}
+	return r, nil
+}
+
+func (c *client) ListIndividualSigningOfSigner(signer string) (map[string][]dbmodels.IndividualSigningBasicInfo, error) {
+	var v []individualSigningRow
+
+	f := func(ctx context.Context) (err error) {
+		v, err = listIndividualSignings(
+			c.db, "b.enabled AND b.apply_to = $1 AND s.signer = $2",
+			[]interface{}{dbmodels.ApplyToIndividual, signer}, "", ctx,
+		)
+		return
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+
+	r := map[string][]dbmodels.IndividualSigningBasicInfo{}
+	for i := range v {
+		r[v[i].claOrgID] = append(r[v[i].claOrgID], v[i].IndividualSigningBasicInfo)
+	}
+	return r, nil
+}
+
+func (c *client) ListIndividualSigningEvents(signer string) ([]dbmodels.IndividualSigningEvent, error) {
+	r := make([]dbmodels.IndividualSigningEvent, 0)
+
+	f := func(ctx context.Context) error {
+		rows, err := c.db.QueryContext(
+			ctx,
+			"SELECT binding_id, email, signer, action, reason, time FROM individual_signing_events "+
+				"WHERE signer = $1 ORDER BY time, id",
+			signer,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var v dbmodels.IndividualSigningEvent
+			if err := rows.Scan(&v.CLAOrgID, &v.Email, &v.Signer, &v.Action, &v.Reason, &v.Time); err != nil {
+				return err
+			}
+			r = append(r, v)
+		}
+		return rows.Err()
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// individualSigningConditions returns the conditions which match the signings of
+// bindings by the option.
+func individualSigningConditions(opt dbmodels.IndividualSigningListOption) *conditions {
+	cs := newConditions("b.enabled")
+	cs.add("b.apply_to = $%d", dbmodels.ApplyToIndividual)
+	cs.add("b.platform = $%d", opt.Platform)
+	cs.add("b.org_id = $%d", opt.OrgID)
+	cs.addIf("b.repo_id = $%d", opt.RepoID)
+	cs.addIf("b.cla_language = $%d", opt.CLALanguage)
+
+	if opt.CorporationEmail != "" {
+		ids := append([]string{util.EmailSuffix(opt.CorporationEmail)}, opt.CorporationDomains...)
+		cs.add("s.corp_id = ANY($%d)", pq.Array(ids))
+	}
+	return cs
+}
+
+func (c *client) ListIndividualSigning(opt dbmodels.IndividualSigningListOption) ([]dbmodels.IndividualSigningListItem, int, error) {
+	cs := individualSigningConditions(opt)
+	signingFilter(cs, opt.Filter, "s.enabled", "s.email")
+
+	column := "s.date"
+	switch opt.Page.SortBy {
+	case dbmodels.SortByName:
+		column = "s.name"
+	case dbmodels.SortByEmail:
+		column = "s.email"
+	}
+
+	var v []individualSigningRow
+	total := 0
+
+	f := func(ctx context.Context) error {
+		err := c.db.QueryRowContext(
+			ctx,
+			"SELECT COUNT(*) FROM individual_signings s JOIN bindings b ON b.id = s.binding_id WHERE "+cs.String(),
+			cs.args...,
+		).Scan(&total)
+		if err != nil {
+			return err
+		}
+
+		v, err = listIndividualSignings(
+			c.db, cs.String(), cs.args, orderClause(opt.Page, column, "s.email")+pageClause(opt.Page), ctx,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, 0, fmt.Errorf("error find individual signings: %v", err)
+	}
+
+	r := make([]dbmodels.IndividualSigningListItem, 0, len(v))
+	for i := range v {
+		r = append(r, dbmodels.IndividualSigningListItem{
+			CLAOrgID:                   v[i].claOrgID,
+			IndividualSigningBasicInfo: v[i].IndividualSigningBasicInfo,
+		})
+	}
+	return r, total, nil
+}
+
+func (c *client) ListIndividualSigningInfo(opt dbmodels.IndividualSigningListOption) (map[string][]dbmodels.IndividualSigningInfo, error) {
+	cs := individualSigningConditions(opt)
+
+	var v []individualSigningRow
+
+	f := func(ctx context.Context) (err error) {
+		v, err = listIndividualSignings(c.db, cs.String(), cs.args, "", ctx)
+		return
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, fmt.Errorf("error find individual signings: %v", err)
+	}
+
+	r := map[string][]dbmodels.IndividualSigningInfo{}
+	for i := range v {
+		item := v[i].IndividualSigningInfo
+		item.Signer = ""
+
+		r[v[i].claOrgID] = append(r[v[i].claOrgID], item)
+	}
+	return r, nil
+}
@@ -0,0 +1,450 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const migrationTable = "schema_migrations"
+
+// migration upgrades the schema of database from version-1 to version by
+// the statements of up, and down reverts it. down is empty if the migration
+// is irreversible. Both of them run in a transaction.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// migrations must be sorted by version which starts from 1 and increases by 1.
+// Never change a migration which has been released, add a new one instead.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create tables",
+		up:      createTables,
+		down:    dropTables,
+	},
+	{
+		version: 2,
+		name:    "create table of audit logs",
+		up:      createAuditLogTable,
+		down:    dropAuditLogTable,
+	},
+	{
+		version: 3,
+		name:    "add status of corporation signings",
+		up:      addCorpSigningStatus,
+		down:    dropCorpSigningStatus,
+	},
+	{
+		version: 4,
+		name:    "create table of corporation domains",
+		up:      createCorpDomainTable,
+		down:    dropCorpDomainTable,
+	},
+	{
+		version: 5,
+		name:    "add lease of email jobs",
+		up:      addEmailJobLease,
+		down:    dropEmailJobLease,
+	},
+}
+
+func latestSchemaVersion() int {
+	return len(migrations)
+}
+
+// SchemaVersion returns the version of migration which was applied latest.
+func (c *client) SchemaVersion() (int, error) {
+	v := 0
+
+	f := func(ctx context.Context) error {
+		var name sql.NullString
+		err := c.db.QueryRowContext(
+			ctx, "SELECT to_regclass($1)::text", migrationTable,
+		).Scan(&name)
+		if err != nil || !name.Valid {
+			// the database has not been migrated if the table doesn't exist.
+			return err
+		}
+
+		return c.db.QueryRowContext(
+			ctx, "SELECT COALESCE(MAX(version), 0) FROM "+migrationTable,
+		).Scan(&v)
+	}
+
+	err := withContext(f)
+	return v, err
+}
+
+// CheckSchemaVersion returns error if the database has not been migrated
+// to the version which this server requires.
+func (c *client) CheckSchemaVersion() error {
+	v, err := c.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %s", err.Error())
+	}
+
+	if n := latestSchemaVersion(); v != n {
+		return fmt.Errorf(
+			"the schema version of database is %d, but %d is required. Run the migrate command first",
+			v, n,
+		)
+	}
+	return nil
+}
+
+// Migrate applies or reverts the migrations until the schema is at the target version.
+// It migrates to the latest version if target is negative, and returns the version at which
+// the schema is after the migration.
+func (c *client) Migrate(target int) (int, error) {
+	latest := latestSchemaVersion()
+	if target < 0 {
+		target = latest
+	}
+	if target > latest {
+		return 0, fmt.Errorf("unknown schema version: %d, the latest one is %d", target, latest)
+	}
+
+	if err := c.createMigrationTable(); err != nil {
+		return 0, err
+	}
+
+	current, err := c.SchemaVersion()
+	if err != nil {
+		return 0, err
+	}
+	if current > latest {
+		return current, fmt.Errorf("the schema version of database is %d which is newer than this server", current)
+	}
+
+	for current < target {
+		m := migrations[current]
+
+		f := func(tx *sql.Tx, ctx context.Context) error {
+			if _, err := tx.ExecContext(ctx, m.up); err != nil {
+				return err
+			}
+
+			_, err := tx.ExecContext(
+				ctx,
+				"INSERT INTO "+migrationTable+" (version, name, applied_at) VALUES ($1, $2, $3)",
+				m.version, m.name, util.Now(),
+			)
+			return err
+		}
+
+		if err := c.doTransaction(f); err != nil {
+			return current, fmt.Errorf("failed to apply migration %d(%s): %s", m.version, m.name, err.Error())
+		}
+		current = m.version
+	}
+
+	for current > target {
+		m := migrations[current-1]
+
+		if m.down == "" {
+			return current, fmt.Errorf("migration %d(%s) is irreversible", m.version, m.name)
+		}
+
+		f := func(tx *sql.Tx, ctx context.Context) error {
+			if _, err := tx.ExecContext(ctx, m.down); err != nil {
+				return err
+			}
+
+			_, err := tx.ExecContext(
+				ctx, "DELETE FROM "+migrationTable+" WHERE version = $1", m.version,
+			)
+			return err
+		}
+
+		if err := c.doTransaction(f); err != nil {
+			return current, fmt.Errorf("failed to revert migration %d(%s): %s", m.version, m.name, err.Error())
+		}
+		current = m.version - 1
+	}
+
+	return current, nil
+}
+
+func (c *client) createMigrationTable() error {
+	f := func(ctx context.Context) error {
+		_, err := c.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrationTable+` (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at BIGINT NOT NULL
+		)`)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return fmt.Errorf("failed to create the table of migrations: %s", err.Error())
+	}
+	return nil
+}
+
+const createTables = `
+CREATE TABLE clas (
+	id        CHAR(24) PRIMARY KEY,
+	seq       BIGSERIAL,
+	name      TEXT NOT NULL,
+	text      TEXT NOT NULL,
+	language  TEXT NOT NULL,
+	submitter TEXT NOT NULL,
+	apply_to  TEXT NOT NULL,
+	fields    JSONB,
+	UNIQUE (submitter, name)
+);
+
+CREATE TABLE bindings (
+	id                     CHAR(24) PRIMARY KEY,
+	seq                    BIGSERIAL,
+	platform               TEXT NOT NULL,
+	org_id                 TEXT NOT NULL,
+	repo_id                TEXT NOT NULL DEFAULT '',
+	cla_id                 CHAR(24) NOT NULL REFERENCES clas (id),
+	cla_language           TEXT NOT NULL,
+	apply_to               TEXT NOT NULL,
+	org_email              TEXT NOT NULL,
+	enabled                BOOLEAN NOT NULL,
+	submitter              TEXT NOT NULL,
+	org_signature_uploaded BOOLEAN NOT NULL DEFAULT FALSE,
+	cla_version            INTEGER NOT NULL DEFAULT 1,
+	resign_required        BOOLEAN NOT NULL DEFAULT FALSE,
+	resign_deadline        BIGINT NOT NULL DEFAULT 0,
+	created_at             TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- an org/repo can be bound only one cla of each language and apply_to.
+CREATE UNIQUE INDEX bindings_enabled_key ON bindings (platform, org_id, repo_id, cla_language, apply_to) WHERE enabled;
+CREATE INDEX bindings_org_idx ON bindings (platform, org_id);
+
+CREATE TABLE cla_versions (
+	binding_id   CHAR(24) NOT NULL REFERENCES bindings (id),
+	version      INTEGER NOT NULL,
+	cla_id       CHAR(24) NOT NULL REFERENCES clas (id),
+	published_at BIGINT NOT NULL,
+	PRIMARY KEY (binding_id, version)
+);
+
+CREATE TABLE org_signatures (
+	binding_id CHAR(24) PRIMARY KEY REFERENCES bindings (id),
+	pdf        BYTEA NOT NULL
+);
+
+CREATE TABLE blank_signatures (
+	language TEXT PRIMARY KEY,
+	pdf      BYTEA NOT NULL
+);
+
+CREATE TABLE individual_signings (
+	binding_id  CHAR(24) NOT NULL REFERENCES bindings (id),
+	email       TEXT NOT NULL,
+	corp_id     TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	date        TEXT NOT NULL,
+	enabled     BOOLEAN NOT NULL,
+	cla_version INTEGER NOT NULL,
+	signer      TEXT NOT NULL DEFAULT '',
+	info        JSONB,
+	PRIMARY KEY (binding_id, email)
+);
+CREATE INDEX individual_signings_corp_idx ON individual_signings (binding_id, corp_id);
+CREATE INDEX individual_signings_signer_idx ON individual_signings (signer);
+
+CREATE TABLE individual_signing_events (
+	id         BIGSERIAL PRIMARY KEY,
+	binding_id CHAR(24) NOT NULL,
+	email      TEXT NOT NULL,
+	signer     TEXT NOT NULL DEFAULT '',
+	action     TEXT NOT NULL,
+	reason     TEXT NOT NULL DEFAULT '',
+	time       BIGINT NOT NULL
+);
+CREATE INDEX individual_signing_events_signer_idx ON individual_signing_events (signer);
+CREATE INDEX individual_signing_events_email_idx ON individual_signing_events (binding_id, email);
+
+CREATE TABLE corporation_signings (
+	binding_id       CHAR(24) NOT NULL REFERENCES bindings (id),
+	corp_id          TEXT NOT NULL,
+	admin_email      TEXT NOT NULL,
+	admin_name       TEXT NOT NULL,
+	corporation_name TEXT NOT NULL,
+	date             TEXT NOT NULL,
+	cla_version      INTEGER NOT NULL,
+	pdf_uploaded     BOOLEAN NOT NULL DEFAULT FALSE,
+	admin_added      BOOLEAN NOT NULL DEFAULT FALSE,
+	info             JSONB,
+	PRIMARY KEY (binding_id, corp_id)
+);
+
+CREATE TABLE corporation_signing_pdfs (
+	binding_id CHAR(24) NOT NULL,
+	corp_id    TEXT NOT NULL,
+	pdf        BYTEA NOT NULL,
+	PRIMARY KEY (binding_id, corp_id),
+	FOREIGN KEY (binding_id, corp_id) REFERENCES corporation_signings (binding_id, corp_id) ON DELETE CASCADE
+);
+
+CREATE TABLE corporation_managers (
+	binding_id         CHAR(24) NOT NULL REFERENCES bindings (id),
+	corp_id            TEXT NOT NULL,
+	email              TEXT NOT NULL,
+	role               TEXT NOT NULL,
+	password           TEXT NOT NULL,
+	initial_pw_changed BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (binding_id, email, role)
+);
+CREATE INDEX corporation_managers_corp_idx ON corporation_managers (binding_id, corp_id);
+CREATE INDEX corporation_managers_email_idx ON corporation_managers (email);
+
+CREATE TABLE verification_codes (
+	email   TEXT NOT NULL,
+	purpose TEXT NOT NULL,
+	code    TEXT NOT NULL,
+	expiry  BIGINT NOT NULL,
+	PRIMARY KEY (email, purpose)
+);
+
+CREATE TABLE org_emails (
+	email         TEXT PRIMARY KEY,
+	platform      TEXT NOT NULL,
+	token         BYTEA,
+	key_id        TEXT NOT NULL DEFAULT '',
+	encrypted_key BYTEA
+);
+
+CREATE TABLE email_jobs (
+	id           CHAR(24) PRIMARY KEY,
+	kind         TEXT NOT NULL,
+	org_email    TEXT NOT NULL,
+	payload      BYTEA,
+	status       TEXT NOT NULL,
+	attempts     INTEGER NOT NULL DEFAULT 0,
+	max_attempts INTEGER NOT NULL,
+	next_run_at  BIGINT NOT NULL,
+	last_error   TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX email_jobs_due_idx ON email_jobs (status, next_run_at);
+
+CREATE TABLE refresh_tokens (
+	id             TEXT PRIMARY KEY,
+	session_id     TEXT NOT NULL,
+	user_name      TEXT NOT NULL,
+	permission     TEXT NOT NULL,
+	session_expiry BIGINT NOT NULL,
+	expiry         BIGINT NOT NULL
+);
+CREATE INDEX refresh_tokens_session_idx ON refresh_tokens (session_id);
+
+CREATE TABLE revoked_sessions (
+	id     TEXT PRIMARY KEY,
+	expiry BIGINT NOT NULL
+);
+
+CREATE TABLE access_sessions (
+	id             TEXT PRIMARY KEY,
+	platform_token BYTEA,
+	expiry         BIGINT NOT NULL
+);
+
+CREATE TABLE oauth_states (
+	state      TEXT PRIMARY KEY,
+	purpose    TEXT NOT NULL,
+	verifier   TEXT NOT NULL,
+	binding    TEXT NOT NULL,
+	return_url TEXT NOT NULL,
+	expiry     BIGINT NOT NULL
+);
+`
+
+const dropTables = `
+DROP TABLE oauth_states, access_sessions, revoked_sessions, refresh_tokens,
+	email_jobs, org_emails, verification_codes, corporation_managers,
+	corporation_signing_pdfs, corporation_signings, individual_signing_events,
+	individual_signings, blank_signatures, org_signatures, cla_versions,
+	bindings, clas;
+`
+
+// audit_logs can only be inserted, which is ensured by the trigger.
+const createAuditLogTable = `
+CREATE TABLE audit_logs (
+	seq           BIGSERIAL PRIMARY KEY,
+	id            CHAR(24) NOT NULL UNIQUE,
+	actor         TEXT NOT NULL,
+	action        TEXT NOT NULL,
+	platform      TEXT NOT NULL,
+	org_id        TEXT NOT NULL,
+	repo_id       TEXT NOT NULL DEFAULT '',
+	cla_org_id    TEXT NOT NULL DEFAULT '',
+	corp_id       TEXT NOT NULL DEFAULT '',
+	target        TEXT NOT NULL DEFAULT '',
+	before_values JSONB,
+	after_values  JSONB,
+	source_ip     TEXT NOT NULL DEFAULT '',
+	time          BIGINT NOT NULL
+);
+CREATE INDEX audit_logs_org_idx ON audit_logs (platform, org_id, time);
+
+CREATE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit logs can only be inserted';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE PROCEDURE audit_logs_append_only();
+`
+
+const dropAuditLogTable = `
+DROP TABLE audit_logs;
+DROP FUNCTION audit_logs_append_only();
+`
+
+// the status of existing signings is derived from the flags of them.
+const addCorpSigningStatus = `
+ALTER TABLE corporation_signings
+	ADD COLUMN status TEXT NOT NULL DEFAULT 'submitted',
+	ADD COLUMN review_comment TEXT NOT NULL DEFAULT '';
+CREATE INDEX corporation_signings_status_idx ON corporation_signings (status);
+
+UPDATE corporation_signings SET status = 'pdf_uploaded' WHERE pdf_uploaded AND NOT admin_added;
+UPDATE corporation_signings SET status = 'admin_created' WHERE admin_added;
+`
+
+const dropCorpSigningStatus = `
+ALTER TABLE corporation_signings DROP COLUMN status, DROP COLUMN review_comment;
+`
+
+const createCorpDomainTable = `
+CREATE TABLE corporation_domains (
+	binding_id CHAR(24) NOT NULL REFERENCES bindings (id),
+	corp_id    TEXT NOT NULL,
+	domain     TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	requester  TEXT NOT NULL,
+	date       TEXT NOT NULL,
+	PRIMARY KEY (binding_id, domain)
+);
+`
+
+const dropCorpDomainTable = `
+DROP TABLE corporation_domains;
+`
+
+// the lease of running jobs was kept by next_run_at before.
+const addEmailJobLease = `
+ALTER TABLE email_jobs
+	ADD COLUMN lease_owner TEXT NOT NULL DEFAULT '',
+	ADD COLUMN lease_until BIGINT NOT NULL DEFAULT 0;
+
+UPDATE email_jobs SET lease_until = next_run_at WHERE status = 'running';
+`
+
+const dropEmailJobLease = `
+ALTER TABLE email_jobs DROP COLUMN lease_owner, DROP COLUMN lease_until;
+`
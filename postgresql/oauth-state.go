@@ -0,0 +1,64 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (c *client) AddOAuthState(opt dbmodels.OAuthState) error {
+	f := func(tx *sql.Tx, ctx context.Context) error {
+		// delete the expired states which were never used
+		_, err := tx.ExecContext(ctx, "DELETE FROM oauth_states WHERE expiry < $1", util.Now())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO oauth_states (state, purpose, verifier, binding, return_url, expiry) "+
+				"VALUES ($1, $2, $3, $4, $5, $6)",
+			opt.State, opt.Purpose, opt.Verifier, opt.Binding, opt.ReturnURL, opt.Expiry,
+		)
+		return err
+	}
+
+	if err := c.doTransaction(f); err != nil {
+		return fmt.Errorf("failed to add oauth state: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *client) UseOAuthState(state string) (dbmodels.OAuthState, error) {
+	var v dbmodels.OAuthState
+
+	f := func(ctx context.Context) error {
+		return c.db.QueryRowContext(
+			ctx,
+			"DELETE FROM oauth_states WHERE state = $1 "+
+				"RETURNING state, purpose, verifier, binding, return_url, expiry",
+			state,
+		).Scan(&v.State, &v.Purpose, &v.Verifier, &v.Binding, &v.ReturnURL, &v.Expiry)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return dbmodels.OAuthState{}, dbmodels.DBError{
+				ErrCode: util.ErrInvalidOAuthState,
+				Err:     fmt.Errorf("unknown oauth state"),
+			}
+		}
+		return dbmodels.OAuthState{}, err
+	}
+
+	if v.Expiry < util.Now() {
+		return dbmodels.OAuthState{}, dbmodels.DBError{
+			ErrCode: util.ErrInvalidOAuthState,
+			Err:     fmt.Errorf("oauth state is expired"),
+		}
+	}
+	return v, nil
+}
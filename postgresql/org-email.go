@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const orgEmailColumns = "email, platform, token, key_id, encrypted_key"
+
+func scanOrgEmail(row interface{ Scan(...interface{}) error }) (dbmodels.OrgEmailCreateInfo, error) {
+	var v dbmodels.OrgEmailCreateInfo
+	err := row.Scan(&v.Email, &v.Platform, &v.Token, &v.KeyID, &v.EncryptedKey)
+	return v, err
+}
+
+func (c *client) CreateOrgEmail(opt dbmodels.OrgEmailCreateInfo) error {
+	f := func(ctx context.Context) error {
+		// the existing one is kept like mongodb does
+		_, err := c.db.ExecContext(
+			ctx,
+			"INSERT INTO org_emails ("+orgEmailColumns+") VALUES ($1, $2, $3, $4, $5) "+
+				"ON CONFLICT (email) DO NOTHING",
+			opt.Email, opt.Platform, opt.Token, opt.KeyID, opt.EncryptedKey,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return fmt.Errorf("Failed to create org email info: write db err:%v", err)
+	}
+	return nil
+}
+
+func (c *client) GetOrgEmailInfo(email string) (dbmodels.OrgEmailCreateInfo, error) {
+	var v dbmodels.OrgEmailCreateInfo
+
+	f := func(ctx context.Context) (err error) {
+		v, err = scanOrgEmail(c.db.QueryRowContext(
+			ctx, "SELECT "+orgEmailColumns+" FROM org_emails WHERE email = $1", email,
+		))
+		return
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return v, dbmodels.DBError{
+				ErrCode: util.ErrNoOrgEmail,
+				Err:     fmt.Errorf("can't find org email configuration"),
+			}
+		}
+		return v, err
+	}
+	return v, nil
+}
+
+func (c *client) ListOrgEmails() ([]dbmodels.OrgEmailCreateInfo, error) {
+	r := make([]dbmodels.OrgEmailCreateInfo, 0)
+
+	f := func(ctx context.Context) error {
+		rows, err := c.db.QueryContext(
+			ctx, "SELECT "+orgEmailColumns+` FROM org_emails ORDER BY email COLLATE "C"`,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			v, err := scanOrgEmail(rows)
+			if err != nil {
+				return err
+			}
+			r = append(r, v)
+		}
+		return rows.Err()
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (c *client) UpdateOrgEmailToken(email, oldKeyID string, info dbmodels.OrgEmailCreateInfo) error {
+	f := func(ctx context.Context) error {
+		v, err := c.db.ExecContext(
+			ctx,
+			"UPDATE org_emails SET token = $3, key_id = $4, encrypted_key = $5 "+
+				"WHERE email = $1 AND key_id = $2",
+			email, oldKeyID, info.Token, info.KeyID, info.EncryptedKey,
+		)
+		if err != nil {
+			return err
+		}
+
+		if n, err := v.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("the token of org email: %s has been changed", email)
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
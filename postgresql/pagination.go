@@ -0,0 +1,75 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// conditions builds the WHERE clause whose parameters are numbered in the order of adding.
+type conditions struct {
+	conds []string
+	args  []interface{}
+}
+
+func newConditions(conds ...string) *conditions {
+	return &conditions{conds: conds}
+}
+
+// add adds the condition whose placeholder of parameter is %d in the format.
+func (c *conditions) add(format string, v interface{}) {
+	c.args = append(c.args, v)
+	c.conds = append(c.conds, fmt.Sprintf(format, len(c.args)))
+}
+
+// addIf adds the condition only if v is not empty.
+func (c *conditions) addIf(format string, v string) {
+	if v != "" {
+		c.add(format, v)
+	}
+}
+
+func (c *conditions) String() string {
+	if len(c.conds) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(c.conds, " AND ")
+}
+
+// pageClause returns the LIMIT and OFFSET of the page.
+func pageClause(page dbmodels.PageOption) string {
+	s := ""
+	if page.PerPage > 0 {
+		s += fmt.Sprintf(" LIMIT %d", page.PerPage)
+	}
+	if n := page.Skip(); n > 0 {
+		s += fmt.Sprintf(" OFFSET %d", n)
+	}
+	return s
+}
+
+// orderClause sorts by the column first and then by the tie column in ascending order,
+// which is the same as the sort of mongodb. The strings are compared by bytes as mongodb does.
+func orderClause(page dbmodels.PageOption, column, tie string) string {
+	d := "ASC"
+	if page.Desc {
+		d = "DESC"
+	}
+	return fmt.Sprintf(`ORDER BY %s COLLATE "C" %s, %s COLLATE "C" ASC`, column, d, tie)
+}
+
+// signingFilter converts the filter to the conditions on the signings.
+func signingFilter(c *conditions, f dbmodels.SigningFilter, enabledColumn, emailColumn string) {
+	if f.Enabled != nil {
+		c.add(enabledColumn+" = $%d", *f.Enabled)
+	}
+
+	c.addIf("date >= $%d", f.DateFrom)
+	c.addIf("date <= $%d", f.DateTo)
+
+	if f.Email != "" {
+		r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+		c.add(emailColumn+" ILIKE $%d", "%"+r.Replace(f.Email)+"%")
+	}
+}
@@ -0,0 +1,163 @@
+// Package postgresql implements dbmodels.IDB on PostgreSQL.
+package postgresql
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var _ dbmodels.IDB = (*client)(nil)
+
+var idPattern = regexp.MustCompile("^[0-9a-f]{24}$")
+
+const (
+	errCodeUniqueViolation     = "23505"
+	errCodeForeignKeyViolation = "23503"
+)
+
+type client struct {
+	db *sql.DB
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx, so that the same
+// query can be run in or out of a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func RegisterDatabase(conn string) (*client, error) {
+	db, err := sql.Open("postgres", conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := withContext(db.PingContext); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &client{db: db}, nil
+}
+
+func (c *client) Close() error {
+	return c.db.Close()
+}
+
+// doTransaction runs f in a transaction which is committed if f succeeds
+// and is rolled back otherwise.
+func (c *client) doTransaction(f func(*sql.Tx, context.Context) error) error {
+	g := func(ctx context.Context) error {
+		tx, err := c.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start postgresql transaction: %s", err.Error())
+		}
+
+		if err := f(tx, ctx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+
+	return withContext(g)
+}
+
+func withContext(f func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return f(ctx)
+}
+
+// newID returns an id in the same format as the object id of mongodb,
+// so that the apis and the clients are not aware of the database.
+func newID() (string, error) {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint32(b, uint32(time.Now().Unix()))
+	if _, err := rand.Read(b[4:]); err != nil {
+		return "", fmt.Errorf("failed to generate id: %s", err.Error())
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func checkID(uid string) error {
+	if !idPattern.MatchString(uid) {
+		return dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     fmt.Errorf("can't convert to object id"),
+		}
+	}
+	return nil
+}
+
+func isErrCode(err error, code string) bool {
+	e, ok := err.(*pq.Error)
+	return ok && string(e.Code) == code
+}
+
+func isErrUniqueViolation(err error) bool {
+	return isErrCode(err, errCodeUniqueViolation)
+}
+
+func isErrNoRows(err error) bool {
+	return err == sql.ErrNoRows
+}
+
+func isHasNotSigned(err error) bool {
+	e, ok := dbmodels.IsDBError(err)
+	return ok && e.ErrCode == util.ErrHasNotSigned
+}
+
+func errHasNotSigned(format string, a ...interface{}) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrHasNotSigned,
+		Err:     fmt.Errorf(format, a...),
+	}
+}
+
+func errHasSigned(msg string) error {
+	return dbmodels.DBError{
+		ErrCode: util.ErrHasSigned,
+		Err:     errors.New(msg),
+	}
+}
+
+// toJSON encodes the value which is stored in the column of jsonb.
+func toJSON(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, dbmodels.DBError{
+			ErrCode: util.ErrInvalidParameter,
+			Err:     err,
+		}
+	}
+	return b, nil
+}
+
+func fromJSON(b []byte, v interface{}) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+func normalizeCLAVersion(v int) int {
+	if v < 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,42 @@
+package postgresql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/dbmodels/dbtest"
+)
+
+// TestContract runs only if CLA_TEST_POSTGRESQL_CONN is set. The public schema
+// of the database is recreated before each case, so it must be a database for test.
+func TestContract(t *testing.T) {
+	conn := os.Getenv("CLA_TEST_POSTGRESQL_CONN")
+	if conn == "" {
+		t.Skip("CLA_TEST_POSTGRESQL_CONN is not set")
+	}
+
+	var clients []*client
+	defer func() {
+		for _, c := range clients {
+			c.Close()
+		}
+	}()
+
+	dbtest.Run(t, func(t *testing.T) dbmodels.IDB {
+		c, err := RegisterDatabase(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients = append(clients, c)
+
+		if _, err := c.db.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Migrate(-1); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	})
+}
@@ -0,0 +1,58 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (c *client) CreateVerificationCode(opt dbmodels.VerificationCode) error {
+	f := func(ctx context.Context) error {
+		// the old code is replaced, including unused one.
+		_, err := c.db.ExecContext(
+			ctx,
+			"INSERT INTO verification_codes (email, purpose, code, expiry) VALUES ($1, $2, $3, $4) "+
+				"ON CONFLICT (email, purpose) DO UPDATE SET code = EXCLUDED.code, expiry = EXCLUDED.expiry",
+			opt.Email, opt.Purpose, opt.Code, opt.Expiry,
+		)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return fmt.Errorf("write db err:%v", err)
+	}
+	return nil
+}
+
+func (c *client) CheckVerificationCode(opt dbmodels.VerificationCode) error {
+	var expiry int64
+
+	f := func(ctx context.Context) error {
+		// the code can be used only once
+		return c.db.QueryRowContext(
+			ctx,
+			"DELETE FROM verification_codes WHERE email = $1 AND purpose = $2 AND code = $3 RETURNING expiry",
+			opt.Email, opt.Purpose, opt.Code,
+		).Scan(&expiry)
+	}
+
+	if err := withContext(f); err != nil {
+		if isErrNoRows(err) {
+			return dbmodels.DBError{
+				ErrCode: util.ErrWrongVerificationCode,
+				Err:     fmt.Errorf("wrong verification code"),
+			}
+		}
+		return err
+	}
+
+	if expiry < util.Now() {
+		return dbmodels.DBError{
+			ErrCode: util.ErrVerificationCodeExpired,
+			Err:     fmt.Errorf("verification code is expired"),
+		}
+	}
+	return nil
+}
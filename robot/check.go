@@ -0,0 +1,225 @@
+package robot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	cmdCheckCLA = "/check-cla"
+
+	// claCommentMark is hidden in the comment of robot, so that the comment
+	// can be found and updated when the pull request is checked again.
+	claCommentMark = "<!-- cla-check -->"
+)
+
+func isCheckCLACmd(comment string) bool {
+	for _, line := range strings.Split(comment, "\n") {
+		if strings.TrimSpace(line) == cmdCheckCLA {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *robot) checkPR(e *prEvent) error {
+	opt := models.CLAOrgListOption{
+		Platform: this.platform,
+		OrgID:    e.Org,
+		RepoID:   e.Repo,
+	}
+	bindings, err := opt.ListForSigningPage()
+	if err != nil {
+		return err
+	}
+	if len(bindings) == 0 {
+		// the org/repo does not require to sign cla
+		return nil
+	}
+
+	emails, err := this.cli.listPRCommitEmails(e)
+	if err != nil {
+		return fmt.Errorf("Failed to list the commits of pr: %s", err.Error())
+	}
+
+	// the corporation signing is found by the repo of binding exactly,
+	// which is empty if the cla is bound to the org.
+	binding := *e
+	binding.Repo = bindings[0].RepoID
+
+	unsigned := map[string]string{}
+	for _, email := range emails {
+		if _, ok := unsigned[email]; ok {
+			continue
+		}
+
+		reason, err := this.checkEmail(&binding, email)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			unsigned[email] = reason
+		}
+	}
+
+	link, err := util.RenderTemplate(this.signingURL, struct {
+		Platform string
+		Org      string
+		Repo     string
+	}{
+		Platform: this.platform,
+		Org:      e.Org,
+		Repo:     e.Repo,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(unsigned) == 0 {
+		return this.markPR(e, true, link, "Thanks for your pull request. All the authors of commits have signed the CLA.")
+	}
+
+	return this.markPR(e, false, link, unsignedComment(unsigned, link))
+}
+
+// checkEmail returns the reason why the email can't pass the check
+func (this *robot) checkEmail(e *prEvent, email string) (string, error) {
+	if email == "" {
+		return "the email of commit author is empty", nil
+	}
+
+	enabled, err := models.IsIndividualSigned(this.platform, e.Org, e.Repo, email)
+	if err == nil {
+		if !enabled {
+			return "the employee signing has not been activated by the corporation manager", nil
+		}
+		return this.checkSigningVersion(e, email)
+	}
+	if !isNotSignedErr(err) {
+		return "", err
+	}
+
+	_, _, err = models.GetCorporationSigningDetail(this.platform, e.Org, e.Repo, email)
+	if err == nil {
+		return "the corporation has signed, but the employee has not signed", nil
+	}
+	if !isNotSignedErr(err) {
+		return "", err
+	}
+
+	return "has not signed", nil
+}
+
+// checkSigningVersion checks whether it should re-sign the new version of cla.
+// The employee should also be blocked if the corporation signed an outdated version.
+func (this *robot) checkSigningVersion(e *prEvent, email string) (string, error) {
+	v, err := models.GetIndividualSigningVersion(this.platform, e.Org, e.Repo, email)
+	if err != nil {
+		return "", err
+	}
+	if isResignRequired(v) {
+		return fmt.Sprintf(
+			"has signed the version %d of CLA, but the version %d is required to sign",
+			v.Version, v.CLAVersion,
+		), nil
+	}
+
+	v, err = models.GetCorporationSigningVersion(this.platform, e.Org, e.Repo, email)
+	if err != nil {
+		if isNotSignedErr(err) {
+			// the author is not an employee
+			return "", nil
+		}
+		return "", err
+	}
+	if isResignRequired(v) {
+		return fmt.Sprintf(
+			"the corporation has signed the version %d of CLA, but the version %d is required to sign",
+			v.Version, v.CLAVersion,
+		), nil
+	}
+
+	return "", nil
+}
+
+func isResignRequired(v dbmodels.SigningVersion) bool {
+	return v.IsOutdated() && v.ResignRequired && util.Now() >= v.ResignDeadline
+}
+
+func (this *robot) markPR(e *prEvent, signed bool, link, comment string) error {
+	toAdd, toRemove := this.cfg.LabelSigned, this.cfg.LabelUnsigned
+	if !signed {
+		toAdd, toRemove = toRemove, toAdd
+	}
+
+	if err := this.cli.addPRLabel(e, toAdd); err != nil {
+		return fmt.Errorf("Failed to add label: %s", err.Error())
+	}
+
+	// the label may not exist
+	if err := this.cli.removePRLabel(e, toRemove); err != nil {
+		beego.Info(err)
+	}
+
+	if err := this.cli.setCommitStatus(e, signed, link); err != nil {
+		return fmt.Errorf("Failed to set commit status: %s", err.Error())
+	}
+
+	if err := this.comment(e, comment); err != nil {
+		return fmt.Errorf("Failed to comment on pr: %s", err.Error())
+	}
+	return nil
+}
+
+// comment updates the comment of robot if it exists, otherwise creates one.
+// The comment of others which contains the mark is ignored.
+func (this *robot) comment(e *prEvent, comment string) error {
+	login, err := this.getLogin()
+	if err != nil {
+		return err
+	}
+
+	comments, err := this.cli.listPRComments(e)
+	if err != nil {
+		return err
+	}
+
+	comment = claCommentMark + "\n" + comment
+
+	for _, item := range comments {
+		if item.Author == login && strings.Contains(item.Body, claCommentMark) {
+			return this.cli.updatePRComment(e, item.ID, comment)
+		}
+	}
+	return this.cli.createPRComment(e, comment)
+}
+
+func unsignedComment(unsigned map[string]string, link string) string {
+	emails := make([]string, 0, len(unsigned))
+	for k := range unsigned {
+		emails = append(emails, k)
+	}
+	sort.Strings(emails)
+
+	items := make([]string, 0, len(emails))
+	for _, email := range emails {
+		items = append(items, fmt.Sprintf("- **%s**: %s", email, unsigned[email]))
+	}
+
+	return fmt.Sprintf(
+		"Thanks for your pull request. Before it can be merged, the authors of the following commits need to sign the Contributor License Agreement(CLA).\n\n%s\n\nPlease sign the CLA [here](%s). After signing, comment `%s` to check again.",
+		strings.Join(items, "\n"), link, cmdCheckCLA,
+	)
+}
+
+func isNotSignedErr(err error) bool {
+	e, ok := dbmodels.IsDBError(err)
+	return ok && (e.ErrCode == util.ErrHasNotSigned || e.ErrCode == util.ErrNoCLABindingDoc)
+}
@@ -0,0 +1,384 @@
+package robot
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/memorydb"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const (
+	testPlatform = "gitee"
+	testLogin    = "cla-robot"
+)
+
+// fakeClient imitates the code platform and records what the robot did on the pull request.
+type fakeClient struct {
+	emails   []string
+	comments []prComment
+	labels   map[string]bool
+	created  []string
+	updated  map[int]string
+	signed   *bool
+}
+
+func newFakeClient(emails ...string) *fakeClient {
+	return &fakeClient{
+		emails:  emails,
+		labels:  map[string]bool{},
+		updated: map[int]string{},
+	}
+}
+
+func (this *fakeClient) getLogin() (string, error) {
+	return testLogin, nil
+}
+
+func (this *fakeClient) verifyWebhook(header http.Header, payload []byte, secret string) error {
+	return nil
+}
+
+func (this *fakeClient) parseEvent(header http.Header, payload []byte) (*prEvent, error) {
+	return nil, nil
+}
+
+func (this *fakeClient) listPRCommitEmails(e *prEvent) ([]string, error) {
+	return this.emails, nil
+}
+
+func (this *fakeClient) addPRLabel(e *prEvent, label string) error {
+	this.labels[label] = true
+	return nil
+}
+
+func (this *fakeClient) removePRLabel(e *prEvent, label string) error {
+	delete(this.labels, label)
+	return nil
+}
+
+func (this *fakeClient) listPRComments(e *prEvent) ([]prComment, error) {
+	return this.comments, nil
+}
+
+func (this *fakeClient) createPRComment(e *prEvent, comment string) error {
+	this.created = append(this.created, comment)
+	return nil
+}
+
+func (this *fakeClient) updatePRComment(e *prEvent, commentID int, comment string) error {
+	this.updated[commentID] = comment
+	return nil
+}
+
+func (this *fakeClient) setCommitStatus(e *prEvent, signed bool, targetURL string) error {
+	this.signed = &signed
+	return nil
+}
+
+func newTestRobot(cli platformClient) *robot {
+	cfg := platformConfig{Platform: testPlatform}
+	cfg.setDefault()
+
+	return &robot{
+		platform:   testPlatform,
+		cfg:        cfg,
+		signingURL: template.Must(template.New(testPlatform).Parse("https://cla/{{.Platform}}/{{.Org}}/{{.Repo}}")),
+		cli:        cli,
+	}
+}
+
+type testDB struct {
+	t  *testing.T
+	db dbmodels.IDB
+}
+
+// newTestDB registers an empty database which is used by the models.
+func newTestDB(t *testing.T) *testDB {
+	db := memorydb.NewDB()
+	dbmodels.RegisterDB(db)
+	return &testDB{t: t, db: db}
+}
+
+func (this *testDB) mustNil(err error) {
+	this.t.Helper()
+
+	if err != nil {
+		this.t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func (this *testDB) createCLA(name, applyTo string) string {
+	id, err := this.db.CreateCLA(dbmodels.CLA{
+		Name:      name,
+		Text:      "text of " + name,
+		Language:  "english",
+		Submitter: "owner",
+		ApplyTo:   applyTo,
+		Fields: []dbmodels.Field{
+			{ID: "1", Title: "name", Type: "string", Required: true},
+		},
+	})
+	this.mustNil(err)
+	return id
+}
+
+func (this *testDB) createBinding(org, applyTo string) string {
+	id, err := this.db.CreateBindingBetweenCLAAndOrg(dbmodels.CLAOrg{
+		Platform:    testPlatform,
+		OrgID:       org,
+		CLAID:       this.createCLA(org+"/"+applyTo, applyTo),
+		CLALanguage: "english",
+		ApplyTo:     applyTo,
+		OrgEmail:    "cla@" + org + ".com",
+		Enabled:     true,
+		Submitter:   "owner",
+	})
+	this.mustNil(err)
+	return id
+}
+
+func (this *testDB) signAsIndividual(claOrgID, org, email string, enabled bool) {
+	this.mustNil(this.db.SignAsIndividual(claOrgID, testPlatform, org, "", dbmodels.IndividualSigningInfo{
+		IndividualSigningBasicInfo: dbmodels.IndividualSigningBasicInfo{
+			Email:   email,
+			Name:    "name of " + email,
+			Date:    util.Date(),
+			Enabled: enabled,
+		},
+		Info: dbmodels.TypeSigningInfo{"1": email},
+	}))
+}
+
+func (this *testDB) signAsCorporation(claOrgID, org, email string) {
+	this.mustNil(this.db.SignAsCorporation(claOrgID, testPlatform, org, "", dbmodels.CorporationSigningInfo{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			AdminEmail:      email,
+			AdminName:       "admin of " + email,
+			CorporationName: util.EmailSuffix(email),
+			Date:            util.Date(),
+		},
+		Info: dbmodels.TypeSigningInfo{"1": email},
+	}))
+}
+
+// publish publishes a new version of cla which must be re-signed after the deadline.
+func (this *testDB) publish(claOrgID, applyTo string, deadline int64) {
+	_, err := this.db.PublishCLAVersion(claOrgID, dbmodels.CLAVersionPublishOption{
+		CLAID:          this.createCLA("new version of "+claOrgID, applyTo),
+		ResignRequired: true,
+		ResignDeadline: deadline,
+	})
+	this.mustNil(err)
+}
+
+// prepareSignings creates the bindings of org, the individual a@x.com,
+// the corporation corp.com and its employees.
+func prepareSignings(t *testing.T) (db *testDB, individual, corp string) {
+	db = newTestDB(t)
+
+	individual = db.createBinding("org", dbmodels.ApplyToIndividual)
+	corp = db.createBinding("org", dbmodels.ApplyToCorporation)
+
+	db.signAsIndividual(individual, "org", "a@x.com", true)
+	db.signAsCorporation(corp, "org", "admin@corp.com")
+	db.signAsIndividual(individual, "org", "active@corp.com", true)
+	db.signAsIndividual(individual, "org", "inactive@corp.com", false)
+	return
+}
+
+func TestCheckEmail(t *testing.T) {
+	cases := []struct {
+		name    string
+		email   string
+		prepare func(db *testDB, individual, corp string)
+		reason  string
+	}{
+		{
+			name:   "empty email",
+			reason: "the email of commit author is empty",
+		},
+		{
+			name:   "unsigned",
+			email:  "b@x.com",
+			reason: "has not signed",
+		},
+		{
+			name:  "signed",
+			email: "a@x.com",
+		},
+		{
+			name:  "employee",
+			email: "active@corp.com",
+		},
+		{
+			name:   "employee not activated",
+			email:  "inactive@corp.com",
+			reason: "the employee signing has not been activated by the corporation manager",
+		},
+		{
+			name:   "employee not signed",
+			email:  "other@corp.com",
+			reason: "the corporation has signed, but the employee has not signed",
+		},
+		{
+			name:  "outdated before deadline",
+			email: "a@x.com",
+			prepare: func(db *testDB, individual, corp string) {
+				db.publish(individual, dbmodels.ApplyToIndividual, util.Now()+3600)
+			},
+		},
+		{
+			name:  "outdated",
+			email: "a@x.com",
+			prepare: func(db *testDB, individual, corp string) {
+				db.publish(individual, dbmodels.ApplyToIndividual, util.Now())
+			},
+			reason: "has signed the version 1 of CLA, but the version 2 is required to sign",
+		},
+		{
+			name:  "corporation outdated",
+			email: "active@corp.com",
+			prepare: func(db *testDB, individual, corp string) {
+				db.publish(corp, dbmodels.ApplyToCorporation, util.Now())
+			},
+			reason: "the corporation has signed the version 1 of CLA, but the version 2 is required to sign",
+		},
+	}
+
+	// the cla is bound to the org
+	e := &prEvent{Org: "org", Number: 1}
+	for _, c := range cases {
+		db, individual, corp := prepareSignings(t)
+		if c.prepare != nil {
+			c.prepare(db, individual, corp)
+		}
+
+		reason, err := newTestRobot(newFakeClient()).checkEmail(e, c.email)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if reason != c.reason {
+			t.Errorf("%s: expect reason %q, but got %q", c.name, c.reason, reason)
+		}
+	}
+}
+
+func TestCheckPR(t *testing.T) {
+	prepareSignings(t)
+
+	cases := []struct {
+		name     string
+		emails   []string
+		signed   bool
+		unsigned []string
+	}{
+		{
+			name:   "all signed",
+			emails: []string{"a@x.com", "active@corp.com", "a@x.com"},
+			signed: true,
+		},
+		{
+			name:     "some unsigned",
+			emails:   []string{"a@x.com", "b@x.com", "inactive@corp.com", "b@x.com"},
+			unsigned: []string{"b@x.com", "inactive@corp.com"},
+		},
+	}
+
+	for _, c := range cases {
+		cli := newFakeClient(c.emails...)
+		if err := newTestRobot(cli).checkPR(&prEvent{Org: "org", Repo: "repo", Number: 1}); err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+
+		label := "cla/no"
+		if c.signed {
+			label = "cla/yes"
+		}
+		if !reflect.DeepEqual(cli.labels, map[string]bool{label: true}) {
+			t.Errorf("%s: unexpected labels: %v", c.name, cli.labels)
+		}
+
+		if cli.signed == nil || *cli.signed != c.signed {
+			t.Errorf("%s: expect commit status of signed to be %v", c.name, c.signed)
+		}
+
+		if len(cli.created) != 1 {
+			t.Errorf("%s: expect one comment, but got %d", c.name, len(cli.created))
+			continue
+		}
+		comment := cli.created[0]
+		if !strings.HasPrefix(comment, claCommentMark) {
+			t.Errorf("%s: the comment has no mark: %s", c.name, comment)
+		}
+		for _, email := range c.unsigned {
+			if strings.Count(comment, "**"+email+"**") != 1 {
+				t.Errorf("%s: the comment should list %s once: %s", c.name, email, comment)
+			}
+		}
+		if len(c.unsigned) > 0 && !strings.Contains(comment, "https://cla/gitee/org/repo") {
+			t.Errorf("%s: the comment has no signing link: %s", c.name, comment)
+		}
+	}
+
+	// the org which requires no cla is not touched
+	cli := newFakeClient("b@x.com")
+	if err := newTestRobot(cli).checkPR(&prEvent{Org: "other", Repo: "repo", Number: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(cli.labels) != 0 || cli.signed != nil || len(cli.created) != 0 {
+		t.Errorf("the pr of org which requires no cla should not be touched")
+	}
+}
+
+func TestComment(t *testing.T) {
+	mark := claCommentMark + "\nold"
+
+	cases := []struct {
+		name     string
+		comments []prComment
+		// updated is the id of comment which should be updated, 0 means creating one
+		updated int
+	}{
+		{
+			name: "no comment",
+		},
+		{
+			name:     "comment of robot",
+			comments: []prComment{{ID: 1, Body: "lgtm", Author: testLogin}, {ID: 2, Body: mark, Author: testLogin}},
+			updated:  2,
+		},
+		{
+			name:     "comment of others with mark",
+			comments: []prComment{{ID: 1, Body: mark, Author: "someone"}},
+		},
+	}
+
+	for _, c := range cases {
+		cli := newFakeClient()
+		cli.comments = c.comments
+
+		if err := newTestRobot(cli).comment(&prEvent{}, "new"); err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+
+		want := claCommentMark + "\nnew"
+		if c.updated == 0 {
+			if len(cli.updated) != 0 || !reflect.DeepEqual(cli.created, []string{want}) {
+				t.Errorf("%s: expect a new comment, but updated %v and created %v", c.name, cli.updated, cli.created)
+			}
+			continue
+		}
+
+		if len(cli.created) != 0 || !reflect.DeepEqual(cli.updated, map[int]string{c.updated: want}) {
+			t.Errorf("%s: expect to update comment %d, but updated %v and created %v", c.name, c.updated, cli.updated, cli.created)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package robot
+
+type robotConfigs struct {
+	Configs []platformConfig `json:"robots" required:"true"`
+}
+
+type platformConfig struct {
+	Platform string `json:"platform" required:"true"`
+
+	// AccessToken is the token of robot account used to comment on the pull request
+	AccessToken string `json:"access_token" required:"true"`
+
+	// WebhookSecret is the secret set on the webhook of code platform
+	WebhookSecret string `json:"webhook_secret" required:"true"`
+
+	// SigningURL is the template of link to the signing page. It can refer to
+	// {{.Platform}}, {{.Org}} and {{.Repo}}
+	SigningURL string `json:"signing_url" required:"true"`
+
+	LabelSigned   string `json:"label_signed,omitempty"`
+	LabelUnsigned string `json:"label_unsigned,omitempty"`
+
+	// APIEndpoint is optional and only used to access the private deployment of code platform
+	APIEndpoint string `json:"api_endpoint,omitempty"`
+}
+
+func (this *platformConfig) setDefault() {
+	if this.LabelSigned == "" {
+		this.LabelSigned = "cla/yes"
+	}
+
+	if this.LabelUnsigned == "" {
+		this.LabelUnsigned = "cla/no"
+	}
+}
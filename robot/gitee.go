@@ -0,0 +1,213 @@
+package robot
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"gitee.com/openeuler/go-gitee/gitee"
+	"github.com/antihax/optional"
+	"golang.org/x/oauth2"
+)
+
+const (
+	giteeEventPR   = "Merge Request Hook"
+	giteeEventNote = "Note Hook"
+	giteePerPage   = 100
+)
+
+type giteeRepository struct {
+	Namespace string `json:"namespace"`
+	Path      string `json:"path"`
+}
+
+type giteePullRequest struct {
+	Number int `json:"number"`
+	Head   struct {
+		Sha string `json:"sha"`
+	} `json:"head"`
+}
+
+func (this *giteePullRequest) toEvent(repo *giteeRepository) *prEvent {
+	return &prEvent{
+		Org:    repo.Namespace,
+		Repo:   repo.Path,
+		Number: this.Number,
+		SHA:    this.Head.Sha,
+	}
+}
+
+type giteeClient struct {
+	c *gitee.APIClient
+}
+
+func newGiteeClient(accessToken, endpoint string) *giteeClient {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
+
+	conf := gitee.NewConfiguration()
+	conf.HTTPClient = oauth2.NewClient(context.Background(), ts)
+	if endpoint != "" {
+		conf.BasePath = endpoint
+	}
+
+	return &giteeClient{c: gitee.NewAPIClient(conf)}
+}
+
+func (this *giteeClient) getLogin() (string, error) {
+	u, _, err := this.c.UsersApi.GetV5User(context.Background(), nil)
+	if err != nil {
+		return "", err
+	}
+	return u.Login, nil
+}
+
+func (this *giteeClient) verifyWebhook(header http.Header, payload []byte, secret string) error {
+	token := header.Get("X-Gitee-Token")
+	if token == "" {
+		return fmt.Errorf("missing X-Gitee-Token")
+	}
+
+	// the webhook is configured with password
+	if hmac.Equal([]byte(token), []byte(secret)) {
+		return nil
+	}
+
+	// the webhook is configured with signing key
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%s\n%s", header.Get("X-Gitee-Timestamp"), secret)))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(token), []byte(sign)) {
+		return fmt.Errorf("invalid X-Gitee-Token")
+	}
+	return nil
+}
+
+func (this *giteeClient) parseEvent(header http.Header, payload []byte) (*prEvent, error) {
+	switch header.Get("X-Gitee-Event") {
+	case giteeEventPR:
+		var v struct {
+			Action      string            `json:"action"`
+			PullRequest *giteePullRequest `json:"pull_request"`
+			Repository  giteeRepository   `json:"repository"`
+		}
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+
+		if v.PullRequest == nil || (v.Action != "open" && v.Action != "update") {
+			return nil, nil
+		}
+		return v.PullRequest.toEvent(&v.Repository), nil
+
+	case giteeEventNote:
+		var v struct {
+			NoteableType string `json:"noteable_type"`
+			Comment      struct {
+				Body string `json:"body"`
+			} `json:"comment"`
+			PullRequest *giteePullRequest `json:"pull_request"`
+			Repository  giteeRepository   `json:"repository"`
+		}
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+
+		if v.NoteableType != "PullRequest" || v.PullRequest == nil || !isCheckCLACmd(v.Comment.Body) {
+			return nil, nil
+		}
+		return v.PullRequest.toEvent(&v.Repository), nil
+	}
+
+	return nil, nil
+}
+
+func (this *giteeClient) listPRCommitEmails(e *prEvent) ([]string, error) {
+	commits, _, err := this.c.PullRequestsApi.GetV5ReposOwnerRepoPullsNumberCommits(
+		context.Background(), e.Org, e.Repo, int32(e.Number), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]string, 0, len(commits))
+	for _, item := range commits {
+		email := ""
+		if item.Commit != nil && item.Commit.Author != nil {
+			email = item.Commit.Author.Email
+		}
+		r = append(r, email)
+	}
+	return r, nil
+}
+
+func (this *giteeClient) addPRLabel(e *prEvent, label string) error {
+	_, _, err := this.c.PullRequestsApi.PostV5ReposOwnerRepoPullsNumberLabels(
+		context.Background(), e.Org, e.Repo, int32(e.Number),
+		gitee.PullRequestLabelPostParam{Body: []string{label}},
+	)
+	return err
+}
+
+func (this *giteeClient) removePRLabel(e *prEvent, label string) error {
+	_, err := this.c.PullRequestsApi.DeleteV5ReposOwnerRepoPullsLabel(
+		context.Background(), e.Org, e.Repo, int32(e.Number), url.PathEscape(label), nil,
+	)
+	return err
+}
+
+func (this *giteeClient) createPRComment(e *prEvent, comment string) error {
+	_, _, err := this.c.PullRequestsApi.PostV5ReposOwnerRepoPullsNumberComments(
+		context.Background(), e.Org, e.Repo, int32(e.Number),
+		gitee.PullRequestCommentPostParam{Body: comment},
+	)
+	return err
+}
+
+func (this *giteeClient) listPRComments(e *prEvent) ([]prComment, error) {
+	var r []prComment
+
+	opt := gitee.GetV5ReposOwnerRepoPullsNumberCommentsOpts{PerPage: optional.NewInt32(giteePerPage)}
+	for p := int32(1); ; p++ {
+		opt.Page = optional.NewInt32(p)
+		comments, _, err := this.c.PullRequestsApi.GetV5ReposOwnerRepoPullsNumberComments(
+			context.Background(), e.Org, e.Repo, int32(e.Number), &opt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range comments {
+			author := ""
+			if item.User != nil {
+				author = item.User.Login
+			}
+			r = append(r, prComment{ID: int(item.Id), Body: item.Body, Author: author})
+		}
+
+		if len(comments) < giteePerPage {
+			break
+		}
+	}
+
+	return r, nil
+}
+
+func (this *giteeClient) updatePRComment(e *prEvent, commentID int, comment string) error {
+	_, _, err := this.c.PullRequestsApi.PatchV5ReposOwnerRepoPullsCommentsId(
+		context.Background(), e.Org, e.Repo, int32(commentID),
+		gitee.PullRequestCommentPatchParam{Body: comment},
+	)
+	return err
+}
+
+// setCommitStatus does nothing, because gitee doesn't support commit status.
+// The label is enough.
+func (this *giteeClient) setCommitStatus(e *prEvent, signed bool, targetURL string) error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package robot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func giteeSignature(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestGiteeVerifyWebhook(t *testing.T) {
+	secret := "secret"
+	timestamp := "1600000000000"
+
+	cases := []struct {
+		name      string
+		token     string
+		timestamp string
+		valid     bool
+	}{
+		{"password", secret, "", true},
+		{"signing key", giteeSignature(secret, timestamp), timestamp, true},
+		{"missing token", "", timestamp, false},
+		{"wrong password", "other", "", false},
+		{"signed by other secret", giteeSignature("other", timestamp), timestamp, false},
+		{"other timestamp", giteeSignature(secret, timestamp), "1600000000001", false},
+	}
+
+	cli := newGiteeClient("", "")
+	for _, c := range cases {
+		h := http.Header{}
+		if c.token != "" {
+			h.Set("X-Gitee-Token", c.token)
+		}
+		h.Set("X-Gitee-Timestamp", c.timestamp)
+
+		err := cli.verifyWebhook(h, []byte("{}"), secret)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expect an error, but got nil", c.name)
+		}
+	}
+}
+
+func TestGiteeParseEvent(t *testing.T) {
+	pr := `"pull_request":{"number":1,"head":{"sha":"sha1"}},"repository":{"namespace":"org","path":"repo"}`
+	want := &prEvent{Org: "org", Repo: "repo", Number: 1, SHA: "sha1"}
+
+	cases := []struct {
+		name    string
+		event   string
+		payload string
+		want    *prEvent
+		err     bool
+	}{
+		{"pr opened", giteeEventPR, `{"action":"open",` + pr + `}`, want, false},
+		{"pr updated", giteeEventPR, `{"action":"update",` + pr + `}`, want, false},
+		{"pr closed", giteeEventPR, `{"action":"close",` + pr + `}`, nil, false},
+		{"check command", giteeEventNote, `{"noteable_type":"PullRequest","comment":{"body":"/check-cla"},` + pr + `}`, want, false},
+		{"other comment", giteeEventNote, `{"noteable_type":"PullRequest","comment":{"body":"lgtm"},` + pr + `}`, nil, false},
+		{"comment on issue", giteeEventNote, `{"noteable_type":"Issue","comment":{"body":"/check-cla"},` + pr + `}`, nil, false},
+		{"unknown event", "Push Hook", `{}`, nil, false},
+		{"invalid payload", giteeEventPR, `{`, nil, true},
+	}
+
+	cli := newGiteeClient("", "")
+	for _, c := range cases {
+		h := http.Header{}
+		h.Set("X-Gitee-Event", c.event)
+
+		e, err := cli.parseEvent(h, []byte(c.payload))
+		if (err != nil) != c.err {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(e, c.want) {
+			t.Errorf("%s: expect %+v, but got %+v", c.name, c.want, e)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package robot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/opensourceways/app-cla-server/code-platform-auth/platforms"
+)
+
+const (
+	githubPerPage   = 100
+	githubStatusCtx = "cla"
+)
+
+type githubRepository struct {
+	Name  string `json:"name"`
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+type githubClient struct {
+	api *platforms.GithubAPI
+}
+
+func newGithubClient(accessToken, endpoint string) *githubClient {
+	return &githubClient{api: platforms.NewGithubAPI(accessToken, endpoint)}
+}
+
+func (this *githubClient) getLogin() (string, error) {
+	var u struct {
+		Login string `json:"login"`
+	}
+
+	if err := this.api.Do(http.MethodGet, "/user", nil, &u); err != nil {
+		return "", err
+	}
+	return u.Login, nil
+}
+
+func (this *githubClient) verifyWebhook(header http.Header, payload []byte, secret string) error {
+	sign := header.Get("X-Hub-Signature-256")
+	if sign == "" {
+		return fmt.Errorf("missing X-Hub-Signature-256")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(sign), []byte(expected)) {
+		return fmt.Errorf("invalid X-Hub-Signature-256")
+	}
+	return nil
+}
+
+func (this *githubClient) parseEvent(header http.Header, payload []byte) (*prEvent, error) {
+	switch header.Get("X-GitHub-Event") {
+	case "pull_request":
+		var v struct {
+			Action      string `json:"action"`
+			PullRequest struct {
+				Number int `json:"number"`
+				Head   struct {
+					Sha string `json:"sha"`
+				} `json:"head"`
+			} `json:"pull_request"`
+			Repository githubRepository `json:"repository"`
+		}
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+
+		switch v.Action {
+		case "opened", "reopened", "synchronize":
+			return &prEvent{
+				Org:    v.Repository.Owner.Login,
+				Repo:   v.Repository.Name,
+				Number: v.PullRequest.Number,
+				SHA:    v.PullRequest.Head.Sha,
+			}, nil
+		}
+
+	case "issue_comment":
+		var v struct {
+			Action string `json:"action"`
+			Issue  struct {
+				Number      int       `json:"number"`
+				PullRequest *struct{} `json:"pull_request"`
+			} `json:"issue"`
+			Comment struct {
+				Body string `json:"body"`
+			} `json:"comment"`
+			Repository githubRepository `json:"repository"`
+		}
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+
+		if v.Action != "created" || v.Issue.PullRequest == nil || !isCheckCLACmd(v.Comment.Body) {
+			return nil, nil
+		}
+
+		e := &prEvent{
+			Org:    v.Repository.Owner.Login,
+			Repo:   v.Repository.Name,
+			Number: v.Issue.Number,
+		}
+
+		// the comment event doesn't include the head of pull request
+		var pr struct {
+			Head struct {
+				Sha string `json:"sha"`
+			} `json:"head"`
+		}
+		if err := this.api.Do(http.MethodGet, this.prPath(e, ""), nil, &pr); err != nil {
+			return nil, err
+		}
+		e.SHA = pr.Head.Sha
+
+		return e, nil
+	}
+
+	return nil, nil
+}
+
+func (this *githubClient) listPRCommitEmails(e *prEvent) ([]string, error) {
+	var r []string
+
+	for p := 1; ; p++ {
+		var commits []struct {
+			Commit struct {
+				Author struct {
+					Email string `json:"email"`
+				} `json:"author"`
+			} `json:"commit"`
+		}
+
+		path := this.prPath(e, fmt.Sprintf("/commits?page=%d&per_page=%d", p, githubPerPage))
+		if err := this.api.Do(http.MethodGet, path, nil, &commits); err != nil {
+			return nil, err
+		}
+
+		for _, item := range commits {
+			r = append(r, item.Commit.Author.Email)
+		}
+
+		if len(commits) < githubPerPage {
+			break
+		}
+	}
+
+	return r, nil
+}
+
+func (this *githubClient) addPRLabel(e *prEvent, label string) error {
+	body := map[string][]string{"labels": {label}}
+	return this.api.Do(http.MethodPost, this.issuePath(e, "/labels"), body, nil)
+}
+
+func (this *githubClient) removePRLabel(e *prEvent, label string) error {
+	return this.api.Do(http.MethodDelete, this.issuePath(e, "/labels/"+url.PathEscape(label)), nil, nil)
+}
+
+func (this *githubClient) createPRComment(e *prEvent, comment string) error {
+	body := map[string]string{"body": comment}
+	return this.api.Do(http.MethodPost, this.issuePath(e, "/comments"), body, nil)
+}
+
+func (this *githubClient) listPRComments(e *prEvent) ([]prComment, error) {
+	var r []prComment
+
+	for p := 1; ; p++ {
+		var comments []struct {
+			ID   int    `json:"id"`
+			Body string `json:"body"`
+			User struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		}
+
+		path := this.issuePath(e, fmt.Sprintf("/comments?page=%d&per_page=%d", p, githubPerPage))
+		if err := this.api.Do(http.MethodGet, path, nil, &comments); err != nil {
+			return nil, err
+		}
+
+		for _, item := range comments {
+			r = append(r, prComment{ID: item.ID, Body: item.Body, Author: item.User.Login})
+		}
+
+		if len(comments) < githubPerPage {
+			break
+		}
+	}
+
+	return r, nil
+}
+
+func (this *githubClient) updatePRComment(e *prEvent, commentID int, comment string) error {
+	body := map[string]string{"body": comment}
+	path := fmt.Sprintf("/repos/%s/%s/issues/comments/%d", e.Org, e.Repo, commentID)
+	return this.api.Do(http.MethodPatch, path, body, nil)
+}
+
+func (this *githubClient) setCommitStatus(e *prEvent, signed bool, targetURL string) error {
+	body := map[string]string{
+		"state":       "success",
+		"target_url":  targetURL,
+		"description": "All the authors of commits have signed the CLA",
+		"context":     githubStatusCtx,
+	}
+	if !signed {
+		body["state"] = "failure"
+		body["description"] = "Some authors of commits have not signed the CLA"
+	}
+
+	path := fmt.Sprintf("/repos/%s/%s/statuses/%s", e.Org, e.Repo, e.SHA)
+	return this.api.Do(http.MethodPost, path, body, nil)
+}
+
+func (this *githubClient) prPath(e *prEvent, sub string) string {
+	return fmt.Sprintf("/repos/%s/%s/pulls/%d%s", e.Org, e.Repo, e.Number, sub)
+}
+
+func (this *githubClient) issuePath(e *prEvent, sub string) string {
+	return fmt.Sprintf("/repos/%s/%s/issues/%d%s", e.Org, e.Repo, e.Number, sub)
+}
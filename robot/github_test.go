@@ -0,0 +1,110 @@
+package robot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func githubSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestGithubVerifyWebhook(t *testing.T) {
+	secret := "secret"
+	payload := []byte(`{"action":"opened"}`)
+
+	cases := []struct {
+		name    string
+		sign    string
+		payload []byte
+		valid   bool
+	}{
+		{"valid signature", githubSignature(secret, payload), payload, true},
+		{"missing signature", "", payload, false},
+		{"signed by other secret", githubSignature("other", payload), payload, false},
+		{"tampered payload", githubSignature(secret, payload), []byte(`{"action":"closed"}`), false},
+		{"signature without prefix", githubSignature(secret, payload)[len("sha256="):], payload, false},
+	}
+
+	cli := newGithubClient("", "")
+	for _, c := range cases {
+		h := http.Header{}
+		if c.sign != "" {
+			h.Set("X-Hub-Signature-256", c.sign)
+		}
+
+		err := cli.verifyWebhook(h, c.payload, secret)
+		if c.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("%s: expect an error, but got nil", c.name)
+		}
+	}
+}
+
+func TestGithubParseEvent(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/repo/pulls/1" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"head": map[string]string{"sha": "sha-of-comment"},
+		})
+	}))
+	defer s.Close()
+
+	pr := func(action string) string {
+		return `{"action":"` + action + `","pull_request":{"number":1,"head":{"sha":"sha1"}},` +
+			`"repository":{"name":"repo","owner":{"login":"org"}}}`
+	}
+	comment := func(body string, onPR bool) string {
+		issue := `{"number":1}`
+		if onPR {
+			issue = `{"number":1,"pull_request":{}}`
+		}
+		return `{"action":"created","issue":` + issue + `,"comment":{"body":"` + body + `"},` +
+			`"repository":{"name":"repo","owner":{"login":"org"}}}`
+	}
+
+	cases := []struct {
+		name    string
+		event   string
+		payload string
+		want    *prEvent
+		err     bool
+	}{
+		{"pr opened", "pull_request", pr("opened"), &prEvent{Org: "org", Repo: "repo", Number: 1, SHA: "sha1"}, false},
+		{"pr synchronized", "pull_request", pr("synchronize"), &prEvent{Org: "org", Repo: "repo", Number: 1, SHA: "sha1"}, false},
+		{"pr closed", "pull_request", pr("closed"), nil, false},
+		{"check command", "issue_comment", comment(`/check-cla\n`, true), &prEvent{Org: "org", Repo: "repo", Number: 1, SHA: "sha-of-comment"}, false},
+		{"other comment", "issue_comment", comment("lgtm", true), nil, false},
+		{"comment on issue", "issue_comment", comment("/check-cla", false), nil, false},
+		{"unknown event", "push", `{}`, nil, false},
+		{"invalid payload", "pull_request", `{`, nil, true},
+	}
+
+	cli := newGithubClient("", s.URL)
+	for _, c := range cases {
+		h := http.Header{}
+		h.Set("X-GitHub-Event", c.event)
+
+		e, err := cli.parseEvent(h, []byte(c.payload))
+		if (err != nil) != c.err {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(e, c.want) {
+			t.Errorf("%s: expect %+v, but got %+v", c.name, c.want, e)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package robot
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"text/template"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var robots = map[string]IRobot{}
+
+type IRobot interface {
+	// VerifyWebhook checks whether the event is sent by the webhook of code platform
+	VerifyWebhook(header http.Header, payload []byte) error
+
+	// HandleEvent checks the cla signing of pull request if it is necessary
+	HandleEvent(header http.Header, payload []byte) error
+}
+
+type prEvent struct {
+	Org    string
+	Repo   string
+	Number int
+	SHA    string
+}
+
+type prComment struct {
+	ID     int
+	Body   string
+	Author string
+}
+
+type platformClient interface {
+	// getLogin returns the login of robot account
+	getLogin() (string, error)
+	verifyWebhook(header http.Header, payload []byte, secret string) error
+	// parseEvent returns nil if the event needs not to be handled
+	parseEvent(header http.Header, payload []byte) (*prEvent, error)
+	listPRCommitEmails(e *prEvent) ([]string, error)
+	addPRLabel(e *prEvent, label string) error
+	removePRLabel(e *prEvent, label string) error
+	listPRComments(e *prEvent) ([]prComment, error)
+	createPRComment(e *prEvent, comment string) error
+	updatePRComment(e *prEvent, commentID int, comment string) error
+	setCommitStatus(e *prEvent, signed bool, targetURL string) error
+}
+
+type robot struct {
+	platform   string
+	cfg        platformConfig
+	signingURL *template.Template
+	cli        platformClient
+
+	// login is the login of robot account which is fetched only once
+	login string
+	lock  sync.Mutex
+}
+
+func RegisterRobot(configFile string) error {
+	cfg := robotConfigs{}
+	if err := util.LoadFromYaml(configFile, &cfg); err != nil {
+		return err
+	}
+
+	for _, item := range cfg.Configs {
+		item.setDefault()
+
+		tmpl, err := template.New(item.Platform).Parse(item.SigningURL)
+		if err != nil {
+			return fmt.Errorf("Failed to register robot of %s: parse signing url failed: %s", item.Platform, err.Error())
+		}
+
+		cli, err := newPlatformClient(item)
+		if err != nil {
+			return fmt.Errorf("Failed to register robot of %s: %s", item.Platform, err.Error())
+		}
+
+		robots[item.Platform] = &robot{
+			platform:   item.Platform,
+			cfg:        item,
+			signingURL: tmpl,
+			cli:        cli,
+		}
+	}
+	return nil
+}
+
+func GetRobot(platform string) (IRobot, error) {
+	r, ok := robots[platform]
+	if !ok {
+		return nil, fmt.Errorf("Failed to get robot: unknown platform: %s", platform)
+	}
+	return r, nil
+}
+
+func newPlatformClient(cfg platformConfig) (platformClient, error) {
+	switch cfg.Platform {
+	case "gitee":
+		return newGiteeClient(cfg.AccessToken, cfg.APIEndpoint), nil
+	case "github":
+		return newGithubClient(cfg.AccessToken, cfg.APIEndpoint), nil
+	}
+	return nil, fmt.Errorf("unknown platform:%s", cfg.Platform)
+}
+
+func (this *robot) VerifyWebhook(header http.Header, payload []byte) error {
+	return this.cli.verifyWebhook(header, payload, this.cfg.WebhookSecret)
+}
+
+func (this *robot) HandleEvent(header http.Header, payload []byte) error {
+	e, err := this.cli.parseEvent(header, payload)
+	if err != nil || e == nil {
+		return err
+	}
+
+	return this.checkPR(e)
+}
+
+func (this *robot) getLogin() (string, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.login == "" {
+		v, err := this.cli.getLogin()
+		if err != nil {
+			return "", err
+		}
+		this.login = v
+	}
+	return this.login, nil
+}
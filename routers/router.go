@@ -11,17 +11,16 @@ import (
 	"github.com/astaxie/beego"
 
 	"github.com/opensourceways/app-cla-server/controllers"
+	"github.com/opensourceways/app-cla-server/metrics"
 )
 
 func init() {
 	ns := beego.NewNamespace("/v1",
-		/*
-			beego.NSNamespace("/cla",
-				beego.NSInclude(
-					&controllers.CLAController{},
-				),
+		beego.NSNamespace("/cla",
+			beego.NSInclude(
+				&controllers.CLAController{},
 			),
-		*/
+		),
 		beego.NSNamespace("/cla-org",
 			beego.NSInclude(
 				&controllers.CLAOrgController{},
@@ -32,45 +31,70 @@ func init() {
 				&controllers.IndividualSigningController{},
 			),
 		),
-		/*
-			beego.NSNamespace("/employee-signing",
-				beego.NSInclude(
-					&controllers.EmployeeSigningController{},
-				),
+		beego.NSNamespace("/employee-signing",
+			beego.NSInclude(
+				&controllers.EmployeeSigningController{},
 			),
-			beego.NSNamespace("/corporation-signing",
-				beego.NSInclude(
-					&controllers.CorporationSigningController{},
-				),
+		),
+		beego.NSNamespace("/corporation-signing",
+			beego.NSInclude(
+				&controllers.CorporationSigningController{},
 			),
-			beego.NSNamespace("/corporation-manager",
-				beego.NSInclude(
-					&controllers.CorporationManagerController{},
-				),
+		),
+		beego.NSNamespace("/corporation-domain",
+			beego.NSInclude(
+				&controllers.CorporationDomainController{},
 			),
-			beego.NSNamespace("/employee-manager",
-				beego.NSInclude(
-					&controllers.EmployeeManagerController{},
-				),
+		),
+		beego.NSNamespace("/corporation-manager",
+			beego.NSInclude(
+				&controllers.CorporationManagerController{},
 			),
-			beego.NSNamespace("/email",
-				beego.NSInclude(
-					&controllers.EmailController{},
-				),
+		),
+		beego.NSNamespace("/employee-manager",
+			beego.NSInclude(
+				&controllers.EmployeeManagerController{},
 			),
-		*/
+		),
+		beego.NSNamespace("/email",
+			beego.NSInclude(
+				&controllers.EmailController{},
+			),
+		),
+		beego.NSNamespace("/robot",
+			beego.NSInclude(
+				&controllers.RobotController{},
+			),
+		),
 		beego.NSNamespace("/auth",
 			beego.NSInclude(
 				&controllers.AuthController{},
 			),
 		),
-		/*
-			beego.NSNamespace("/org-signature",
-				beego.NSInclude(
-					&controllers.OrgSignatureController{},
-				),
+		beego.NSNamespace("/org-signature",
+			beego.NSInclude(
+				&controllers.OrgSignatureController{},
+			),
+		),
+		beego.NSNamespace("/signing-export",
+			beego.NSInclude(
+				&controllers.SigningExportController{},
+			),
+		),
+		beego.NSNamespace("/audit-log",
+			beego.NSInclude(
+				&controllers.AuditLogController{},
 			),
-		*/
+		),
 	)
 	beego.AddNamespace(ns)
+
+	beego.Handler("/metrics", metrics.Handler())
+
+	// it must be inserted before the permission filter, so that the route is
+	// saved even if the request is rejected.
+	beego.InsertFilter("/*", beego.BeforeExec, metrics.SaveRoute, false)
+
+	// every api is checked by the permission table before it runs.
+	beego.InsertFilter("/v1/*", beego.BeforeExec, controllers.CheckAPIPermission, true, true)
 }
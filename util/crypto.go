@@ -0,0 +1,54 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// DeriveKey derives the 32 bytes key of AES-256 from the secret.
+func DeriveKey(secret string) []byte {
+	v := sha256.Sum256([]byte(secret))
+	return v[:]
+}
+
+// Encrypt encrypts the plaintext by AES-GCM. The nonce is prepended to the ciphertext.
+// The additional data is authenticated but not encrypted, and must be same when decrypting.
+func Encrypt(plaintext, key, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts the ciphertext generated by Encrypt.
+func Decrypt(ciphertext, key, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	n := gcm.NonceSize()
+	if len(ciphertext) < n {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+
+	return gcm.Open(nil, ciphertext[:n], ciphertext[n:], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	key := DeriveKey("secret")
+	ad := []byte("additional data")
+	plaintext := []byte("plaintext")
+
+	ciphertext, err := Encrypt(plaintext, key, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Error("the ciphertext includes the plaintext")
+	}
+
+	b, err := Decrypt(ciphertext, key, ad)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if !bytes.Equal(b, plaintext) {
+		t.Errorf("expect %q, but got %q", plaintext, b)
+	}
+
+	// the nonce is random
+	if ciphertext1, err := Encrypt(plaintext, key, ad); err != nil || bytes.Equal(ciphertext1, ciphertext) {
+		t.Errorf("expect the ciphertexts of same plaintext to be different: %v", err)
+	}
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+
+	cases := []struct {
+		name       string
+		ciphertext []byte
+		key        []byte
+		ad         []byte
+	}{
+		{"wrong key", ciphertext, DeriveKey("other"), ad},
+		{"wrong additional data", ciphertext, key, []byte("other")},
+		{"no additional data", ciphertext, key, nil},
+		{"tampered ciphertext", tampered, key, ad},
+		{"short ciphertext", ciphertext[:4], key, ad},
+		{"invalid key", ciphertext, key[:5], ad},
+	}
+	for _, c := range cases {
+		if _, err := Decrypt(c.ciphertext, c.key, c.ad); err == nil {
+			t.Errorf("%s: expect an error, but got nil", c.name)
+		}
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	key := DeriveKey("secret")
+	if len(key) != 32 {
+		t.Errorf("expect the key of 32 bytes, but got %d", len(key))
+	}
+	if !bytes.Equal(key, DeriveKey("secret")) {
+		t.Error("expect the same key from the same secret")
+	}
+	if bytes.Equal(key, DeriveKey("other")) {
+		t.Error("expect different keys from different secrets")
+	}
+}
@@ -8,6 +8,9 @@ const (
 	ErrMissingToken              = "missing_token"
 	ErrUnknownToken              = "unknown_token"
 	ErrInvalidToken              = "invalid_token"
+	ErrInvalidRefreshToken       = "invalid_refresh_token"
+	ErrSessionExpired            = "expired_session"
+	ErrInvalidOAuthState         = "invalid_oauth_state"
 	ErrSigningUncompleted        = "uncompleted_signing"
 	ErrUnknownEmailPlatform      = "unknown_email_platform"
 	ErrSendingEmail              = "failed_to_send_email"
@@ -21,5 +24,9 @@ const (
 	ErrNoOrgEmail                = "no_org_email"
 	ErrNotReadyToSign            = "not_ready_to_sign"
 	ErrNotSupportedPlatform      = "not_supported_platform"
+	ErrInvalidSigningStatus      = "invalid_signing_status"
+	ErrCorpDomainExists          = "corp_domain_exists"
+	ErrNoPendingCorpDomain       = "no_pending_corp_domain"
 	ErrSystemError               = "system_error"
+	ErrEmailJobLeaseLost         = "email_job_lease_lost"
 )
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"crypto/subtle"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword returns the salted hash of password which can be stored safely.
+func HashPassword(pw string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// IsPasswordHashed checks whether the stored password has been hashed.
+// The passwords were stored as plaintext by the early versions.
+func IsPasswordHashed(stored string) bool {
+	_, err := bcrypt.Cost([]byte(stored))
+	return err == nil
+}
+
+// CheckPassword checks whether the password matches the stored one
+// no matter it is hashed or not.
+func CheckPassword(stored, pw string) bool {
+	if !IsPasswordHashed(stored) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(pw)) == 1
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(stored), []byte(pw)) == nil
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	hashed, err := HashPassword("pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hashed == "pw" || strings.Contains(hashed, "pw") {
+		t.Errorf("the password is stored as plaintext: %s", hashed)
+	}
+	if !IsPasswordHashed(hashed) {
+		t.Errorf("expect %s to be hashed", hashed)
+	}
+
+	// the hash is salted
+	if hashed1, err := HashPassword("pw"); err != nil || hashed1 == hashed {
+		t.Errorf("expect the hashes of same password to be different: %s, %v", hashed1, err)
+	}
+
+	if !CheckPassword(hashed, "pw") {
+		t.Error("expect the password to match its hash")
+	}
+	for _, pw := range []string{"", "pw1", "PW", hashed} {
+		if CheckPassword(hashed, pw) {
+			t.Errorf("the wrong password %q matches the hash", pw)
+		}
+	}
+}
+
+func TestCheckLegacyPassword(t *testing.T) {
+	// the early versions stored the plaintext password
+	stored := "pw"
+
+	if IsPasswordHashed(stored) {
+		t.Fatal("the plaintext password is treated as hashed")
+	}
+
+	if !CheckPassword(stored, "pw") {
+		t.Error("expect the password to match the plaintext one")
+	}
+	for _, pw := range []string{"", "pw1", "p"} {
+		if CheckPassword(stored, pw) {
+			t.Errorf("the wrong password %q matches the plaintext one", pw)
+		}
+	}
+
+	// it is hashed after the first login
+	hashed, err := HashPassword(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsPasswordHashed(hashed) || !CheckPassword(hashed, "pw") {
+		t.Error("expect the migrated password to be hashed and match the password")
+	}
+}
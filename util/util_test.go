@@ -0,0 +1,30 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRandStr(t *testing.T) {
+	cases := map[string]string{
+		"alphanum": "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+		"alpha":    "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+		"number":   "0123456789",
+	}
+
+	for randType, dictionary := range cases {
+		s := RandStr(32, randType)
+		if len(s) != 32 {
+			t.Errorf("%s: expect 32 characters, but got %d", randType, len(s))
+		}
+		for _, c := range s {
+			if !strings.ContainsRune(dictionary, c) {
+				t.Errorf("%s: unexpected character %q in %s", randType, c, s)
+			}
+		}
+
+		if RandStr(32, randType) == s {
+			t.Errorf("%s: expect the random strings to be different", randType)
+		}
+	}
+}
@@ -1,142 +1,253 @@
 package worker
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/astaxie/beego"
 
+	"github.com/opensourceways/app-cla-server/dbmodels"
 	"github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/metrics"
 	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/pdf"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
+const (
+	jobKindSimpleMessage  = "simple-message"
+	jobKindCorporationPDF = "corporation-pdf"
+
+	// jobLease is the seconds a job is held by a worker. The job will be
+	// picked up again after the lease if the worker fails to finish it,
+	// for example the server is restarted.
+	jobLease = 600
+
+	backoffBase = 30
+	backoffMax  = 3600
+
+	pollInterval = 10 * time.Second
+)
+
 var worker IEmailWorker
 
 type IEmailWorker interface {
 	GenCLAPDFForCorporationAndSendIt(claOrg *models.CLAOrg, signing *models.CorporationSigning, cla *models.CLA)
 	SendSimpleMessage(orgEmail string, msg *email.EmailMessage)
+	Shutdown()
 }
 
 func GetEmailWorker() IEmailWorker {
 	return worker
 }
 
-func InitEmailWorker(g pdf.IPDFGenerator) {
-	worker = &emailWorker{pdfGenerator: g}
+func InitEmailWorker(g pdf.IPDFGenerator, workerNum, maxAttempts int) {
+	w := &emailWorker{
+		pdfGenerator: g,
+		maxAttempts:  maxAttempts,
+		notify:       make(chan struct{}, workerNum),
+		stop:         make(chan struct{}),
+	}
+
+	for i := 0; i < workerNum; i++ {
+		w.wg.Add(1)
+		go w.run()
+	}
+
+	worker = w
+}
+
+type corporationPDFJob struct {
+	CLAOrg  *models.CLAOrg             `json:"cla_org"`
+	Signing *models.CorporationSigning `json:"signing"`
+	CLA     *models.CLA                `json:"cla"`
 }
 
 type emailWorker struct {
 	pdfGenerator pdf.IPDFGenerator
-	wg           sync.WaitGroup
-	shutdown     bool
+	maxAttempts  int
+
+	notify chan struct{}
+	stop   chan struct{}
+	wg     sync.WaitGroup
 }
 
+// Shutdown waits for the jobs being handled. The remaining ones are kept
+// in the outbox and will be handled after restart.
 func (this *emailWorker) Shutdown() {
-	this.shutdown = true
+	close(this.stop)
 
-	// Handle remaining requests
 	this.wg.Wait()
 }
 
 func (this *emailWorker) GenCLAPDFForCorporationAndSendIt(claOrg *models.CLAOrg, signing *models.CorporationSigning, cla *models.CLA) {
-	f := func() {
-		defer func() {
-			this.wg.Done()
-		}()
+	job := corporationPDFJob{
+		CLAOrg:  claOrg,
+		Signing: signing,
+		CLA:     cla,
+	}
+	this.addJob(jobKindCorporationPDF, claOrg.OrgEmail, job)
+}
 
-		emailCfg, ec, err := getEmailClient(claOrg.OrgEmail)
-		if err != nil {
+func (this *emailWorker) SendSimpleMessage(orgEmail string, msg *email.EmailMessage) {
+	this.addJob(jobKindSimpleMessage, orgEmail, msg)
+}
+
+func (this *emailWorker) addJob(kind, orgEmail string, payload interface{}) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		beego.Error(fmt.Sprintf("failed to marshal email job: %s", err.Error()))
+		return
+	}
+
+	job := dbmodels.EmailJob{
+		Kind:        kind,
+		OrgEmail:    orgEmail,
+		Payload:     b,
+		MaxAttempts: this.maxAttempts,
+		NextRunAt:   util.Now(),
+	}
+	if _, err := dbmodels.GetDB().AddEmailJob(job); err != nil {
+		beego.Error(fmt.Sprintf("failed to add email job: %s", err.Error()))
+		return
+	}
+
+	select {
+	case this.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (this *emailWorker) run() {
+	defer this.wg.Done()
+
+	for {
+		select {
+		case <-this.stop:
+			beego.Info("email worker exits")
 			return
+		default:
 		}
 
-		file := ""
-		for {
-			if this.shutdown {
-				beego.Info("email worker exits forcedly")
-				break
-			}
+		job, err := dbmodels.GetDB().ClaimEmailJob(util.Now(), jobLease)
+		if err != nil {
+			beego.Error(fmt.Sprintf("failed to claim email job: %s", err.Error()))
+		}
 
-			if file == "" || util.IsFileNotExist(file) {
-				file1, err := this.pdfGenerator.GenCLAPDFForCorporation(claOrg, signing, cla)
-				if err != nil {
-					next(err)
-					continue
-				}
-				file = file1
+		if job == nil {
+			select {
+			case <-this.stop:
+			case <-this.notify:
+			case <-time.After(pollInterval):
 			}
+			continue
+		}
 
-			data := email.CorporationSigning{}
-			msg, err := data.GenEmailMsg()
-			if err != nil {
-				next(err)
-				continue
-			}
-			msg.To = []string{signing.AdminEmail}
-			msg.Attachment = file
+		this.handleJob(job)
+	}
+}
 
-			if err := ec.SendEmail(emailCfg.Token, msg); err != nil {
-				next(err)
-				continue
-			}
+func (this *emailWorker) handleJob(job *dbmodels.EmailJob) {
+	var err error
+	switch job.Kind {
+	case jobKindSimpleMessage:
+		err = this.sendSimpleMessage(job)
+	case jobKindCorporationPDF:
+		err = this.sendCorporationPDF(job)
+	default:
+		err = fmt.Errorf("unknown kind of email job: %s", job.Kind)
+	}
 
-			os.Remove(file)
-			break
-		}
+	db := dbmodels.GetDB()
+
+	if err == nil {
+		err = db.FinishEmailJob(job)
+	} else if job.Attempts >= job.MaxAttempts {
+		beego.Error(fmt.Sprintf("email job(%s) is dead: %s", job.ID, err.Error()))
+		err = db.KillEmailJob(job, err.Error())
+	} else {
+		beego.Info(fmt.Sprintf("email job(%s) failed and will retry: %s", job.ID, err.Error()))
+		err = db.RetryEmailJob(job, util.Now()+backoff(job.Attempts), err.Error())
 	}
 
-	this.wg.Add(1)
-	go f()
+	if err != nil {
+		beego.Error(fmt.Sprintf("failed to update email job(%s): %s", job.ID, err.Error()))
+	}
 }
 
-func (this *emailWorker) SendSimpleMessage(orgEmail string, msg *email.EmailMessage) {
-	f := func() {
-		defer func() {
-			this.wg.Done()
-		}()
+func (this *emailWorker) sendSimpleMessage(job *dbmodels.EmailJob) error {
+	var msg email.EmailMessage
+	if err := json.Unmarshal(job.Payload, &msg); err != nil {
+		return err
+	}
 
-		emailCfg, ec, err := getEmailClient(orgEmail)
-		if err != nil {
-			return
-		}
+	emailCfg, ec, err := getEmailClient(job.OrgEmail)
+	if err != nil {
+		return err
+	}
 
-		for {
-			if this.shutdown {
-				beego.Info("email worker exits forcedly")
-				break
-			}
+	return sendEmail(emailCfg, ec, &msg)
+}
 
-			if err := ec.SendEmail(emailCfg.Token, msg); err != nil {
-				next(err)
-				continue
-			}
+func (this *emailWorker) sendCorporationPDF(job *dbmodels.EmailJob) error {
+	var v corporationPDFJob
+	if err := json.Unmarshal(job.Payload, &v); err != nil {
+		return err
+	}
 
-			break
-		}
+	emailCfg, ec, err := getEmailClient(job.OrgEmail)
+	if err != nil {
+		return err
+	}
+
+	file, err := this.pdfGenerator.GenCLAPDFForCorporation(v.CLAOrg, v.Signing, v.CLA)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file)
+
+	data := email.CorporationSigning{}
+	msg, err := data.GenEmailMsg()
+	if err != nil {
+		return err
 	}
+	msg.To = []string{v.Signing.AdminEmail}
+	msg.Attachment = file
 
-	this.wg.Add(1)
-	go f()
+	return sendEmail(emailCfg, ec, msg)
 }
 
-func next(err error) {
-	beego.Info(err)
-	time.Sleep(time.Minute * time.Duration(1))
+// backoff returns the seconds to wait before the next attempt, which doubles
+// on each failure and is at most backoffMax.
+func backoff(attempts int) int64 {
+	d := int64(backoffBase)
+	for i := 1; i < attempts && d < backoffMax; i++ {
+		d *= 2
+	}
+
+	if d > backoffMax {
+		return backoffMax
+	}
+	return d
+}
 
+func sendEmail(emailCfg *models.OrgEmail, ec email.IEmail, msg *email.EmailMessage) error {
+	err := ec.SendEmail(emailCfg.Token, msg)
+	metrics.IncEmailSending(emailCfg.Platform, err)
+	return err
 }
 
 func getEmailClient(orgEmail string) (*models.OrgEmail, email.IEmail, error) {
 	emailCfg := &models.OrgEmail{Email: orgEmail}
 	if err := emailCfg.Get(); err != nil {
-		beego.Info(err)
 		return nil, nil, err
 	}
 
 	ec, err := email.GetEmailClient(emailCfg.Platform)
 	if err != nil {
-		beego.Info(err)
 		return nil, nil, err
 	}
 